@@ -1,11 +1,18 @@
 // Init collections
+db.createCollection("board_column_changes");
 db.createCollection("board_templates");
 db.createCollection("boards");
 db.createCollection("categories");
+db.createCollection("custom_fields");
+db.createCollection("issue_changes");
+db.createCollection("issue_imports");
+db.createCollection("issue_schedules");
 db.createCollection("issue_statuses");
 db.createCollection("issue_types");
+db.createCollection("issue_worklogs");
 db.createCollection("issues");
 db.createCollection("labels");
+db.createCollection("notifications");
 db.createCollection("priority_types");
 db.createCollection("project_counters");
 db.createCollection("project_types");
@@ -113,32 +120,43 @@ const issueTypes = [
     _id: "BUG",
     name: "Bug",
     description: "A problem or error.",
+    isSubTask: false,
     isDefault: false,
   },
   {
     _id: "EPIC",
     name: "Epic",
     description: "A big user story that needs to be broken down.",
+    isSubTask: false,
     isDefault: false,
   },
   {
     _id: "STORY",
     name: "Story",
     description: "Functionality or a feature expressed as a user goal.",
+    isSubTask: false,
     isDefault: false,
   },
   {
     _id: "TASK",
     name: "Task",
     description: "A small, distinct piece of work.",
+    isSubTask: false,
     isDefault: true,
   },
+  {
+    _id: "SUB_TASK",
+    name: "Sub-task",
+    description: "A piece of work that is part of a larger task.",
+    isSubTask: true,
+    isDefault: false,
+  },
 ];
 const issueTypesUpdate = issueTypes.map(
-  ({ _id, name, description, isDefault }) => ({
+  ({ _id, name, description, isSubTask, isDefault }) => ({
     updateOne: {
       filter: { _id },
-      update: { $set: { _id, name, description, isDefault } },
+      update: { $set: { _id, name, description, isSubTask, isDefault } },
       upsert: true,
     },
   })
@@ -247,6 +265,7 @@ const workflowsUpdate = workflows.map(({ _id, name, isLocked, steps }) => {
             statusIds,
           })),
         },
+        $setOnInsert: { transitions: [] },
       },
       upsert: true,
     },
@@ -3,6 +3,7 @@ package main
 import (
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/gorilla/handlers"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/njehyde/issue-tracker/pkg/http/rest"
 	"github.com/njehyde/issue-tracker/pkg/http/ws"
 	"github.com/njehyde/issue-tracker/pkg/listing"
+	"github.com/njehyde/issue-tracker/pkg/reporting"
 	"github.com/njehyde/issue-tracker/pkg/storage/mongo"
 	"github.com/njehyde/issue-tracker/pkg/updating"
 )
@@ -37,19 +39,35 @@ func main() {
 		slog.Panicf(err.Error())
 	}
 
+	// Rank issues saved before rank keys, then rebalance ranks as they grow
+	err = s.MigrateIssueRanks()
+	if err != nil {
+		slog.Panicf(err.Error())
+	}
+	go s.RebalanceIssueRanks()
+
 	var eb = events.NewEventBus()
 
 	hub := ws.NewHub()
 	go hub.Run()
 
+	// Record, and announce, SLA breaches and overdue issues as they happen
+	updater := updating.NewService(s, hub)
+	go updating.WatchIssueBreaches(updater, time.Minute)
+
+	// Create the issues of recurring issue schedules as their runs come due
+	adder := adding.NewService(s, hub)
+	go adding.WatchIssueSchedules(adder, time.Minute)
+
 	// Setup the router
 	router := rest.Handler(
 		authenticating.NewService(s),
 		listing.NewService(s),
-		adding.NewService(s, hub),
-		updating.NewService(s, hub),
+		adder,
+		updater,
 		deleting.NewService(s, hub),
 		checking.NewService(s),
+		reporting.NewService(s),
 		hub,
 		eb,
 	)
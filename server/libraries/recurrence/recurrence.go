@@ -0,0 +1,38 @@
+package recurrence
+
+import (
+	"strings"
+	"time"
+)
+
+// Rule finds the occurrences of a recurrence rule
+type Rule interface {
+	// Next returns the first occurrence after a time, or false when there are no more occurrences
+	Next(time.Time) (time.Time, bool)
+}
+
+// Parse parses a cron expression, or an RRULE, into a rule whose occurrences are never before start. Occurrences are in
+// the location of start.
+func Parse(rule string, start time.Time) (Rule, error) {
+	rule = strings.TrimSpace(rule)
+
+	upper := strings.ToUpper(rule)
+	if strings.HasPrefix(upper, "RRULE:") || strings.HasPrefix(upper, "FREQ=") {
+		return parseRRule(strings.TrimPrefix(upper, "RRULE:"), start)
+	}
+
+	return parseCron(rule, start)
+}
+
+// Between returns the occurrences of a rule after from, and up to and including to, stopping at limit occurrences.
+func Between(r Rule, from time.Time, to time.Time, limit int) []time.Time {
+	results := []time.Time{}
+
+	t, ok := r.Next(from)
+	for ok && !t.After(to) && len(results) < limit {
+		results = append(results, t)
+		t, ok = r.Next(t)
+	}
+
+	return results
+}
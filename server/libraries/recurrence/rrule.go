@@ -0,0 +1,257 @@
+package recurrence
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rruleMaxPeriods is how many periods of an RRULE are searched for an occurrence, so that rules whose parts can never
+// match do not search forever.
+const rruleMaxPeriods = 100000
+
+var rruleWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// rrule is the subset of an RFC 5545 recurrence rule that schedules need: a daily, weekly, monthly or yearly frequency,
+// its interval, the days, hours and minutes it occurs on, and when it ends.
+type rrule struct {
+	freq       string
+	interval   int
+	byDay      []time.Weekday
+	byMonthDay []int
+	byHour     []int
+	byMinute   []int
+	count      int
+	until      *time.Time
+	start      time.Time
+}
+
+func parseRRule(rule string, start time.Time) (*rrule, error) {
+	r := rrule{interval: 1, start: start}
+
+	for _, part := range strings.Split(rule, ";") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("Invalid RRULE part %q", part)
+		}
+
+		var err error
+		switch kv[0] {
+		case "FREQ":
+			r.freq = kv[1]
+		case "INTERVAL":
+			r.interval, err = strconv.Atoi(kv[1])
+			if err == nil && r.interval < 1 {
+				err = fmt.Errorf("RRULE INTERVAL must be greater than zero")
+			}
+		case "COUNT":
+			r.count, err = strconv.Atoi(kv[1])
+			if err == nil && r.count < 1 {
+				err = fmt.Errorf("RRULE COUNT must be greater than zero")
+			}
+		case "UNTIL":
+			r.until, err = parseRRuleTime(kv[1], start.Location())
+		case "BYDAY":
+			for _, d := range strings.Split(kv[1], ",") {
+				weekday, ok := rruleWeekdays[d]
+				if !ok {
+					return nil, fmt.Errorf("Invalid RRULE BYDAY %q", d)
+				}
+				r.byDay = append(r.byDay, weekday)
+			}
+		case "BYMONTHDAY":
+			r.byMonthDay, err = parseRRuleInts(kv[1], -31, 31)
+		case "BYHOUR":
+			r.byHour, err = parseRRuleInts(kv[1], 0, 23)
+		case "BYMINUTE":
+			r.byMinute, err = parseRRuleInts(kv[1], 0, 59)
+		default:
+			return nil, fmt.Errorf("RRULE part %v is not supported", kv[0])
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch r.freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	case "":
+		return nil, fmt.Errorf("RRULE FREQ is empty")
+	default:
+		return nil, fmt.Errorf("RRULE FREQ %v is not supported", r.freq)
+	}
+
+	if r.count > 0 && r.until != nil {
+		return nil, fmt.Errorf("RRULE cannot have both COUNT and UNTIL")
+	}
+
+	// Yearly rules only recur on the date they start on, and weeks have no month days
+	if r.freq == "YEARLY" && len(r.byDay) > 0 {
+		return nil, fmt.Errorf("RRULE BYDAY is not supported with FREQ=YEARLY")
+	}
+	if (r.freq == "YEARLY" || r.freq == "WEEKLY") && len(r.byMonthDay) > 0 {
+		return nil, fmt.Errorf("RRULE BYMONTHDAY is not supported with FREQ=%v", r.freq)
+	}
+
+	// Parts that are not given are taken from the start
+	if len(r.byHour) == 0 {
+		r.byHour = []int{start.Hour()}
+	}
+	if len(r.byMinute) == 0 {
+		r.byMinute = []int{start.Minute()}
+	}
+	if r.freq == "WEEKLY" && len(r.byDay) == 0 {
+		r.byDay = []time.Weekday{start.Weekday()}
+	}
+	if r.freq == "MONTHLY" && len(r.byMonthDay) == 0 && len(r.byDay) == 0 {
+		r.byMonthDay = []int{start.Day()}
+	}
+
+	return &r, nil
+}
+
+func parseRRuleInts(value string, min int, max int) ([]int, error) {
+	results := []int{}
+
+	for _, s := range strings.Split(value, ",") {
+		v, err := strconv.Atoi(s)
+		if err != nil || v < min || v > max || v == 0 && min < 0 {
+			return nil, fmt.Errorf("Invalid RRULE value %q", s)
+		}
+		results = append(results, v)
+	}
+
+	sort.Ints(results)
+
+	return results, nil
+}
+
+func parseRRuleTime(value string, loc *time.Location) (*time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		var t time.Time
+		var err error
+		if strings.HasSuffix(layout, "Z") {
+			t, err = time.Parse(layout, value)
+		} else {
+			t, err = time.ParseInLocation(layout, value, loc)
+		}
+		if err == nil {
+			return &t, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Invalid RRULE UNTIL %q", value)
+}
+
+func (r *rrule) Next(after time.Time) (time.Time, bool) {
+	n := 0
+
+	for period := 0; period < rruleMaxPeriods; period++ {
+		for _, t := range r.occurrences(period) {
+			if t.Before(r.start) {
+				continue
+			}
+			if r.until != nil && t.After(*r.until) {
+				return time.Time{}, false
+			}
+			n++
+			if r.count > 0 && n > r.count {
+				return time.Time{}, false
+			}
+			if t.After(after) {
+				return t, true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// occurrences returns the occurrences of a period of the rule, in order.
+func (r *rrule) occurrences(period int) []time.Time {
+	loc := r.start.Location()
+	y, m, d := r.start.Date()
+	step := period * r.interval
+
+	days := []time.Time{}
+	switch r.freq {
+	case "DAILY":
+		day := time.Date(y, m, d+step, 0, 0, 0, 0, loc)
+		last := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, loc).Day()
+		if r.matchesDay(day, last) {
+			days = append(days, day)
+		}
+	case "WEEKLY":
+		// Weeks start on Monday
+		monday := d - (int(r.start.Weekday())+6)%7 + step*7
+		for offset := 0; offset < 7; offset++ {
+			day := time.Date(y, m, monday+offset, 0, 0, 0, 0, loc)
+			if containsWeekday(r.byDay, day.Weekday()) {
+				days = append(days, day)
+			}
+		}
+	case "MONTHLY":
+		// Month days and weekdays both narrow down the days of the month, as in RFC 5545
+		first := time.Date(y, m+time.Month(step), 1, 0, 0, 0, 0, loc)
+		last := first.AddDate(0, 1, -1).Day()
+		for md := 1; md <= last; md++ {
+			day := time.Date(first.Year(), first.Month(), md, 0, 0, 0, 0, loc)
+			if r.matchesDay(day, last) {
+				days = append(days, day)
+			}
+		}
+	case "YEARLY":
+		day := time.Date(y+step, m, d, 0, 0, 0, 0, loc)
+		if day.Day() == d {
+			days = append(days, day)
+		}
+	}
+
+	results := []time.Time{}
+	for _, day := range days {
+		for _, h := range r.byHour {
+			for _, min := range r.byMinute {
+				results = append(results, time.Date(day.Year(), day.Month(), day.Day(), h, min, 0, 0, loc))
+			}
+		}
+	}
+
+	return results
+}
+
+// matchesDay returns whether a day of a month with the given last day is one of the rule's month days and weekdays,
+// where it has any.
+func (r *rrule) matchesDay(day time.Time, last int) bool {
+	if len(r.byDay) > 0 && !containsWeekday(r.byDay, day.Weekday()) {
+		return false
+	}
+	if len(r.byMonthDay) == 0 {
+		return true
+	}
+
+	for _, md := range r.byMonthDay {
+		if md < 0 {
+			md = last + md + 1
+		}
+		if md == day.Day() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsWeekday(weekdays []time.Weekday, w time.Weekday) bool {
+	for _, v := range weekdays {
+		if v == w {
+			return true
+		}
+	}
+
+	return false
+}
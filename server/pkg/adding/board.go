@@ -1,6 +1,113 @@
 package adding
 
-// Board defines the adding form of a board entity.
+import "fmt"
+
+// Board defines the adding form of a board entity. The board type names the template the board is created from, which
+// provides the defaults for any of the other fields that are not set. Boards with their own columns must place every
+// status of the template's workflow in a column or in the unmapped statuses.
 type Board struct {
-	Type string `json:"type"`
+	Type             string        `json:"type"`
+	Name             string        `json:"name,omitempty"`
+	Description      string        `json:"description,omitempty"`
+	IsBacklogVisible *bool         `json:"isBacklogVisible,omitempty"`
+	IsBoardVisible   *bool         `json:"isBoardVisible,omitempty"`
+	Filters          []BoardFilter `json:"filters,omitempty"`
+	Columns          []BoardColumn `json:"columns,omitempty"`
+	UnmappedStatuses []string      `json:"unmappedStatuses,omitempty"`
+}
+
+// BoardFilter defines the adding form of a board issue filter, for example a field of "label" and a value of
+// "mobile". Boards only show the issues that match all of their filters.
+type BoardFilter struct {
+	Field string `json:"field"`
+	Value string `json:"value"`
+}
+
+// BoardColumn defines the adding form of a board column Value Object.
+type BoardColumn struct {
+	Name          string   `json:"name"`
+	IssueStatuses []string `json:"issueStatuses"`
+}
+
+func validateAddBoard(b *Board) error {
+	if b == nil {
+		return fmt.Errorf("Board is nil")
+	}
+	if len(b.Type) == 0 {
+		return fmt.Errorf("'type' is empty")
+	}
+	for _, f := range b.Filters {
+		if len(f.Field) == 0 {
+			return fmt.Errorf("Filter 'field' is empty")
+		}
+	}
+
+	return validateBoardColumns(b.Columns)
+}
+
+// BoardType defines the adding form of a board type entity, the template that boards are created from. Board types
+// without columns give their boards a column for each step of the workflow.
+type BoardType struct {
+	Name             string        `json:"name"`
+	IsSprintable     bool          `json:"isSprintable"`
+	IsBacklogVisible bool          `json:"isBacklogVisible"`
+	IsBoardVisible   bool          `json:"isBoardVisible"`
+	WorkflowID       int32         `json:"workflowId"`
+	IsDefault        bool          `json:"default"`
+	Columns          []BoardColumn `json:"columns,omitempty"`
+	UnmappedStatuses []string      `json:"unmappedStatuses,omitempty"`
+}
+
+// BoardTypeFromBoard defines the adding form of a board type created from the configuration of an existing board.
+type BoardTypeFromBoard struct {
+	Name      string `json:"name"`
+	IsDefault bool   `json:"default"`
+}
+
+func validateAddBoardType(bt *BoardType) error {
+	if bt == nil {
+		return fmt.Errorf("Board type is nil")
+	}
+	if len(bt.Name) == 0 {
+		return fmt.Errorf("'name' is empty")
+	}
+	if bt.WorkflowID == 0 {
+		return fmt.Errorf("'workflowId' is empty")
+	}
+
+	return validateBoardColumns(bt.Columns)
+}
+
+func validateAddBoardTypeFromBoard(bt *BoardTypeFromBoard) error {
+	if bt == nil {
+		return fmt.Errorf("Board type is nil")
+	}
+	if len(bt.Name) == 0 {
+		return fmt.Errorf("'name' is empty")
+	}
+
+	return nil
+}
+
+func validateBoardColumns(columns []BoardColumn) error {
+	names := make(map[string]bool)
+	statuses := make(map[string]bool)
+	for _, c := range columns {
+		if len(c.Name) == 0 {
+			return fmt.Errorf("Column 'name' is empty")
+		}
+		if names[c.Name] {
+			return fmt.Errorf("Column %v is duplicated", c.Name)
+		}
+		names[c.Name] = true
+
+		for _, status := range c.IssueStatuses {
+			if statuses[status] {
+				return fmt.Errorf("Issue status %v is in more than one column", status)
+			}
+			statuses[status] = true
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,42 @@
+package adding
+
+import "fmt"
+
+// CustomField defines the adding form of a custom field entity.
+type CustomField struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Type        string   `json:"type"`
+	Options     []string `json:"options,omitempty"`
+	IsRequired  bool     `json:"isRequired"`
+	ProjectIDs  []string `json:"projectIds,omitempty"`
+	IssueTypes  []string `json:"issueTypes,omitempty"`
+}
+
+var customFieldTypes = []string{"TEXT", "NUMBER", "DATE", "SELECT", "MULTI_SELECT", "USER"}
+
+func validateAddCustomField(cf *CustomField) error {
+	if cf == nil {
+		return fmt.Errorf("Custom field is nil")
+	}
+	if len(cf.Name) == 0 {
+		return fmt.Errorf("'name' is empty")
+	}
+
+	isValidType := false
+	for _, t := range customFieldTypes {
+		if t == cf.Type {
+			isValidType = true
+			break
+		}
+	}
+	if !isValidType {
+		return fmt.Errorf("'type' must be one of %v", customFieldTypes)
+	}
+
+	if (cf.Type == "SELECT" || cf.Type == "MULTI_SELECT") && len(cf.Options) == 0 {
+		return fmt.Errorf("'options' is empty")
+	}
+
+	return nil
+}
@@ -6,10 +6,28 @@ type EventType string
 const (
 	// IssueAdded defines the EventType for when an issue has been added.
 	IssueAdded EventType = "ISSUE_ADDED"
+	// IssueCloned defines the EventType for when an issue has been cloned.
+	IssueCloned EventType = "ISSUE_CLONED"
 	// IssueCommentAdded defines the EventType for when an issue comment has been added.
 	IssueCommentAdded EventType = "ISSUE_COMMENT_ADDED"
+	// IssueImportProgressed defines the EventType for when an issue import job has added more of its issues, or finished.
+	IssueImportProgressed EventType = "ISSUE_IMPORT_PROGRESSED"
+	// IssueScheduleAdded defines the EventType for when a project issue schedule has been added.
+	IssueScheduleAdded EventType = "ISSUE_SCHEDULE_ADDED"
+	// IssueWorklogAdded defines the EventType for when an issue worklog has been added.
+	IssueWorklogAdded EventType = "ISSUE_WORKLOG_ADDED"
 	// ProjectAdded defines the EventType for when a project has been added.
 	ProjectAdded EventType = "PROJECT_ADDED"
+	// ProjectComponentAdded defines the EventType for when a project component has been added.
+	ProjectComponentAdded EventType = "PROJECT_COMPONENT_ADDED"
+	// ProjectIssueTemplateAdded defines the EventType for when a project issue template has been added.
+	ProjectIssueTemplateAdded EventType = "PROJECT_ISSUE_TEMPLATE_ADDED"
+	// ProjectSLAPolicyAdded defines the EventType for when a project SLA policy has been added.
+	ProjectSLAPolicyAdded EventType = "PROJECT_SLA_POLICY_ADDED"
+	// ProjectVersionAdded defines the EventType for when a project version has been added.
+	ProjectVersionAdded EventType = "PROJECT_VERSION_ADDED"
+	// ProjectBoardAdded defines the EventType for when a project board has been added.
+	ProjectBoardAdded EventType = "PROJECT_BOARD_ADDED"
 	// ProjectBoardSprintAdded defines the EventType for when a project board sprint has been added.
 	ProjectBoardSprintAdded EventType = "PROJECT_BOARD_SPRINT_ADDED"
 )
@@ -26,20 +44,84 @@ type IssueAddedPayload struct {
 	ProjectID string `json:"projectId"`
 }
 
+// IssueClonedPayload defines the payload of data for an issue cloned event.
+type IssueClonedPayload struct {
+	UserID       string `json:"userId"`
+	ProjectID    string `json:"projectId"`
+	IssueID      string `json:"issueId"`
+	ClonedFromID string `json:"clonedFromId"`
+}
+
 // IssueCommentAddedPayload defines the payload of data for an issue comment added event.
 type IssueCommentAddedPayload struct {
 	UserID  string `json:"userId"`
 	IssueID string `json:"issueId"`
 }
 
+// IssueImportProgressPayload defines the payload of data for an issue import progressed event.
+type IssueImportProgressPayload struct {
+	UserID    string `json:"userId"`
+	ProjectID string `json:"projectId"`
+	ImportID  string `json:"importId"`
+	Status    string `json:"status"`
+	Total     int    `json:"total"`
+	Processed int    `json:"processed"`
+	Added     int    `json:"added"`
+	Failed    int    `json:"failed"`
+}
+
+// IssueScheduleAddedPayload defines the payload of data for a project issue schedule added event.
+type IssueScheduleAddedPayload struct {
+	UserID    string `json:"userId"`
+	ProjectID string `json:"projectId"`
+}
+
+// IssueWorklogAddedPayload defines the payload of data for an issue worklog added event.
+type IssueWorklogAddedPayload struct {
+	UserID  string `json:"userId"`
+	IssueID string `json:"issueId"`
+}
+
 // ProjectAddedPayload defines the payload of data for a project added event.
 type ProjectAddedPayload struct {
 	UserID string `json:"userId"`
 }
 
+// ProjectBoardAddedPayload defines the payload of data for a project board added event.
+type ProjectBoardAddedPayload struct {
+	UserID    string `json:"userId"`
+	ProjectID string `json:"projectId"`
+}
+
 // ProjectBoardSprintAddedPayload defines the payload of data for a project board sprint added event.
 type ProjectBoardSprintAddedPayload struct {
 	UserID    string `json:"userId"`
 	ProjectID string `json:"projectId"`
 	BoardID   string `json:"boardId"`
 }
+
+// ProjectComponentAddedPayload defines the payload of data for a project component added event.
+type ProjectComponentAddedPayload struct {
+	UserID      string `json:"userId"`
+	ProjectID   string `json:"projectId"`
+	ComponentID string `json:"componentId,omitempty"`
+}
+
+// ProjectIssueTemplateAddedPayload defines the payload of data for a project issue template added event.
+type ProjectIssueTemplateAddedPayload struct {
+	UserID    string `json:"userId"`
+	ProjectID string `json:"projectId"`
+}
+
+// ProjectSLAPolicyAddedPayload defines the payload of data for a project SLA policy added event.
+type ProjectSLAPolicyAddedPayload struct {
+	UserID    string `json:"userId"`
+	ProjectID string `json:"projectId"`
+}
+
+// ProjectVersionAddedPayload defines the payload of data for a project version added event.
+type ProjectVersionAddedPayload struct {
+	UserID    string `json:"userId"`
+	ProjectID string `json:"projectId"`
+	VersionID string `json:"versionId,omitempty"`
+}
@@ -0,0 +1,282 @@
+package adding
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/njehyde/issue-tracker/libraries/slog"
+)
+
+// IssueImport defines the adding form of an import of issues from a CSV file into a project. A dry run only checks the
+// rows of the file, and returns them with their errors.
+type IssueImport struct {
+	Mapping IssueImportMapping `json:"mapping"`
+	DryRun  bool               `json:"dryRun"`
+}
+
+// IssueImportMapping defines which column of a CSV file, by its header, each issue field is read from. Assignees are
+// read as user emails, and labels as a comma or semicolon separated list.
+type IssueImportMapping struct {
+	Summary  string `json:"summary"`
+	Type     string `json:"type"`
+	Status   string `json:"status"`
+	Priority string `json:"priority"`
+	Points   string `json:"points,omitempty"`
+	Assignee string `json:"assignee,omitempty"`
+	Labels   string `json:"labels,omitempty"`
+}
+
+// IssueImportRow defines a row of a CSV file being imported, numbered from the first row after the header, the issue
+// it adds, and anything wrong with it. Issue types, statuses and priorities can be given by id or by name.
+type IssueImportRow struct {
+	Row           int      `json:"row"`
+	Summary       string   `json:"summary"`
+	Type          string   `json:"type"`
+	Status        string   `json:"status"`
+	Priority      string   `json:"priority"`
+	Points        int32    `json:"points,omitempty"`
+	AssigneeEmail string   `json:"assignee,omitempty"`
+	AssigneeID    string   `json:"assigneeId,omitempty"`
+	Labels        []string `json:"labels,omitempty"`
+	Errors        []string `json:"errors,omitempty"`
+}
+
+// IssueImportResult defines the outcome of an import: the checked rows of a dry run, or the id of the job that adds
+// the issues in the background.
+type IssueImportResult struct {
+	ImportID string           `json:"importId,omitempty"`
+	DryRun   bool             `json:"dryRun"`
+	Total    int              `json:"total"`
+	Invalid  int              `json:"invalid"`
+	Rows     []IssueImportRow `json:"rows,omitempty"`
+}
+
+// IssueImportProgress defines how far an issue import job has got.
+type IssueImportProgress struct {
+	Status    string
+	Total     int
+	Processed int
+	Added     int
+	Errors    []IssueImportError
+}
+
+// IssueImportError defines why a row of an issue import job could not be added.
+type IssueImportError struct {
+	Row     int
+	Message string
+}
+
+// Issue import job statuses.
+const (
+	// IssueImportRunning is the status of an import job that is adding its issues.
+	IssueImportRunning = "RUNNING"
+	// IssueImportCompleted is the status of an import job that has been through all of its rows.
+	IssueImportCompleted = "COMPLETED"
+)
+
+// maxImportRows is the most rows that a CSV file can have, so that an import is held in memory safely.
+const maxImportRows = 10000
+
+// importProgressInterval is how many rows an import job adds between reports of its progress.
+const importProgressInterval = 25
+
+func validateIssueImport(i *IssueImport) error {
+	if i == nil {
+		return fmt.Errorf("Issue import is nil")
+	}
+	if len(i.Mapping.Summary) == 0 {
+		return fmt.Errorf("'summary' column is empty")
+	}
+	if len(i.Mapping.Type) == 0 {
+		return fmt.Errorf("'type' column is empty")
+	}
+	if len(i.Mapping.Status) == 0 {
+		return fmt.Errorf("'status' column is empty")
+	}
+	if len(i.Mapping.Priority) == 0 {
+		return fmt.Errorf("'priority' column is empty")
+	}
+
+	return nil
+}
+
+// readIssueImportRows reads the rows of a CSV file through a column mapping, checking the values that do not need the
+// project to be checked.
+func readIssueImportRows(r io.Reader, m *IssueImportMapping) ([]IssueImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("CSV file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int)
+	for n, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = n
+	}
+
+	column := func(name string) (int, error) {
+		if len(name) == 0 {
+			return -1, nil
+		}
+		n, ok := columns[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return -1, fmt.Errorf("Column '%v' is not in the CSV file", name)
+		}
+		return n, nil
+	}
+
+	fields := []string{m.Summary, m.Type, m.Status, m.Priority, m.Points, m.Assignee, m.Labels}
+	indexes := make([]int, len(fields))
+	for n, name := range fields {
+		if indexes[n], err = column(name); err != nil {
+			return nil, err
+		}
+	}
+
+	rows := []IssueImportRow{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == maxImportRows {
+			return nil, fmt.Errorf("CSV file has more than %v rows", maxImportRows)
+		}
+
+		value := func(field int) string {
+			n := indexes[field]
+			if n < 0 || n >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[n])
+		}
+
+		row := IssueImportRow{
+			Row:           len(rows) + 1,
+			Summary:       value(0),
+			Type:          value(1),
+			Status:        value(2),
+			Priority:      value(3),
+			AssigneeEmail: value(5),
+			Labels:        splitImportLabels(value(6)),
+		}
+
+		if len(row.Summary) == 0 {
+			row.Errors = append(row.Errors, "'summary' is empty")
+		}
+		if len(row.Type) == 0 {
+			row.Errors = append(row.Errors, "'type' is empty")
+		}
+		if len(row.Status) == 0 {
+			row.Errors = append(row.Errors, "'status' is empty")
+		}
+		if len(row.Priority) == 0 {
+			row.Errors = append(row.Errors, "'priority' is empty")
+		}
+		if points := value(4); len(points) > 0 {
+			p, err := strconv.ParseInt(points, 10, 32)
+			if err != nil || p < 0 {
+				row.Errors = append(row.Errors, fmt.Sprintf("Invalid points %v", points))
+			}
+			row.Points = int32(p)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func splitImportLabels(value string) []string {
+	results := []string{}
+	seen := make(map[string]bool)
+
+	for _, l := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' }) {
+		l = strings.TrimSpace(l)
+		if len(l) == 0 || seen[l] {
+			continue
+		}
+		seen[l] = true
+		results = append(results, l)
+	}
+
+	return results
+}
+
+// runIssueImport adds the issues of the rows of an import job, one at a time, saving and announcing the job's
+// progress as it goes. Rows that cannot be added are recorded on the job, and the rest are still added.
+func (s *service) runIssueImport(userID string, projectID string, importID string, rows []IssueImportRow) {
+	progress := IssueImportProgress{Status: IssueImportRunning, Total: len(rows)}
+
+	report := func() {
+		if err := s.repo.UpdateIssueImport(&importID, &progress); err != nil {
+			slog.Errorf("Issue import %v progress could not be saved: %v", importID, err)
+		}
+
+		payload := IssueImportProgressPayload{
+			UserID:    userID,
+			ProjectID: projectID,
+			ImportID:  importID,
+			Status:    progress.Status,
+			Total:     progress.Total,
+			Processed: progress.Processed,
+			Added:     progress.Added,
+			Failed:    len(progress.Errors),
+		}
+		if err := s.broadcastEvent(IssueImportProgressed, payload); err != nil {
+			slog.Errorf("Issue import %v progress could not be announced: %v", importID, err)
+		}
+	}
+
+	for _, row := range rows {
+		labels := []Label{}
+		for _, l := range row.Labels {
+			labels = append(labels, Label{IsNew: false, Value: l})
+		}
+
+		i := Issue{
+			ProjectID:  projectID,
+			Type:       row.Type,
+			Summary:    row.Summary,
+			Status:     row.Status,
+			Priority:   row.Priority,
+			Points:     row.Points,
+			ReporterID: userID,
+			AssigneeID: row.AssigneeID,
+			Labels:     labels,
+		}
+
+		if err := s.repo.AddIssue(&i); err != nil {
+			progress.Errors = append(progress.Errors, IssueImportError{Row: row.Row, Message: err.Error()})
+		} else {
+			progress.Added++
+		}
+
+		progress.Processed++
+		if progress.Processed%importProgressInterval == 0 && progress.Processed < progress.Total {
+			report()
+		}
+	}
+
+	progress.Status = IssueImportCompleted
+	report()
+
+	if progress.Added > 0 {
+		payload := IssueAddedPayload{userID, projectID}
+		if err := s.broadcastEvent(IssueAdded, payload); err != nil {
+			slog.Errorf("Issue import %v could not announce its issues: %v", importID, err)
+		}
+	}
+}
@@ -1,17 +1,30 @@
 package adding
 
+import (
+	"fmt"
+	"time"
+)
+
 // Issue defines the adding form of an issue entity.
 type Issue struct {
-	ProjectID   string  `json:"projectID"`
-	Type        string  `json:"type"`
-	Summary     string  `json:"summary"`
-	Description string  `json:"description,omitempty"`
-	Status      string  `json:"status"`
-	Priority    string  `json:"priority"`
-	Points      int32   `json:"points,omitempty"`
-	ReporterID  string  `json:"reporterId"`
-	AssigneeID  string  `json:"assigneeId,omitempty"`
-	Labels      []Label `json:"labels,omitempty"`
+	ProjectID         string                 `json:"projectID"`
+	Type              string                 `json:"type"`
+	Summary           string                 `json:"summary"`
+	Description       string                 `json:"description,omitempty"`
+	Status            string                 `json:"status"`
+	Priority          string                 `json:"priority"`
+	Points            int32                  `json:"points,omitempty"`
+	OriginalEstimate  int64                  `json:"originalEstimate,omitempty"`
+	RemainingEstimate *int64                 `json:"remainingEstimate,omitempty"`
+	DueDate           *time.Time             `json:"dueDate,omitempty"`
+	ReporterID        string                 `json:"reporterId"`
+	AssigneeID        string                 `json:"assigneeId,omitempty"`
+	EpicID            string                 `json:"epicId,omitempty"`
+	Labels            []Label                `json:"labels,omitempty"`
+	Components        []string               `json:"components,omitempty"`
+	FixVersions       []string               `json:"fixVersions,omitempty"`
+	CustomFields      map[string]interface{} `json:"customFields,omitempty"`
+	TemplateID        string                 `json:"templateId,omitempty"`
 }
 
 // IssueComment defines the adding form of an issue comment entity.
@@ -26,6 +39,14 @@ type IssueStatus struct {
 	Category    string `json:"category"`
 }
 
+// IssueType defines the adding form of an issue type entity.
+type IssueType struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Icon        string `json:"icon,omitempty"`
+	IsSubTask   bool   `json:"subTask"`
+}
+
 // Label defines the adding form of a label entity.
 type Label struct {
 	IsNew bool   `json:"isNew"`
@@ -38,3 +59,14 @@ type PriorityType struct {
 	Description string `json:"description"`
 	Color       string `json:"color"`
 }
+
+func validateAddIssueType(it *IssueType) error {
+	if it == nil {
+		return fmt.Errorf("Issue type is nil")
+	}
+	if len(it.Name) == 0 {
+		return fmt.Errorf("'name' is empty")
+	}
+
+	return nil
+}
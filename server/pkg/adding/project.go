@@ -32,3 +32,35 @@ func validateAddProject(p *Project) error {
 
 	return nil
 }
+
+// Component defines the adding form of a project component entity.
+type Component struct {
+	Name         string `json:"name"`
+	Description  string `json:"description,omitempty"`
+	LeadID       string `json:"leadId,omitempty"`
+	AssigneeType string `json:"assigneeType"`
+}
+
+var componentAssigneeTypes = []string{"PROJECT_DEFAULT", "COMPONENT_LEAD", "UNASSIGNED"}
+
+func validateAddComponent(c *Component) error {
+	if c == nil {
+		return fmt.Errorf("Component is nil")
+	}
+	if len(c.Name) == 0 {
+		return fmt.Errorf("'name' is empty")
+	}
+	if len(c.AssigneeType) == 0 {
+		c.AssigneeType = componentAssigneeTypes[0]
+	}
+	for _, t := range componentAssigneeTypes {
+		if t == c.AssigneeType {
+			if t == "COMPONENT_LEAD" && len(c.LeadID) == 0 {
+				return fmt.Errorf("'leadId' is empty")
+			}
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Unknown assignee type %v", c.AssigneeType)
+}
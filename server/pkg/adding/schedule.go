@@ -0,0 +1,176 @@
+package adding
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/njehyde/issue-tracker/libraries/recurrence"
+	"github.com/njehyde/issue-tracker/libraries/slog"
+)
+
+// IssueSchedule defines the adding form of a project issue schedule entity, which creates an issue from its template
+// at every occurrence of its rule. The rule is a cron expression, or an RRULE, in the schedule's time zone.
+type IssueSchedule struct {
+	Name     string         `json:"name"`
+	Rule     string         `json:"rule"`
+	Timezone string         `json:"timezone,omitempty"`
+	StartAt  *time.Time     `json:"startAt,omitempty"`
+	CatchUp  string         `json:"catchUp,omitempty"`
+	Issue    ScheduledIssue `json:"issue"`
+}
+
+// ScheduledIssue defines the adding form of the issue template of an issue schedule. Issues are due the given number
+// of seconds after they are created.
+type ScheduledIssue struct {
+	Type             string                 `json:"type"`
+	Summary          string                 `json:"summary"`
+	Description      string                 `json:"description,omitempty"`
+	Status           string                 `json:"status"`
+	Priority         string                 `json:"priority"`
+	Points           int32                  `json:"points,omitempty"`
+	OriginalEstimate int64                  `json:"originalEstimate,omitempty"`
+	DueIn            int64                  `json:"dueIn,omitempty"`
+	AssigneeID       string                 `json:"assigneeId,omitempty"`
+	EpicID           string                 `json:"epicId,omitempty"`
+	Labels           []string               `json:"labels,omitempty"`
+	Components       []string               `json:"components,omitempty"`
+	CustomFields     map[string]interface{} `json:"customFields,omitempty"`
+}
+
+// DueIssueSchedule defines an issue schedule with runs that are due.
+type DueIssueSchedule struct {
+	ID        string
+	ProjectID string
+	UserID    string
+	Rule      string
+	Timezone  string
+	StartAt   time.Time
+	CatchUp   string
+	NextRunAt time.Time
+}
+
+// Catch up policies, for the runs of an issue schedule that were missed while the server was down.
+const (
+	// CatchUpSkip skips missed runs.
+	CatchUpSkip = "SKIP"
+	// CatchUpLatest creates an issue for the latest missed run only.
+	CatchUpLatest = "LATEST"
+	// CatchUpAll creates an issue for every missed run.
+	CatchUpAll = "ALL"
+)
+
+// missedRunGrace is how late a run can be before it is treated as missed.
+const missedRunGrace = 15 * time.Minute
+
+// maxCatchUpRuns is the most missed runs that a schedule catches up on at once, and maxDueRuns the most due runs that
+// are looked at.
+const (
+	maxCatchUpRuns = 50
+	maxDueRuns     = 1000
+)
+
+func validateAddIssueSchedule(s *IssueSchedule) error {
+	if s == nil {
+		return fmt.Errorf("Issue schedule is nil")
+	}
+	if len(s.Name) == 0 {
+		return fmt.Errorf("'name' is empty")
+	}
+	if len(s.Rule) == 0 {
+		return fmt.Errorf("'rule' is empty")
+	}
+	if len(s.Timezone) == 0 {
+		s.Timezone = "UTC"
+	}
+	if len(s.CatchUp) == 0 {
+		s.CatchUp = CatchUpLatest
+	}
+	if s.CatchUp != CatchUpSkip && s.CatchUp != CatchUpLatest && s.CatchUp != CatchUpAll {
+		return fmt.Errorf("Unknown catch up policy %v", s.CatchUp)
+	}
+	if len(s.Issue.Type) == 0 {
+		return fmt.Errorf("'type' is empty")
+	}
+	if len(s.Issue.Summary) == 0 {
+		return fmt.Errorf("'summary' is empty")
+	}
+	if len(s.Issue.Status) == 0 {
+		return fmt.Errorf("'status' is empty")
+	}
+	if len(s.Issue.Priority) == 0 {
+		return fmt.Errorf("'priority' is empty")
+	}
+	if s.Issue.DueIn < 0 {
+		return fmt.Errorf("'dueIn' must not be negative")
+	}
+
+	return nil
+}
+
+// getIssueScheduleRule parses the rule of an issue schedule in its time zone, starting from its start time or now.
+func getIssueScheduleRule(rule string, timezone string, startAt *time.Time) (recurrence.Rule, time.Time, error) {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("Unknown time zone %v", timezone)
+	}
+
+	start := time.Now()
+	if startAt != nil {
+		start = *startAt
+	}
+	start = start.In(loc)
+
+	r, err := recurrence.Parse(rule, start)
+	if err != nil {
+		return nil, start, err
+	}
+
+	return r, start, nil
+}
+
+// getNextRunAt returns the first run of a rule after a time, or nil when the rule has no more runs.
+func getNextRunAt(r recurrence.Rule, after time.Time) *time.Time {
+	next, ok := r.Next(after)
+	if !ok {
+		return nil
+	}
+
+	return &next
+}
+
+// runs returns the runs of a due issue schedule that should create issues, following its catch up policy, and its
+// next run after now.
+func (d *DueIssueSchedule) runs(now time.Time) ([]time.Time, *time.Time, error) {
+	r, _, err := getIssueScheduleRule(d.Rule, d.Timezone, &d.StartAt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	due := recurrence.Between(r, d.NextRunAt.Add(-time.Nanosecond), now, maxDueRuns)
+
+	results := []time.Time{}
+	for n, t := range due {
+		switch {
+		case now.Sub(t) <= missedRunGrace:
+		case d.CatchUp == CatchUpAll && len(results) < maxCatchUpRuns:
+		case d.CatchUp == CatchUpLatest && n == len(due)-1:
+		default:
+			continue
+		}
+		results = append(results, t)
+	}
+
+	return results, getNextRunAt(r, now), nil
+}
+
+// WatchIssueSchedules runs the due issue schedules every interval, until the process exits.
+func WatchIssueSchedules(s Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := s.RunIssueSchedules(); err != nil {
+			slog.Errorf("Running issue schedules failed: %v", err)
+		}
+	}
+}
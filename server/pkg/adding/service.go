@@ -2,41 +2,109 @@ package adding
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
+	"time"
 
+	"github.com/njehyde/issue-tracker/libraries/slog"
 	"github.com/njehyde/issue-tracker/pkg/http/ws"
 )
 
 // Service provides entity adding operations.
 type Service interface {
+	// AddBoardType adds a new board type entity.
+	AddBoardType(*BoardType) error
+	// AddBoardTypeFromBoard adds a new board type entity from the configuration of a project board.
+	AddBoardTypeFromBoard(*string, *string, *BoardTypeFromBoard) error
+	// AddCustomField adds a new custom field entity.
+	AddCustomField(*CustomField) error
 	// AddIssue adds a new issue entity.
 	AddIssue(*string, *Issue) error
 	// AddIssueComment adds a new issue comment entity.
 	AddIssueComment(*string, *string, *IssueComment) error
+	// AddIssueSchedule adds a new project issue schedule entity.
+	AddIssueSchedule(*string, *string, *IssueSchedule) error
 	// AddIssueStatus adds a new issue status entity.
 	AddIssueStatus(*IssueStatus) error
+	// AddIssueType adds a new issue type entity.
+	AddIssueType(*IssueType) error
+	// AddIssueWorklog logs work against an issue.
+	AddIssueWorklog(*string, *string, *IssueWorklog) error
 	// AddPriorityType adds a new priority type entity.
 	AddPriorityType(*PriorityType) error
 	// AddProject adds a new project entity.
 	AddProject(*string, *Project) error
+	// AddProjectComponent adds a new project component entity.
+	AddProjectComponent(*string, *string, *Component) error
+	// AddProjectIssueTemplate adds a new project issue template entity.
+	AddProjectIssueTemplate(*string, *string, *IssueTemplate) error
+	// AddProjectSLAPolicy adds a new project SLA policy entity.
+	AddProjectSLAPolicy(*string, *string, *SLAPolicy) error
+	// AddProjectVersion adds a new project version entity.
+	AddProjectVersion(*string, *string, *Version) error
+	// AddProjectBoard adds a new project board entity.
+	AddProjectBoard(*string, *string, *Board) error
 	// AddProjectBoardSprint adds a new project board sprint entity.
 	AddProjectBoardSprint(*string, *string, *string) error
+	// CloneIssue adds a copy of an issue, with its comments and history, to the same or another project.
+	CloneIssue(*string, *string, *IssueClone) error
+	// ImportIssues checks the rows of a CSV file, and unless it is a dry run, adds their issues to a project in the background.
+	ImportIssues(*string, *string, *IssueImport, io.Reader) (*IssueImportResult, error)
+	// RunIssueSchedules creates the issues of the issue schedules with runs that are due.
+	RunIssueSchedules() error
 	// AddUser(User) error
 }
 
 // Repository provides access to the adding repository.
 type Repository interface {
+	// AddBoardType saves a board type to the repository.
+	AddBoardType(*BoardType) error
+	// AddBoardTypeFromBoard saves a board type, from the configuration of a project board, to the repository.
+	AddBoardTypeFromBoard(*string, *string, *BoardTypeFromBoard) error
+	// AddCustomField saves a custom field to the repository.
+	AddCustomField(*CustomField) error
 	// AddIssue saves an issue to the repository
 	AddIssue(*Issue) error
+	// AddIssueImport saves an issue import job, and any labels its rows need, to the repository, and returns its id.
+	AddIssueImport(*string, *string, []IssueImportRow) (string, error)
 	// AddIssueComment saves a issue comment entity to the repository.
 	AddIssueComment(*string, *string, *IssueComment) error
+	// AddIssueSchedule saves a project issue schedule, and when it next runs, to the repository.
+	AddIssueSchedule(*string, *string, *IssueSchedule, *time.Time) error
+	// AddScheduledIssue saves the issue of an issue schedule run to the repository, unless the run already created one.
+	AddScheduledIssue(*string, time.Time) (bool, error)
 	// AddIssueStatus saves a issue status to the repository.
 	AddIssueStatus(*IssueStatus) error
+	// AddIssueType saves an issue type to the repository.
+	AddIssueType(*IssueType) error
+	// AddIssueWorklog saves an issue worklog entity to the repository.
+	AddIssueWorklog(*string, *string, *IssueWorklog) error
 	// AddPriorityType saves a priority type to the repository.
 	AddPriorityType(*PriorityType) error
 	// AddProject saves a project to the repository
 	AddProject(*Project) error
+	// AddProjectComponent saves a project component to the repository
+	AddProjectComponent(*string, *Component) error
+	// AddProjectIssueTemplate saves a project issue template to the repository
+	AddProjectIssueTemplate(*string, *IssueTemplate) error
+	// AddProjectSLAPolicy saves a project SLA policy to the repository
+	AddProjectSLAPolicy(*string, *SLAPolicy) error
+	// AddProjectVersion saves a project version to the repository
+	AddProjectVersion(*string, *Version) error
+	// AddProjectBoard saves a project board to the repository
+	AddProjectBoard(*string, *Board) error
 	// AddProjectBoardSprint saves a project board sprint to the repository
 	AddProjectBoardSprint(*string, *string, *string) error
+	// CheckIssueImportRows checks the rows of an issue import against a project, resolving their values, and records their errors.
+	CheckIssueImportRows(*string, []IssueImportRow) error
+	// CloneIssue saves a copy of an issue, with its comments and history, to the repository, and returns the id of the copy.
+	CloneIssue(*string, *IssueClone) (string, error)
+	// GetDueIssueSchedules returns the enabled issue schedules with runs that are due from the repository.
+	GetDueIssueSchedules(time.Time) ([]DueIssueSchedule, error)
+	// UpdateIssueImport saves the progress of an issue import job to the repository.
+	UpdateIssueImport(*string, *IssueImportProgress) error
+	// UpdateIssueScheduleRun saves when an issue schedule last ran, when it next runs, and any error, to the repository.
+	UpdateIssueScheduleRun(*string, time.Time, *time.Time, string) error
 	// AddUser saves a user to the repository
 	// AddUser(User) error
 }
@@ -51,6 +119,48 @@ func NewService(r Repository, hub *ws.Hub) Service {
 	return &service{r, hub}
 }
 
+func (s *service) AddBoardType(bt *BoardType) error {
+	err := validateAddBoardType(bt)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.AddBoardType(bt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) AddBoardTypeFromBoard(projectID *string, boardID *string, bt *BoardTypeFromBoard) error {
+	err := validateAddBoardTypeFromBoard(bt)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.AddBoardTypeFromBoard(projectID, boardID, bt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) AddCustomField(cf *CustomField) error {
+	err := validateAddCustomField(cf)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.AddCustomField(cf)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (s *service) AddIssue(userID *string, i *Issue) error {
 	// TODO: Validation for AddIssue
 	// err = validateAddIssue(*i)
@@ -91,6 +201,38 @@ func (s *service) AddIssueComment(userID *string, issueID *string, c *IssueComme
 	return nil
 }
 
+func (s *service) AddIssueSchedule(userID *string, projectID *string, is *IssueSchedule) error {
+	err := validateAddIssueSchedule(is)
+	if err != nil {
+		return err
+	}
+
+	r, start, err := getIssueScheduleRule(is.Rule, is.Timezone, is.StartAt)
+	if err != nil {
+		return err
+	}
+	is.StartAt = &start
+
+	// Runs before the schedule is added are not caught up on
+	after := time.Now()
+	if start.After(after) {
+		after = start.Add(-time.Nanosecond)
+	}
+
+	err = s.repo.AddIssueSchedule(projectID, userID, is, getNextRunAt(r, after))
+	if err != nil {
+		return err
+	}
+
+	payload := IssueScheduleAddedPayload{UserID: *userID, ProjectID: *projectID}
+	err = s.broadcastEvent(IssueScheduleAdded, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (s *service) AddIssueStatus(i *IssueStatus) error {
 	// TODO: Validation for AddIssueStatus
 	// err = validateAddIssueStatus(*i)
@@ -105,6 +247,40 @@ func (s *service) AddIssueStatus(i *IssueStatus) error {
 	return nil
 }
 
+func (s *service) AddIssueType(it *IssueType) error {
+	err := validateAddIssueType(it)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.AddIssueType(it)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) AddIssueWorklog(userID *string, issueID *string, w *IssueWorklog) error {
+	err := validateAddIssueWorklog(w)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.AddIssueWorklog(issueID, userID, w)
+	if err != nil {
+		return err
+	}
+
+	payload := IssueWorklogAddedPayload{*userID, *issueID}
+	err = s.broadcastEvent(IssueWorklogAdded, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (s *service) AddPriorityType(pt *PriorityType) error {
 	// TODO: Validation for AddPriorityType
 	// err = validateAddPriorityType(*pt)
@@ -138,6 +314,106 @@ func (s *service) AddProject(userID *string, p *Project) (err error) {
 	return nil
 }
 
+func (s *service) AddProjectComponent(userID *string, projectID *string, c *Component) error {
+	err := validateAddComponent(c)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.AddProjectComponent(projectID, c)
+	if err != nil {
+		return err
+	}
+
+	payload := ProjectComponentAddedPayload{UserID: *userID, ProjectID: *projectID}
+	err = s.broadcastEvent(ProjectComponentAdded, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) AddProjectIssueTemplate(userID *string, projectID *string, t *IssueTemplate) error {
+	err := validateAddIssueTemplate(t)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.AddProjectIssueTemplate(projectID, t)
+	if err != nil {
+		return err
+	}
+
+	payload := ProjectIssueTemplateAddedPayload{UserID: *userID, ProjectID: *projectID}
+	err = s.broadcastEvent(ProjectIssueTemplateAdded, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) AddProjectSLAPolicy(userID *string, projectID *string, p *SLAPolicy) error {
+	err := validateAddSLAPolicy(p)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.AddProjectSLAPolicy(projectID, p)
+	if err != nil {
+		return err
+	}
+
+	payload := ProjectSLAPolicyAddedPayload{UserID: *userID, ProjectID: *projectID}
+	err = s.broadcastEvent(ProjectSLAPolicyAdded, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) AddProjectVersion(userID *string, projectID *string, v *Version) error {
+	err := validateAddVersion(v)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.AddProjectVersion(projectID, v)
+	if err != nil {
+		return err
+	}
+
+	payload := ProjectVersionAddedPayload{UserID: *userID, ProjectID: *projectID}
+	err = s.broadcastEvent(ProjectVersionAdded, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) AddProjectBoard(userID *string, projectID *string, b *Board) error {
+	err := validateAddBoard(b)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.AddProjectBoard(projectID, b)
+	if err != nil {
+		return err
+	}
+
+	payload := ProjectBoardAddedPayload{*userID, *projectID}
+	err = s.broadcastEvent(ProjectBoardAdded, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (s *service) AddProjectBoardSprint(userID *string, projectID *string, boardID *string) error {
 	// TODO: Validation for AddProjectBoardSprint
 	// err = validateAddProject(p)
@@ -176,6 +452,118 @@ func (s *service) AddProjectBoardSprint(userID *string, projectID *string, board
 // 	return nil
 // }
 
+func (s *service) CloneIssue(userID *string, issueID *string, c *IssueClone) error {
+	err := validateIssueClone(c)
+	if err != nil {
+		return err
+	}
+
+	cloneID, err := s.repo.CloneIssue(issueID, c)
+	if err != nil {
+		return err
+	}
+
+	payload := IssueClonedPayload{*userID, c.ProjectID, cloneID, *issueID}
+	err = s.broadcastEvent(IssueCloned, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) ImportIssues(userID *string, projectID *string, i *IssueImport, r io.Reader) (*IssueImportResult, error) {
+	err := validateIssueImport(i)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := readIssueImportRows(r, &i.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.CheckIssueImportRows(projectID, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	result := IssueImportResult{DryRun: i.DryRun, Total: len(rows)}
+	for _, row := range rows {
+		if len(row.Errors) > 0 {
+			result.Invalid++
+		}
+	}
+
+	if i.DryRun {
+		result.Rows = rows
+		return &result, nil
+	}
+
+	if result.Total == 0 {
+		return nil, fmt.Errorf("CSV file has no rows")
+	}
+	if result.Invalid > 0 {
+		return nil, fmt.Errorf("%v of %v rows have errors, which a dry run lists", result.Invalid, result.Total)
+	}
+
+	result.ImportID, err = s.repo.AddIssueImport(projectID, userID, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	go s.runIssueImport(*userID, *projectID, result.ImportID, rows)
+
+	return &result, nil
+}
+
+func (s *service) RunIssueSchedules() error {
+	now := time.Now()
+
+	schedules, err := s.repo.GetDueIssueSchedules(now)
+	if err != nil {
+		return err
+	}
+
+	for n := range schedules {
+		d := &schedules[n]
+
+		// A schedule that fails is stopped, or its run skipped, with the error recorded on the schedule rather than
+		// retried, so that a broken rule or template does not hold up the other schedules
+		var runErr string
+		runs, next, err := d.runs(now)
+		if err != nil {
+			slog.Errorf("Issue schedule %v could not be read: %v", d.ID, err)
+			runErr = err.Error()
+		}
+
+		for _, runAt := range runs {
+			added, err := s.repo.AddScheduledIssue(&d.ID, runAt)
+			if err != nil {
+				slog.Errorf("Issue schedule %v could not run at %v: %v", d.ID, runAt, err)
+				runErr = err.Error()
+				break
+			}
+			if !added {
+				continue
+			}
+
+			payload := IssueAddedPayload{d.UserID, d.ProjectID}
+			err = s.broadcastEvent(IssueAdded, payload)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = s.repo.UpdateIssueScheduleRun(&d.ID, now, next, runErr)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (s *service) broadcastEvent(eventType EventType, payload interface{}) error {
 	m := Message{Type: eventType, Payload: payload}
 	b, err := json.Marshal(m)
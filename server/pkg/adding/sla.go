@@ -0,0 +1,53 @@
+package adding
+
+import "fmt"
+
+// SLAPolicy defines the adding form of a project SLA policy entity. Its clock starts when an issue first enters a
+// status of one of the start categories, is paused in the pause categories, and stops in the stop categories.
+type SLAPolicy struct {
+	Name            string      `json:"name"`
+	StartCategories []string    `json:"startCategories"`
+	PauseCategories []string    `json:"pauseCategories,omitempty"`
+	StopCategories  []string    `json:"stopCategories"`
+	Targets         []SLATarget `json:"targets"`
+}
+
+// SLATarget defines the adding form of the time an SLA policy allows issues of a priority. Durations are in seconds.
+type SLATarget struct {
+	Priority string `json:"priority"`
+	Duration int64  `json:"duration"`
+}
+
+func validateAddSLAPolicy(p *SLAPolicy) error {
+	if p == nil {
+		return fmt.Errorf("SLA policy is nil")
+	}
+	if len(p.Name) == 0 {
+		return fmt.Errorf("'name' is empty")
+	}
+	if len(p.StartCategories) == 0 {
+		return fmt.Errorf("'startCategories' is empty")
+	}
+	if len(p.StopCategories) == 0 {
+		return fmt.Errorf("'stopCategories' is empty")
+	}
+	if len(p.Targets) == 0 {
+		return fmt.Errorf("'targets' is empty")
+	}
+
+	priorities := make(map[string]bool)
+	for _, t := range p.Targets {
+		if len(t.Priority) == 0 {
+			return fmt.Errorf("'priority' is empty")
+		}
+		if t.Duration <= 0 {
+			return fmt.Errorf("'duration' must be greater than zero")
+		}
+		if priorities[t.Priority] {
+			return fmt.Errorf("Priority %v has more than one target", t.Priority)
+		}
+		priorities[t.Priority] = true
+	}
+
+	return nil
+}
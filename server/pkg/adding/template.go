@@ -0,0 +1,29 @@
+package adding
+
+import "fmt"
+
+// IssueTemplate defines the adding form of a project issue template entity, which fills in the issues of an issue type
+// that are added from it.
+type IssueTemplate struct {
+	Name          string                 `json:"name"`
+	IssueType     string                 `json:"issueType"`
+	SummaryPrefix string                 `json:"summaryPrefix,omitempty"`
+	Description   string                 `json:"description,omitempty"`
+	Priority      string                 `json:"priority,omitempty"`
+	Labels        []string               `json:"labels,omitempty"`
+	CustomFields  map[string]interface{} `json:"customFields,omitempty"`
+}
+
+func validateAddIssueTemplate(t *IssueTemplate) error {
+	if t == nil {
+		return fmt.Errorf("Issue template is nil")
+	}
+	if len(t.Name) == 0 {
+		return fmt.Errorf("'name' is empty")
+	}
+	if len(t.IssueType) == 0 {
+		return fmt.Errorf("'issueType' is empty")
+	}
+
+	return nil
+}
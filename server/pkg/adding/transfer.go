@@ -0,0 +1,22 @@
+package adding
+
+import "fmt"
+
+// IssueClone defines the adding form of a copy of an issue in the same, or another, project. The copy keeps the issue
+// type and status of the issue, unless others are given for projects that do not have them.
+type IssueClone struct {
+	ProjectID string `json:"projectId"`
+	Type      string `json:"type,omitempty"`
+	Status    string `json:"status,omitempty"`
+}
+
+func validateIssueClone(c *IssueClone) error {
+	if c == nil {
+		return fmt.Errorf("Issue clone is nil")
+	}
+	if len(c.ProjectID) == 0 {
+		return fmt.Errorf("'projectId' is empty")
+	}
+
+	return nil
+}
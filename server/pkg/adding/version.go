@@ -0,0 +1,28 @@
+package adding
+
+import (
+	"fmt"
+	"time"
+)
+
+// Version defines the adding form of a project version entity.
+type Version struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	StartAt     *time.Time `json:"startAt,omitempty"`
+	ReleaseAt   *time.Time `json:"releaseAt,omitempty"`
+}
+
+func validateAddVersion(v *Version) error {
+	if v == nil {
+		return fmt.Errorf("Version is nil")
+	}
+	if len(v.Name) == 0 {
+		return fmt.Errorf("'name' is empty")
+	}
+	if v.StartAt != nil && v.ReleaseAt != nil && v.ReleaseAt.Before(*v.StartAt) {
+		return fmt.Errorf("'releaseAt' is before 'startAt'")
+	}
+
+	return nil
+}
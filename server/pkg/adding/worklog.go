@@ -0,0 +1,32 @@
+package adding
+
+import (
+	"fmt"
+	"time"
+)
+
+// IssueWorklog defines the adding form of an issue work log entry. Durations and estimates are in seconds. Without a
+// remaining estimate, the logged time is taken off the issue's remaining estimate.
+type IssueWorklog struct {
+	StartedAt         time.Time `json:"startedAt"`
+	Duration          int64     `json:"duration"`
+	Comment           string    `json:"comment,omitempty"`
+	RemainingEstimate *int64    `json:"remainingEstimate,omitempty"`
+}
+
+func validateAddIssueWorklog(w *IssueWorklog) error {
+	if w == nil {
+		return fmt.Errorf("Issue worklog is nil")
+	}
+	if w.StartedAt.IsZero() {
+		return fmt.Errorf("'startedAt' is empty")
+	}
+	if w.Duration <= 0 {
+		return fmt.Errorf("'duration' must be greater than zero")
+	}
+	if w.RemainingEstimate != nil && *w.RemainingEstimate < 0 {
+		return fmt.Errorf("'remainingEstimate' must not be negative")
+	}
+
+	return nil
+}
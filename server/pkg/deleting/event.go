@@ -8,8 +8,22 @@ const (
 	IssueDeleted EventType = "ISSUE_DELETED"
 	// IssueCommentDeleted defines the EventType for when an issue comment has been deleted.
 	IssueCommentDeleted EventType = "ISSUE_COMMENT_DELETED"
+	// IssueScheduleDeleted defines the EventType for when a project issue schedule has been deleted.
+	IssueScheduleDeleted EventType = "ISSUE_SCHEDULE_DELETED"
+	// IssueWorklogDeleted defines the EventType for when an issue worklog has been deleted.
+	IssueWorklogDeleted EventType = "ISSUE_WORKLOG_DELETED"
 	// ProjectDeleted defines the EventType for when a project has been deleted.
 	ProjectDeleted EventType = "PROJECT_DELETED"
+	// ProjectComponentDeleted defines the EventType for when a project component has been deleted.
+	ProjectComponentDeleted EventType = "PROJECT_COMPONENT_DELETED"
+	// ProjectIssueTemplateDeleted defines the EventType for when a project issue template has been deleted.
+	ProjectIssueTemplateDeleted EventType = "PROJECT_ISSUE_TEMPLATE_DELETED"
+	// ProjectSLAPolicyDeleted defines the EventType for when a project SLA policy has been deleted.
+	ProjectSLAPolicyDeleted EventType = "PROJECT_SLA_POLICY_DELETED"
+	// ProjectVersionDeleted defines the EventType for when a project version has been deleted.
+	ProjectVersionDeleted EventType = "PROJECT_VERSION_DELETED"
+	// ProjectBoardDeleted defines the EventType for when a project board has been deleted.
+	ProjectBoardDeleted EventType = "PROJECT_BOARD_DELETED"
 	// ProjectBoardSprintDeleted defines the EventType for when a project board sprint has been deleted.
 	ProjectBoardSprintDeleted EventType = "PROJECT_BOARD_SPRINT_DELETED"
 )
@@ -34,12 +48,33 @@ type IssueCommentDeletedPayload struct {
 	CommentID string `json:"commentId"`
 }
 
+// IssueScheduleDeletedPayload defines the payload of data for a project issue schedule deleted event.
+type IssueScheduleDeletedPayload struct {
+	UserID     string `json:"userId"`
+	ProjectID  string `json:"projectId"`
+	ScheduleID string `json:"scheduleId"`
+}
+
+// IssueWorklogDeletedPayload defines the payload of data for an issue worklog deleted event.
+type IssueWorklogDeletedPayload struct {
+	UserID    string `json:"userId"`
+	IssueID   string `json:"issueId"`
+	WorklogID string `json:"worklogId"`
+}
+
 // ProjectDeletedPayload defines the payload of data for a project deleted event.
 type ProjectDeletedPayload struct {
 	UserID    string `json:"userId"`
 	ProjectID string `json:"projectId"`
 }
 
+// ProjectBoardDeletedPayload defines the payload of data for a project board deleted event.
+type ProjectBoardDeletedPayload struct {
+	UserID    string `json:"userId"`
+	ProjectID string `json:"projectId"`
+	BoardID   string `json:"boardId"`
+}
+
 // ProjectBoardSprintDeletedPayload defines the payload of data for a project board sprint deleted event.
 type ProjectBoardSprintDeletedPayload struct {
 	UserID    string `json:"userId"`
@@ -47,3 +82,31 @@ type ProjectBoardSprintDeletedPayload struct {
 	BoardID   string `json:"boardId"`
 	SprintID  string `json:"sprintId"`
 }
+
+// ProjectComponentDeletedPayload defines the payload of data for a project component deleted event.
+type ProjectComponentDeletedPayload struct {
+	UserID      string `json:"userId"`
+	ProjectID   string `json:"projectId"`
+	ComponentID string `json:"componentId,omitempty"`
+}
+
+// ProjectIssueTemplateDeletedPayload defines the payload of data for a project issue template deleted event.
+type ProjectIssueTemplateDeletedPayload struct {
+	UserID          string `json:"userId"`
+	ProjectID       string `json:"projectId"`
+	IssueTemplateID string `json:"issueTemplateId,omitempty"`
+}
+
+// ProjectSLAPolicyDeletedPayload defines the payload of data for a project SLA policy deleted event.
+type ProjectSLAPolicyDeletedPayload struct {
+	UserID      string `json:"userId"`
+	ProjectID   string `json:"projectId"`
+	SLAPolicyID string `json:"slaPolicyId,omitempty"`
+}
+
+// ProjectVersionDeletedPayload defines the payload of data for a project version deleted event.
+type ProjectVersionDeletedPayload struct {
+	UserID    string `json:"userId"`
+	ProjectID string `json:"projectId"`
+	VersionID string `json:"versionId,omitempty"`
+}
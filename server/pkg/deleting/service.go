@@ -2,30 +2,75 @@ package deleting
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/njehyde/issue-tracker/pkg/http/ws"
 )
 
 // Service provides entity deletion operations
 type Service interface {
+	// DeleteBoardType attempts to delete a board type entity that no board is using.
+	DeleteBoardType(string) error
+	// DeleteCustomField attempts to delete a custom field entity, and its values.
+	DeleteCustomField(string) error
 	// DeleteIssue attempts to delete an issue entity.
 	DeleteIssue(*string, string) error
 	// DeleteIssueComment attempts to delete an issue comment entity.
 	DeleteIssueComment(*string, *string, *string) error
+	// DeleteIssueSchedule attempts to delete a project issue schedule entity.
+	DeleteIssueSchedule(*string, *string, *string) error
+	// DeleteIssueType attempts to delete an issue type entity, migrating its issues to another issue type.
+	DeleteIssueType(string, string) error
+	// DeleteIssueWorklog attempts to delete an issue worklog entity.
+	DeleteIssueWorklog(*string, *string, *string) error
+	// DeleteLabel attempts to delete a label entity, and remove it from the issues using it.
+	DeleteLabel(string) error
 	// DeleteProject attempts to delete a project entity.
 	DeleteProject(*string, string) error
+	// DeleteProjectComponent attempts to delete a project component entity.
+	DeleteProjectComponent(*string, *string, *string) error
+	// DeleteProjectIssueTemplate attempts to delete a project issue template entity.
+	DeleteProjectIssueTemplate(*string, *string, *string) error
+	// DeleteProjectSLAPolicy attempts to delete a project SLA policy entity.
+	DeleteProjectSLAPolicy(*string, *string, *string) error
+	// DeleteProjectVersion attempts to delete a project version entity.
+	DeleteProjectVersion(*string, *string, *string) error
+	// DeleteProjectBoard attempts to delete a project board entity.
+	DeleteProjectBoard(*string, *string, *string) error
 	// DeleteProjectBoardSprint attempts to project board sprint entity.
 	DeleteProjectBoardSprint(*string, *string, *string, *string) error
 }
 
 // Repository provides access to the deleting repository
 type Repository interface {
+	// DeleteBoardType attempts to delete a board type entity that no board is using from the repository.
+	DeleteBoardType(string) error
+	// DeleteCustomField attempts to delete a custom field entity, and its issue values, from the repository.
+	DeleteCustomField(string) error
 	// DeleteIssue attempts to delete an issue entity from the repository.
 	DeleteIssue(string) error
 	// DeleteIssueComment attempts to delete an issue comment entity from the repository.
 	DeleteIssueComment(*string, *string) error
+	// DeleteIssueSchedule attempts to delete a project issue schedule entity from the repository, leaving the issues it created.
+	DeleteIssueSchedule(*string, *string) error
+	// DeleteIssueType attempts to delete an issue type entity from the repository, after migrating its issues to another issue type.
+	DeleteIssueType(string, string) error
+	// DeleteIssueWorklog attempts to delete an issue worklog entity from the repository.
+	DeleteIssueWorklog(*string, *string, *string) error
+	// DeleteLabel attempts to delete a label entity from the repository, and remove it from the issues using it.
+	DeleteLabel(string) error
 	// DeleteProject attempts to delete a project entity from the repository.
 	DeleteProject(string) error
+	// DeleteProjectComponent attempts to delete a project component entity from the repository, and remove it from issues.
+	DeleteProjectComponent(*string, *string) error
+	// DeleteProjectIssueTemplate attempts to delete a project issue template entity from the repository.
+	DeleteProjectIssueTemplate(*string, *string) error
+	// DeleteProjectSLAPolicy attempts to delete a project SLA policy entity from the repository, and the breaches recorded against it.
+	DeleteProjectSLAPolicy(*string, *string) error
+	// DeleteProjectVersion attempts to delete a project version entity from the repository, and remove it from issues.
+	DeleteProjectVersion(*string, *string) error
+	// DeleteProjectBoard attempts to delete a board entity from the repository, without deleting its issues.
+	DeleteProjectBoard(*string, *string) error
 	// DeleteProjectBoardSprint attempts to delete a sprint entity from the repository.
 	DeleteProjectBoardSprint(*string, *string, *string) error
 }
@@ -40,6 +85,24 @@ func NewService(r Repository, hub *ws.Hub) Service {
 	return &service{r, hub}
 }
 
+func (s *service) DeleteBoardType(id string) error {
+	err := s.repo.DeleteBoardType(id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) DeleteCustomField(id string) error {
+	err := s.repo.DeleteCustomField(id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (s *service) DeleteIssue(userID *string, issueID string) error {
 	// TODO: Validation for DeleteIssue
 	err := s.repo.DeleteIssue(issueID)
@@ -73,6 +136,61 @@ func (s *service) DeleteIssueComment(userID *string, issueID *string, commentID
 	return nil
 }
 
+func (s *service) DeleteIssueSchedule(userID *string, projectID *string, scheduleID *string) error {
+	err := s.repo.DeleteIssueSchedule(projectID, scheduleID)
+	if err != nil {
+		return err
+	}
+
+	payload := IssueScheduleDeletedPayload{*userID, *projectID, *scheduleID}
+	err = s.broadcastEvent(IssueScheduleDeleted, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) DeleteIssueType(id string, migrateTo string) error {
+	if len(migrateTo) == 0 {
+		return fmt.Errorf("'migrateTo' is empty")
+	}
+	if migrateTo == id {
+		return fmt.Errorf("Issues cannot be migrated to the issue type being deleted")
+	}
+
+	err := s.repo.DeleteIssueType(id, migrateTo)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) DeleteIssueWorklog(userID *string, issueID *string, worklogID *string) error {
+	err := s.repo.DeleteIssueWorklog(issueID, worklogID, userID)
+	if err != nil {
+		return err
+	}
+
+	payload := IssueWorklogDeletedPayload{*userID, *issueID, *worklogID}
+	err = s.broadcastEvent(IssueWorklogDeleted, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) DeleteLabel(id string) error {
+	err := s.repo.DeleteLabel(id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (s *service) DeleteProject(userID *string, projectID string) error {
 	// TODO: Validation for DeleteProject
 	err := s.repo.DeleteProject(projectID)
@@ -89,6 +207,81 @@ func (s *service) DeleteProject(userID *string, projectID string) error {
 	return nil
 }
 
+func (s *service) DeleteProjectComponent(userID *string, projectID *string, componentID *string) error {
+	err := s.repo.DeleteProjectComponent(projectID, componentID)
+	if err != nil {
+		return err
+	}
+
+	payload := ProjectComponentDeletedPayload{*userID, *projectID, *componentID}
+	err = s.broadcastEvent(ProjectComponentDeleted, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) DeleteProjectIssueTemplate(userID *string, projectID *string, templateID *string) error {
+	err := s.repo.DeleteProjectIssueTemplate(projectID, templateID)
+	if err != nil {
+		return err
+	}
+
+	payload := ProjectIssueTemplateDeletedPayload{*userID, *projectID, *templateID}
+	err = s.broadcastEvent(ProjectIssueTemplateDeleted, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) DeleteProjectSLAPolicy(userID *string, projectID *string, policyID *string) error {
+	err := s.repo.DeleteProjectSLAPolicy(projectID, policyID)
+	if err != nil {
+		return err
+	}
+
+	payload := ProjectSLAPolicyDeletedPayload{*userID, *projectID, *policyID}
+	err = s.broadcastEvent(ProjectSLAPolicyDeleted, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) DeleteProjectVersion(userID *string, projectID *string, versionID *string) error {
+	err := s.repo.DeleteProjectVersion(projectID, versionID)
+	if err != nil {
+		return err
+	}
+
+	payload := ProjectVersionDeletedPayload{*userID, *projectID, *versionID}
+	err = s.broadcastEvent(ProjectVersionDeleted, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) DeleteProjectBoard(userID *string, projectID *string, boardID *string) error {
+	err := s.repo.DeleteProjectBoard(projectID, boardID)
+	if err != nil {
+		return err
+	}
+
+	payload := ProjectBoardDeletedPayload{*userID, *projectID, *boardID}
+	err = s.broadcastEvent(ProjectBoardDeleted, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (s *service) DeleteProjectBoardSprint(userID *string, projectID *string, boardID *string, sprintID *string) error {
 	// TODO: Validation for DeleteProjectBoardSprint
 	err := s.repo.DeleteProjectBoardSprint(projectID, boardID, sprintID)
@@ -3,11 +3,76 @@ package rest
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 
 	"github.com/gorilla/mux"
 	"github.com/njehyde/issue-tracker/pkg/adding"
 )
 
+func addBoardType(service adding.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var bt adding.BoardType
+
+		err := json.NewDecoder(r.Body).Decode(&bt)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.AddBoardType(&bt)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Board type added successfully", w)
+	}
+}
+
+func addBoardTypeFromBoard(service adding.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var bt adding.BoardTypeFromBoard
+
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		boardID := vars["boardId"]
+
+		err := json.NewDecoder(r.Body).Decode(&bt)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.AddBoardTypeFromBoard(&projectID, &boardID, &bt)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Board type added successfully", w)
+	}
+}
+
+func addCustomField(service adding.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var cf adding.CustomField
+
+		err := json.NewDecoder(r.Body).Decode(&cf)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.AddCustomField(&cf)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Custom field added successfully", w)
+	}
+}
+
 func addIssue(service adding.Service) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var i adding.Issue
@@ -63,6 +128,64 @@ func addIssueComment(service adding.Service) func(w http.ResponseWriter, r *http
 	}
 }
 
+func cloneIssue(service adding.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var c adding.IssueClone
+
+		vars := mux.Vars(r)
+		issueID := vars["issueId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = json.NewDecoder(r.Body).Decode(&c)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.CloneIssue(userID, &issueID, &c)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Issue cloned successfully", w)
+	}
+}
+
+func addIssueWorklog(service adding.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var iw adding.IssueWorklog
+
+		vars := mux.Vars(r)
+		issueID := vars["issueId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = json.NewDecoder(r.Body).Decode(&iw)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.AddIssueWorklog(userID, &issueID, &iw)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Work logged successfully", w)
+	}
+}
+
 func addIssueStatus(service adding.Service) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var is adding.IssueStatus
@@ -83,6 +206,26 @@ func addIssueStatus(service adding.Service) func(w http.ResponseWriter, r *http.
 	}
 }
 
+func addIssueType(service adding.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var it adding.IssueType
+
+		err := json.NewDecoder(r.Body).Decode(&it)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.AddIssueType(&it)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Issue type added successfully", w)
+	}
+}
+
 func addPriorityType(service adding.Service) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var pt adding.PriorityType
@@ -129,6 +272,233 @@ func addProject(service adding.Service) func(w http.ResponseWriter, r *http.Requ
 	}
 }
 
+func addProjectComponent(service adding.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var c adding.Component
+
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = json.NewDecoder(r.Body).Decode(&c)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.AddProjectComponent(userID, &projectID, &c)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Component added successfully", w)
+	}
+}
+
+func addProjectIssueTemplate(service adding.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var t adding.IssueTemplate
+
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = json.NewDecoder(r.Body).Decode(&t)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.AddProjectIssueTemplate(userID, &projectID, &t)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Issue template added successfully", w)
+	}
+}
+
+func addProjectSLAPolicy(service adding.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var p adding.SLAPolicy
+
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = json.NewDecoder(r.Body).Decode(&p)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.AddProjectSLAPolicy(userID, &projectID, &p)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("SLA policy added successfully", w)
+	}
+}
+
+// maxImportFileSize is the most bytes of a CSV file upload that are held in memory, the rest going to temporary files.
+const maxImportFileSize = 32 << 20
+
+func importProjectIssuesFromCSV(service adding.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var i adding.IssueImport
+
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = r.ParseMultipartForm(maxImportFileSize)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = json.Unmarshal([]byte(r.FormValue("mapping")), &i.Mapping)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		if dryRun := r.FormValue("dryRun"); len(dryRun) > 0 {
+			i.DryRun, err = strconv.ParseBool(dryRun)
+			if err != nil {
+				handleRequestError(err, w)
+				return
+			}
+		}
+
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+		defer file.Close()
+
+		result, err := service.ImportIssues(userID, &projectID, &i, file)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendResultResponse(result, w)
+	}
+}
+
+func addProjectIssueSchedule(service adding.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var s adding.IssueSchedule
+
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = json.NewDecoder(r.Body).Decode(&s)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.AddIssueSchedule(userID, &projectID, &s)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Issue schedule added successfully", w)
+	}
+}
+
+func addProjectBoard(service adding.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var b adding.Board
+
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = json.NewDecoder(r.Body).Decode(&b)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.AddProjectBoard(userID, &projectID, &b)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Board added successfully", w)
+	}
+}
+
+func addProjectVersion(service adding.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var v adding.Version
+
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = json.NewDecoder(r.Body).Decode(&v)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.AddProjectVersion(userID, &projectID, &v)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Version added successfully", w)
+	}
+}
+
 func addProjectBoardSprint(service adding.Service) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
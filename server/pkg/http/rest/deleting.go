@@ -7,6 +7,36 @@ import (
 	"github.com/njehyde/issue-tracker/pkg/deleting"
 )
 
+func deleteBoardType(service deleting.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		id := vars["id"]
+
+		err := service.DeleteBoardType(id)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Board type deleted successfully", w)
+	}
+}
+
+func deleteCustomField(service deleting.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		id := vars["id"]
+
+		err := service.DeleteCustomField(id)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Custom field deleted successfully", w)
+	}
+}
+
 func deleteIssue(service deleting.Service) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
@@ -50,6 +80,59 @@ func deleteIssueComment(service deleting.Service) func(w http.ResponseWriter, r
 	}
 }
 
+func deleteIssueWorklog(service deleting.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		issueID := vars["issueId"]
+		worklogID := vars["worklogId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.DeleteIssueWorklog(userID, &issueID, &worklogID)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Issue worklog deleted successfully", w)
+	}
+}
+
+func deleteIssueType(service deleting.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		id := vars["id"]
+		migrateTo := r.FormValue("migrateTo")
+
+		err := service.DeleteIssueType(id, migrateTo)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Issue type deleted successfully", w)
+	}
+}
+
+func deleteLabel(service deleting.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		id := vars["id"]
+
+		err := service.DeleteLabel(id)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Label deleted successfully", w)
+	}
+}
+
 func deleteProject(service deleting.Service) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
@@ -71,6 +154,138 @@ func deleteProject(service deleting.Service) func(w http.ResponseWriter, r *http
 	}
 }
 
+func deleteProjectComponent(service deleting.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		componentID := vars["componentId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.DeleteProjectComponent(userID, &projectID, &componentID)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Component deleted successfully", w)
+	}
+}
+
+func deleteProjectIssueTemplate(service deleting.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		templateID := vars["issueTemplateId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.DeleteProjectIssueTemplate(userID, &projectID, &templateID)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Issue template deleted successfully", w)
+	}
+}
+
+func deleteProjectSLAPolicy(service deleting.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		policyID := vars["slaPolicyId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.DeleteProjectSLAPolicy(userID, &projectID, &policyID)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("SLA policy deleted successfully", w)
+	}
+}
+
+func deleteProjectIssueSchedule(service deleting.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		scheduleID := vars["issueScheduleId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.DeleteIssueSchedule(userID, &projectID, &scheduleID)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Issue schedule deleted successfully", w)
+	}
+}
+
+func deleteProjectBoard(service deleting.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		boardID := vars["boardId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.DeleteProjectBoard(userID, &projectID, &boardID)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Board deleted successfully", w)
+	}
+}
+
+func deleteProjectVersion(service deleting.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		versionID := vars["versionId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.DeleteProjectVersion(userID, &projectID, &versionID)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Version deleted successfully", w)
+	}
+}
+
 func deleteProjectBoardSprint(service deleting.Service) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
@@ -19,6 +19,7 @@ import (
 	"github.com/njehyde/issue-tracker/pkg/events"
 	"github.com/njehyde/issue-tracker/pkg/http/ws"
 	"github.com/njehyde/issue-tracker/pkg/listing"
+	"github.com/njehyde/issue-tracker/pkg/reporting"
 	"github.com/njehyde/issue-tracker/pkg/updating"
 )
 
@@ -36,6 +37,7 @@ func Handler(
 	u updating.Service,
 	d deleting.Service,
 	c checking.Service,
+	rp reporting.Service,
 	hub *ws.Hub,
 	eb *events.EventBus) http.Handler {
 
@@ -50,24 +52,50 @@ func Handler(
 	r.HandleFunc("/health", checkHealth(c)).Methods("GET")
 
 	r.HandleFunc("/boardTypes", getBoardTypes(l)).Methods("GET")
+	r.HandleFunc("/boardTypes", addBoardType(a)).Methods("POST")
+	r.HandleFunc("/boardTypes/{id:[A-Z0-9_]+}", getBoardType(l)).Methods("GET")
+	r.HandleFunc("/boardTypes/{id:[A-Z0-9_]+}", updateBoardType(u)).Methods("PUT")
+	r.HandleFunc("/boardTypes/{id:[A-Z0-9_]+}", deleteBoardType(d)).Methods("DELETE")
 	r.HandleFunc("/categories", getCategories(l)).Methods("GET")
+	r.HandleFunc("/customFields", getCustomFields(l)).Methods("GET")
+	r.HandleFunc("/customFields", addCustomField(a)).Methods("POST")
+	r.HandleFunc("/customFields/{id:[a-z0-9]+}", updateCustomField(u)).Methods("PUT")
+	r.HandleFunc("/customFields/{id:[a-z0-9]+}", deleteCustomField(d)).Methods("DELETE")
 	r.HandleFunc("/issues", getIssues(l)).Methods("GET")
+	r.HandleFunc("/issues/export", exportIssues(l)).Methods("GET")
 	r.HandleFunc("/issues/{id:[a-z0-9]+}", getIssue(l)).Methods("GET")
+	r.HandleFunc("/issues/{ref:[A-Za-z0-9]+-[0-9]+}", getIssueByRef(l)).Methods("GET")
+	r.HandleFunc("/issues/{id:[a-z0-9]+}/cycleTime", getIssueCycleTime(rp)).Methods("GET")
 	r.HandleFunc("/issues", addIssue(a)).Methods("POST")
 	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/issues/{issueId:[a-z0-9]+}", updateIssue(u)).Methods("PUT")
 	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/issue/ordinals", updateIssueOrdinals(u)).Methods("PUT")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/issues/{issueId:[a-z0-9]+}/move", moveIssue(u)).Methods("PUT")
+	r.HandleFunc("/issues/{issueId:[a-z0-9]+}/project", transferIssue(u)).Methods("PUT")
+	r.HandleFunc("/issues/{issueId:[a-z0-9]+}/clone", cloneIssue(a)).Methods("POST")
 	r.HandleFunc("/issues/{id:[a-z0-9]+}", deleteIssue(d)).Methods("DELETE")
 	r.HandleFunc("/issues/{issueId:[a-z0-9]+}/comments", getIssueComments(l)).Methods("GET")
 	r.HandleFunc("/issues/{issueId:[a-z0-9]+}/comments", addIssueComment(a)).Methods("POST")
 	r.HandleFunc("/issues/{issueId:[a-z0-9]+}/comments/{commentId:[a-z0-9]+}", updateIssueComment(u)).Methods("PUT")
 	r.HandleFunc("/issues/{issueId:[a-z0-9]+}/comments/{commentId:[a-z0-9]+}", deleteIssueComment(d)).Methods("DELETE")
+	r.HandleFunc("/issues/{issueId:[a-z0-9]+}/worklogs", getIssueWorklogs(l)).Methods("GET")
+	r.HandleFunc("/issues/{issueId:[a-z0-9]+}/worklogs", addIssueWorklog(a)).Methods("POST")
+	r.HandleFunc("/issues/{issueId:[a-z0-9]+}/worklogs/{worklogId:[a-z0-9]+}", updateIssueWorklog(u)).Methods("PUT")
+	r.HandleFunc("/issues/{issueId:[a-z0-9]+}/worklogs/{worklogId:[a-z0-9]+}", deleteIssueWorklog(d)).Methods("DELETE")
 	r.HandleFunc("/issueStatuses", getIssueStatuses(l)).Methods("GET")
 	r.HandleFunc("/issueStatuses", addIssueStatus(a)).Methods("POST")
 	r.HandleFunc("/issueStatuses/{id:[A-Z_]+}", updateIssueStatus(u)).Methods("PUT")
 	r.HandleFunc("/issueStatuses/{id:[A-Z_]+}/increase", increaseIssueStatus(u)).Methods("PUT")
 	r.HandleFunc("/issueStatuses/{id:[A-Z_]+}/decrease", decreaseIssueStatus(u)).Methods("PUT")
 	r.HandleFunc("/issueTypes", getIssueTypes(l)).Methods("GET")
+	r.HandleFunc("/issueTypes", addIssueType(a)).Methods("POST")
+	r.HandleFunc("/issueTypes/{id:[A-Z0-9_-]+}", updateIssueType(u)).Methods("PUT")
+	r.HandleFunc("/issueTypes/{id:[A-Z0-9_-]+}", deleteIssueType(d)).Queries("migrateTo", "{migrateTo:[A-Z0-9_-]+}").Methods("DELETE")
 	r.HandleFunc("/labels", getLabels(l)).Methods("GET")
+	r.HandleFunc("/labels/{id:[a-z0-9]+}", updateLabel(u)).Methods("PUT")
+	r.HandleFunc("/labels/{id:[a-z0-9]+}/merge", mergeLabels(u)).Methods("PUT")
+	r.HandleFunc("/labels/{id:[a-z0-9]+}", deleteLabel(d)).Methods("DELETE")
+	r.HandleFunc("/notifications", getNotifications(l)).Methods("GET")
+	r.HandleFunc("/notifications/{notificationId:[a-z0-9]+}/read", readNotification(u)).Methods("PUT")
 	r.HandleFunc("/priorityTypes", getPriorityTypes(l)).Methods("GET")
 	r.HandleFunc("/priorityTypes", addPriorityType(a)).Methods("POST")
 	r.HandleFunc("/priorityTypes/{id:[A-Z_]+}", updatePriorityType(u)).Methods("PUT")
@@ -79,18 +107,71 @@ func Handler(
 	r.HandleFunc("/projects/{id:[a-z0-9]+}", updateProject(u)).Methods("PUT")
 	r.HandleFunc("/projects/{id:[a-z0-9]+}", deleteProject(d)).Methods("DELETE")
 	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/issues", getProjectIssues(l)).Methods("GET")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/issues/export", exportProjectIssues(l)).Methods("GET")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/controlChart", getProjectControlChart(rp)).Methods("GET")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/components", getProjectComponents(l)).Methods("GET")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/components", addProjectComponent(a)).Methods("POST")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/components/{componentId:[a-z0-9]+}", updateProjectComponent(u)).Methods("PUT")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/components/{componentId:[a-z0-9]+}", deleteProjectComponent(d)).Methods("DELETE")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/slaPolicies", getProjectSLAPolicies(l)).Methods("GET")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/slaPolicies", addProjectSLAPolicy(a)).Methods("POST")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/slaPolicies/{slaPolicyId:[a-z0-9]+}", updateProjectSLAPolicy(u)).Methods("PUT")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/slaPolicies/{slaPolicyId:[a-z0-9]+}", deleteProjectSLAPolicy(d)).Methods("DELETE")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/import/csv", importProjectIssuesFromCSV(a)).Methods("POST")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/imports/{importId:[a-z0-9]+}", getProjectIssueImport(l)).Methods("GET")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/issueSchedules", getProjectIssueSchedules(l)).Methods("GET")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/issueSchedules", addProjectIssueSchedule(a)).Methods("POST")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/issueSchedules/{issueScheduleId:[a-z0-9]+}", updateProjectIssueSchedule(u)).Methods("PUT")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/issueSchedules/{issueScheduleId:[a-z0-9]+}", deleteProjectIssueSchedule(d)).Methods("DELETE")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/issueTemplates", getProjectIssueTemplates(l)).Methods("GET")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/issueTemplates", addProjectIssueTemplate(a)).Methods("POST")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/issueTemplates/{issueTemplateId:[a-z0-9]+}", updateProjectIssueTemplate(u)).Methods("PUT")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/issueTemplates/{issueTemplateId:[a-z0-9]+}", deleteProjectIssueTemplate(d)).Methods("DELETE")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/issueTypes", getProjectIssueTypes(l)).Methods("GET")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/issueTypes", updateProjectIssueTypeScheme(u)).Methods("PUT")
 	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/backlog/issues", getProjectBacklogIssues(l)).Methods("GET")
 	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/backlog/issues/{issueId:[a-z0-9]+}/top", sendIssueToTopOfBacklog(u)).Methods("PUT")
 	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/backlog/issues/{issueId:[a-z0-9]+}/bottom", sendIssueToBottomOfBacklog(u)).Methods("PUT")
 	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/sprints/{sprintId:[a-z0-9]+}/issues", getProjectSprintIssues(l)).Methods("GET")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/sprints/{sprintId:[a-z0-9]+}/burndown", getProjectSprintBurndown(rp)).Methods("GET")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/sprints/{sprintId:[a-z0-9]+}/timeTracking", getProjectSprintTimeTracking(rp)).Methods("GET")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/epics/{epicId:[a-z0-9]+}/timeTracking", getProjectEpicTimeTracking(rp)).Methods("GET")
 	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/sprints/{sprintId:[a-z0-9]+}/issues/{issueId:[a-z0-9]+}", sendIssueToSprint(u)).Methods("PUT")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/boards", getProjectBoards(l)).Methods("GET")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/boards", addProjectBoard(a)).Methods("POST")
 	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/boards/{boardId:[a-z0-9]+}", getProjectBoard(l)).Methods("GET")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/boards/{boardId:[a-z0-9]+}", updateProjectBoard(u)).Methods("PUT")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/boards/{boardId:[a-z0-9]+}", deleteProjectBoard(d)).Methods("DELETE")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/boards/{boardId:[a-z0-9]+}/default", updateProjectDefaultBoard(u)).Methods("PUT")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/boards/{boardId:[a-z0-9]+}/boardType", addBoardTypeFromBoard(a)).Methods("POST")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/boards/{boardId:[a-z0-9]+}/issues", getProjectBoardIssues(l)).Methods("GET")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/boards/{boardId:[a-z0-9]+}/swimlanes", updateProjectBoardSwimlanes(u)).Methods("PUT")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/boards/{boardId:[a-z0-9]+}/columns", addProjectBoardColumn(u)).Methods("POST")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/boards/{boardId:[a-z0-9]+}/columns/{ordinal:[0-9]+}", updateProjectBoardColumn(u)).Methods("PUT")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/boards/{boardId:[a-z0-9]+}/columns/{ordinal:[0-9]+}", removeProjectBoardColumn(u)).Methods("DELETE")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/boards/{boardId:[a-z0-9]+}/columns/{ordinal:[0-9]+}/move", moveProjectBoardColumn(u)).Methods("PUT")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/boards/{boardId:[a-z0-9]+}/columns/{ordinal:[0-9]+}/split", splitProjectBoardColumn(u)).Methods("PUT")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/boards/{boardId:[a-z0-9]+}/columns/{ordinal:[0-9]+}/wipLimit", updateProjectBoardColumnWIPLimit(u)).Methods("PUT")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/boards/{boardId:[a-z0-9]+}/statuses/{statusId:[A-Z_]+}", updateProjectBoardStatusMapping(u)).Methods("PUT")
 	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/boards/{boardId:[a-z0-9]+}/sprints", addProjectBoardSprint(a)).Methods("POST")
 	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/boards/{boardId:[a-z0-9]+}/sprints/{sprintId:[a-z0-9]+}", updateProjectBoardSprint(u)).Methods("PUT")
 	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/boards/{boardId:[a-z0-9]+}/sprints/{sprintId:[a-z0-9]+}", deleteProjectBoardSprint(d)).Methods("DELETE")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/boards/{boardId:[a-z0-9]+}/sprints/{sprintId:[a-z0-9]+}/start", startProjectBoardSprint(u)).Methods("PUT")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/boards/{boardId:[a-z0-9]+}/sprints/{sprintId:[a-z0-9]+}/complete", completeProjectBoardSprint(u)).Methods("PUT")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/boards/{boardId:[a-z0-9]+}/cfd", getProjectBoardCumulativeFlow(rp)).Methods("GET")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/boards/{boardId:[a-z0-9]+}/velocity", getProjectBoardVelocity(rp)).Methods("GET")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/versions", getProjectVersions(l)).Methods("GET")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/versions", addProjectVersion(a)).Methods("POST")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/versions/{versionId:[a-z0-9]+}", updateProjectVersion(u)).Methods("PUT")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/versions/{versionId:[a-z0-9]+}", deleteProjectVersion(d)).Methods("DELETE")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/versions/{versionId:[a-z0-9]+}/release", releaseProjectVersion(u)).Methods("PUT")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/versions/{versionId:[a-z0-9]+}/unrelease", unreleaseProjectVersion(u)).Methods("PUT")
+	r.HandleFunc("/projects/{projectId:[a-z0-9]+}/versions/{versionId:[a-z0-9]+}/notes", getProjectVersionNotes(l)).Methods("GET")
 	r.HandleFunc("/projectTypes", getProjectTypes(l)).Methods("GET")
 	r.HandleFunc("/workflows", getWorkflows(l)).Methods("GET")
+	r.HandleFunc("/workflows/{id:[0-9]+}/transitions", updateWorkflowTransitions(u)).Methods("PUT")
 	r.HandleFunc("/users", getUsers(l)).Methods("GET")
+	r.HandleFunc("/users/{userId:[a-z0-9]+}/timesheet", getUserTimesheet(rp)).Methods("GET")
 	// r.HandleFunc("/users", addUser(a)).Methods("POST")
 	// r.HandleFunc("/users", updateUser(a)).Methods("PUT")
 	// r.HandleFunc("/users", deleteUser(a)).Methods("DELETE")
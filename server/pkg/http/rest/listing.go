@@ -1,14 +1,54 @@
 package rest
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
 	"github.com/njehyde/issue-tracker/libraries/slog"
 	"github.com/njehyde/issue-tracker/pkg/listing"
 )
 
+func exportIssues(service listing.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID := ""
+
+		sendIssueExport(service, &projectID, w, r)
+	}
+}
+
+func exportProjectIssues(service listing.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+
+		sendIssueExport(service, &projectID, w, r)
+	}
+}
+
+func getBoardType(service listing.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		id := vars["id"]
+
+		bt, err := service.GetBoardType(id)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		type GetBoardTypeResult struct {
+			BoardType listing.BoardType `json:"boardType"`
+		}
+
+		result := GetBoardTypeResult{BoardType: *bt}
+		sendResultResponse(result, w)
+	}
+}
+
 func getBoardTypes(service listing.Service) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		boardTypes, err := service.GetBoardTypes()
@@ -43,6 +83,26 @@ func getCategories(service listing.Service) func(w http.ResponseWriter, r *http.
 	}
 }
 
+func getCustomFields(service listing.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID := r.FormValue("projectId")
+		issueType := r.FormValue("issueType")
+
+		customFields, err := service.GetCustomFields(&projectID, &issueType)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		type GetCustomFieldsResult struct {
+			CustomFields []listing.CustomField `json:"customFields"`
+		}
+
+		result := GetCustomFieldsResult{CustomFields: customFields}
+		sendResultResponse(result, w)
+	}
+}
+
 func getIssue(service listing.Service) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
@@ -63,6 +123,32 @@ func getIssue(service listing.Service) func(w http.ResponseWriter, r *http.Reque
 	}
 }
 
+func getIssueByRef(service listing.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		ref := vars["ref"]
+
+		issue, err := service.GetIssueByRef(ref)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		// Issues moved to another project redirect from their old reference, relative to this path
+		if issue.ProjectRef != ref {
+			http.Redirect(w, r, issue.ProjectRef, http.StatusMovedPermanently)
+			return
+		}
+
+		type GetIssueResult struct {
+			Issue listing.Issue `json:"issue"`
+		}
+
+		result := GetIssueResult{Issue: issue}
+		sendResultResponse(result, w)
+	}
+}
+
 func getIssues(service listing.Service) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		v := r.URL.Query()
@@ -79,7 +165,13 @@ func getIssues(service listing.Service) func(w http.ResponseWriter, r *http.Requ
 		}
 		pagination := listing.Pagination{PageSize: i, Cursor: cursor}
 
-		issues, count, err := service.GetIssues(&pagination)
+		query, err := getIssueQuery(v)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		issues, count, err := service.GetIssues(&pagination, &query)
 		if err != nil {
 			handleServiceError(err, w)
 			return
@@ -132,6 +224,84 @@ func getIssueComments(service listing.Service) func(w http.ResponseWriter, r *ht
 	}
 }
 
+func getIssueWorklogs(service listing.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		issueID := vars["issueId"]
+
+		v := r.URL.Query()
+		pageSize := v.Get("pageSize")
+		cursor := v.Get("cursor")
+
+		if len(pageSize) == 0 {
+			pageSize = "10"
+		}
+
+		i, err := strconv.Atoi(pageSize)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+		pagination := listing.Pagination{PageSize: i, Cursor: cursor}
+
+		worklogs, count, err := service.GetIssueWorklogs(&issueID, &pagination)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		type GetIssueWorklogsResult struct {
+			Worklogs []listing.IssueWorklog `json:"worklogs"`
+			Metadata listing.Metadata       `json:"metadata"`
+		}
+
+		metadata := listing.Metadata{Pagination: &pagination, Count: count}
+		result := GetIssueWorklogsResult{Worklogs: worklogs, Metadata: metadata}
+		sendResultResponse(result, w)
+	}
+}
+
+func getNotifications(service listing.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		v := r.URL.Query()
+		pageSize := v.Get("pageSize")
+		cursor := v.Get("cursor")
+		unread := v.Get("unread") == "true"
+
+		if len(pageSize) == 0 {
+			pageSize = "10"
+		}
+
+		i, err := strconv.Atoi(pageSize)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+		pagination := listing.Pagination{PageSize: i, Cursor: cursor}
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		notifications, count, err := service.GetNotifications(userID, unread, &pagination)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		type GetNotificationsResult struct {
+			Notifications []listing.Notification `json:"notifications"`
+			Metadata      listing.Metadata       `json:"metadata"`
+		}
+
+		metadata := listing.Metadata{Pagination: &pagination, Count: count}
+		result := GetNotificationsResult{Notifications: notifications, Metadata: metadata}
+		sendResultResponse(result, w)
+	}
+}
+
 func getIssueStatuses(service listing.Service) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		term := r.FormValue("term")
@@ -282,6 +452,149 @@ func getProjectBoard(service listing.Service) func(w http.ResponseWriter, r *htt
 	}
 }
 
+func getProjectBoardIssues(service listing.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		boardID := vars["boardId"]
+
+		bi, err := service.GetProjectBoardIssues(&projectID, &boardID)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		type GetProjectBoardIssuesResult struct {
+			BoardIssues listing.BoardIssues `json:"boardIssues"`
+		}
+
+		result := GetProjectBoardIssuesResult{BoardIssues: *bi}
+		sendResultResponse(result, w)
+	}
+}
+
+func getProjectBoards(service listing.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+
+		boards, err := service.GetProjectBoards(&projectID)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		type GetProjectBoardsResult struct {
+			Boards []listing.Board `json:"boards"`
+		}
+
+		result := GetProjectBoardsResult{Boards: boards}
+		sendResultResponse(result, w)
+	}
+}
+
+func getProjectComponents(service listing.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+
+		components, err := service.GetProjectComponents(&projectID)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		type GetProjectComponentsResult struct {
+			Components []listing.Component `json:"components"`
+		}
+
+		result := GetProjectComponentsResult{Components: components}
+		sendResultResponse(result, w)
+	}
+}
+
+func getProjectIssueTemplates(service listing.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		issueType := r.URL.Query().Get("issueType")
+
+		templates, err := service.GetProjectIssueTemplates(&projectID, issueType)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		type GetProjectIssueTemplatesResult struct {
+			IssueTemplates []listing.IssueTemplate `json:"issueTemplates"`
+		}
+
+		result := GetProjectIssueTemplatesResult{IssueTemplates: templates}
+		sendResultResponse(result, w)
+	}
+}
+
+func getProjectSLAPolicies(service listing.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+
+		policies, err := service.GetProjectSLAPolicies(&projectID)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		type GetProjectSLAPoliciesResult struct {
+			SLAPolicies []listing.SLAPolicy `json:"slaPolicies"`
+		}
+
+		result := GetProjectSLAPoliciesResult{SLAPolicies: policies}
+		sendResultResponse(result, w)
+	}
+}
+
+func getProjectIssueImport(service listing.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		importID := vars["importId"]
+
+		i, err := service.GetProjectIssueImport(&projectID, &importID)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		type GetProjectIssueImportResult struct {
+			IssueImport *listing.IssueImport `json:"issueImport"`
+		}
+
+		result := GetProjectIssueImportResult{IssueImport: i}
+		sendResultResponse(result, w)
+	}
+}
+
+func getProjectIssueSchedules(service listing.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+
+		schedules, err := service.GetProjectIssueSchedules(&projectID)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		type GetProjectIssueSchedulesResult struct {
+			IssueSchedules []listing.IssueSchedule `json:"issueSchedules"`
+		}
+
+		result := GetProjectIssueSchedulesResult{IssueSchedules: schedules}
+		sendResultResponse(result, w)
+	}
+}
+
 func getProjectIssues(service listing.Service) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
@@ -302,7 +615,13 @@ func getProjectIssues(service listing.Service) func(w http.ResponseWriter, r *ht
 		}
 		pagination := listing.Pagination{PageSize: i, Cursor: cursor}
 
-		issues, count, err := service.GetProjectIssues(&projectID, &pagination)
+		query, err := getIssueQuery(v)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		issues, count, err := service.GetProjectIssues(&projectID, &pagination, &query)
 		if err != nil {
 			handleServiceError(err, w)
 			return
@@ -323,6 +642,26 @@ func getProjectIssues(service listing.Service) func(w http.ResponseWriter, r *ht
 	}
 }
 
+func getProjectIssueTypes(service listing.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+
+		issueTypes, err := service.GetProjectIssueTypes(&projectID)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		type GetProjectIssueTypesResult struct {
+			IssueTypes []listing.IssueType `json:"issueTypes"`
+		}
+
+		result := GetProjectIssueTypesResult{IssueTypes: issueTypes}
+		sendResultResponse(result, w)
+	}
+}
+
 func getProjects(service listing.Service) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		v := r.URL.Query()
@@ -415,6 +754,53 @@ func getProjectTypes(service listing.Service) func(w http.ResponseWriter, r *htt
 	}
 }
 
+func getProjectVersionNotes(service listing.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		versionID := vars["versionId"]
+
+		notes, err := service.GetProjectVersionNotes(&projectID, &versionID)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		if r.FormValue("format") == "markdown" {
+			w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+			w.Write([]byte(notes.Markdown))
+			return
+		}
+
+		type GetProjectVersionNotesResult struct {
+			ReleaseNotes *listing.ReleaseNotes `json:"releaseNotes"`
+		}
+
+		result := GetProjectVersionNotesResult{ReleaseNotes: notes}
+		sendResultResponse(result, w)
+	}
+}
+
+func getProjectVersions(service listing.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+
+		versions, err := service.GetProjectVersions(&projectID)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		type GetProjectVersionsResult struct {
+			Versions []listing.Version `json:"versions"`
+		}
+
+		result := GetProjectVersionsResult{Versions: versions}
+		sendResultResponse(result, w)
+	}
+}
+
 func getUsers(service listing.Service) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		term := r.FormValue("term")
@@ -450,3 +836,88 @@ func getWorkflows(service listing.Service) func(w http.ResponseWriter, r *http.R
 		sendResultResponse(result, w)
 	}
 }
+
+// getIssueQuery reads the issue query from the query string of a request, for example
+// "?status=DONE&cf.{customFieldId}=production&sort=cf.{customFieldId}&order=-1".
+func getIssueQuery(v url.Values) (q listing.IssueQuery, err error) {
+	for field, values := range v {
+		switch field {
+		case "pageSize", "cursor", "format", "columns":
+		case "sort":
+			q.Sort = v.Get("sort")
+		case "order":
+			order, err := strconv.ParseInt(v.Get("order"), 10, 32)
+			if err != nil {
+				return q, err
+			}
+			q.Order = int32(order)
+		default:
+			for _, value := range values {
+				q.Filters = append(q.Filters, listing.IssueFilter{Field: field, Value: value})
+			}
+		}
+	}
+
+	return q, nil
+}
+
+// exportContentTypes are the content types of the issue export formats.
+var exportContentTypes = map[string]string{
+	listing.ExportCSV:    "text/csv; charset=utf-8",
+	listing.ExportJSON:   "application/json",
+	listing.ExportNDJSON: "application/x-ndjson",
+}
+
+// exportResponseWriter holds back the headers of an issue export until its first bytes are written, so that an export
+// that fails before then can still send an error response. Each write is flushed to the client as it is made.
+type exportResponseWriter struct {
+	w       http.ResponseWriter
+	export  *listing.IssueExport
+	written bool
+}
+
+func (ew *exportResponseWriter) Write(b []byte) (int, error) {
+	if !ew.written {
+		ew.written = true
+		ew.w.Header().Set("Content-Type", exportContentTypes[ew.export.Format])
+		ew.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"issues.%v\"", ew.export.Format))
+	}
+
+	n, err := ew.w.Write(b)
+	if f, ok := ew.w.(http.Flusher); ok {
+		f.Flush()
+	}
+
+	return n, err
+}
+
+// sendIssueExport streams the issues matching the query string of a request, for example
+// "?format=ndjson&columns=projectRef,summary,status,cf.{customFieldId}&status=DONE". An export that fails once it has
+// started can only be cut short.
+func sendIssueExport(service listing.Service, projectID *string, w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query()
+
+	query, err := getIssueQuery(v)
+	if err != nil {
+		handleRequestError(err, w)
+		return
+	}
+
+	e := listing.IssueExport{Format: strings.ToLower(v.Get("format")), Query: query}
+	for _, c := range strings.Split(v.Get("columns"), ",") {
+		if c = strings.TrimSpace(c); len(c) > 0 {
+			e.Columns = append(e.Columns, c)
+		}
+	}
+
+	ew := exportResponseWriter{w: w, export: &e}
+
+	err = service.ExportIssues(projectID, &e, &ew)
+	if err != nil && ew.written {
+		slog.Errorf("Issue export failed after it started: %v", err)
+		return
+	}
+	if err != nil {
+		handleServiceError(err, w)
+	}
+}
@@ -0,0 +1,270 @@
+package rest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/njehyde/issue-tracker/pkg/reporting"
+)
+
+// reportDateLayout is the layout of the date range query params of reports.
+const reportDateLayout = "2006-01-02"
+
+func getIssueCycleTime(service reporting.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		id := vars["id"]
+
+		ct, err := service.GetIssueCycleTime(&id)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		type GetIssueCycleTimeResult struct {
+			CycleTime *reporting.IssueCycleTime `json:"cycleTime"`
+		}
+
+		result := GetIssueCycleTimeResult{CycleTime: ct}
+		sendResultResponse(result, w)
+	}
+}
+
+func getProjectBoardCumulativeFlow(service reporting.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		boardID := vars["boardId"]
+
+		v := r.URL.Query()
+
+		q := reporting.CumulativeFlowQuery{To: time.Now()}
+		q.From = q.To.AddDate(0, 0, -30)
+
+		var err error
+		if from := v.Get("from"); len(from) > 0 {
+			q.From, err = time.Parse(reportDateLayout, from)
+			if err != nil {
+				handleRequestError(err, w)
+				return
+			}
+		}
+		if to := v.Get("to"); len(to) > 0 {
+			q.To, err = time.Parse(reportDateLayout, to)
+			if err != nil {
+				handleRequestError(err, w)
+				return
+			}
+		}
+
+		cf, err := service.GetProjectBoardCumulativeFlow(&projectID, &boardID, &q)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		type GetProjectBoardCumulativeFlowResult struct {
+			CumulativeFlow *reporting.CumulativeFlow `json:"cumulativeFlow"`
+		}
+
+		result := GetProjectBoardCumulativeFlowResult{CumulativeFlow: cf}
+		sendResultResponse(result, w)
+	}
+}
+
+func getProjectBoardVelocity(service reporting.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		boardID := vars["boardId"]
+
+		v := r.URL.Query()
+		sprints := v.Get("sprints")
+		breakdown := v.Get("breakdown")
+
+		if len(sprints) == 0 {
+			sprints = "7"
+		}
+
+		n, err := strconv.Atoi(sprints)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		q := reporting.VelocityQuery{Sprints: n}
+		if len(breakdown) > 0 {
+			q.Breakdowns = strings.Split(breakdown, ",")
+		}
+
+		velocity, err := service.GetProjectBoardVelocity(&projectID, &boardID, &q)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		type GetProjectBoardVelocityResult struct {
+			Velocity *reporting.Velocity `json:"velocity"`
+		}
+
+		result := GetProjectBoardVelocityResult{Velocity: velocity}
+		sendResultResponse(result, w)
+	}
+}
+
+func getProjectControlChart(service reporting.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+
+		v := r.URL.Query()
+
+		q := reporting.ControlChartQuery{To: time.Now()}
+		q.From = q.To.AddDate(0, 0, -90)
+
+		var err error
+		if from := v.Get("from"); len(from) > 0 {
+			q.From, err = time.Parse(reportDateLayout, from)
+			if err != nil {
+				handleRequestError(err, w)
+				return
+			}
+		}
+		if to := v.Get("to"); len(to) > 0 {
+			q.To, err = time.Parse(reportDateLayout, to)
+			if err != nil {
+				handleRequestError(err, w)
+				return
+			}
+			q.To = q.To.Add(24*time.Hour - time.Nanosecond)
+		}
+		if types := v.Get("type"); len(types) > 0 {
+			q.Types = strings.Split(types, ",")
+		}
+		if labels := v.Get("label"); len(labels) > 0 {
+			q.Labels = strings.Split(labels, ",")
+		}
+
+		cc, err := service.GetProjectControlChart(&projectID, &q)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		type GetProjectControlChartResult struct {
+			ControlChart *reporting.ControlChart `json:"controlChart"`
+		}
+
+		result := GetProjectControlChartResult{ControlChart: cc}
+		sendResultResponse(result, w)
+	}
+}
+
+func getProjectSprintBurndown(service reporting.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		sprintID := vars["sprintId"]
+
+		mode := r.FormValue("mode")
+
+		burndown, err := service.GetProjectSprintBurndown(&projectID, &sprintID, mode)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		type GetProjectSprintBurndownResult struct {
+			Burndown *reporting.Burndown `json:"burndown"`
+		}
+
+		result := GetProjectSprintBurndownResult{Burndown: burndown}
+		sendResultResponse(result, w)
+	}
+}
+
+func getProjectEpicTimeTracking(service reporting.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		epicID := vars["epicId"]
+
+		tt, err := service.GetProjectEpicTimeTracking(&projectID, &epicID)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		type GetProjectEpicTimeTrackingResult struct {
+			TimeTracking *reporting.TimeTracking `json:"timeTracking"`
+		}
+
+		result := GetProjectEpicTimeTrackingResult{TimeTracking: tt}
+		sendResultResponse(result, w)
+	}
+}
+
+func getProjectSprintTimeTracking(service reporting.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		sprintID := vars["sprintId"]
+
+		tt, err := service.GetProjectSprintTimeTracking(&projectID, &sprintID)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		type GetProjectSprintTimeTrackingResult struct {
+			TimeTracking *reporting.TimeTracking `json:"timeTracking"`
+		}
+
+		result := GetProjectSprintTimeTrackingResult{TimeTracking: tt}
+		sendResultResponse(result, w)
+	}
+}
+
+func getUserTimesheet(service reporting.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		userID := vars["userId"]
+
+		v := r.URL.Query()
+
+		q := reporting.TimesheetQuery{To: time.Now()}
+		q.From = q.To.AddDate(0, 0, -6)
+
+		var err error
+		if from := v.Get("from"); len(from) > 0 {
+			q.From, err = time.Parse(reportDateLayout, from)
+			if err != nil {
+				handleRequestError(err, w)
+				return
+			}
+		}
+		if to := v.Get("to"); len(to) > 0 {
+			q.To, err = time.Parse(reportDateLayout, to)
+			if err != nil {
+				handleRequestError(err, w)
+				return
+			}
+		}
+
+		ts, err := service.GetUserTimesheet(&userID, &q)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		type GetUserTimesheetResult struct {
+			Timesheet *reporting.Timesheet `json:"timesheet"`
+		}
+
+		result := GetUserTimesheetResult{Timesheet: ts}
+		sendResultResponse(result, w)
+	}
+}
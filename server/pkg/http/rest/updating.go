@@ -2,12 +2,45 @@ package rest
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/gorilla/mux"
 	"github.com/njehyde/issue-tracker/pkg/updating"
 )
 
+func completeProjectBoardSprint(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var sc updating.SprintCompletion
+
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		boardID := vars["boardId"]
+		sprintID := vars["sprintId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = json.NewDecoder(r.Body).Decode(&sc)
+		if err != nil && err != io.EOF {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.CompleteProjectBoardSprint(userID, &projectID, &boardID, &sprintID, &sc)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Sprint completed successfully", w)
+	}
+}
+
 func decreaseIssueStatus(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
@@ -68,6 +101,59 @@ func increasePriorityType(service updating.Service) func(w http.ResponseWriter,
 	}
 }
 
+func mergeLabels(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var lm updating.LabelMerge
+
+		vars := mux.Vars(r)
+		id := vars["id"]
+
+		err := json.NewDecoder(r.Body).Decode(&lm)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.MergeLabels(id, &lm)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Labels merged successfully", w)
+	}
+}
+
+func releaseProjectVersion(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var vr updating.VersionRelease
+
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		versionID := vars["versionId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = json.NewDecoder(r.Body).Decode(&vr)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.ReleaseProjectVersion(userID, &projectID, &versionID, &vr)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Version released successfully", w)
+	}
+}
+
 func sendIssueToSprint(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var meta updating.SendIssueToSprintMetadata
@@ -144,6 +230,82 @@ func sendIssueToTopOfBacklog(service updating.Service) func(w http.ResponseWrite
 	}
 }
 
+func startProjectBoardSprint(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var ss updating.SprintStart
+
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		boardID := vars["boardId"]
+		sprintID := vars["sprintId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = json.NewDecoder(r.Body).Decode(&ss)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.StartProjectBoardSprint(userID, &projectID, &boardID, &sprintID, &ss)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Sprint started successfully", w)
+	}
+}
+
+func unreleaseProjectVersion(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		versionID := vars["versionId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.UnreleaseProjectVersion(userID, &projectID, &versionID)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Version unreleased successfully", w)
+	}
+}
+
+func updateCustomField(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var cf updating.CustomField
+
+		vars := mux.Vars(r)
+		id := vars["id"]
+
+		err := json.NewDecoder(r.Body).Decode(&cf)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.UpdateCustomField(id, &cf)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Custom field updated successfully", w)
+	}
+}
+
 func updateIssue(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var i updating.Issue
@@ -174,6 +336,65 @@ func updateIssue(service updating.Service) func(w http.ResponseWriter, r *http.R
 	}
 }
 
+func moveIssue(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var p updating.IssuePosition
+
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		issueID := vars["issueId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = json.NewDecoder(r.Body).Decode(&p)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.MoveIssue(userID, &projectID, &issueID, &p)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Issue moved successfully", w)
+	}
+}
+
+func transferIssue(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var t updating.IssueTransfer
+
+		vars := mux.Vars(r)
+		issueID := vars["issueId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = json.NewDecoder(r.Body).Decode(&t)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.TransferIssue(userID, &issueID, &t)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Issue moved to project successfully", w)
+	}
+}
+
 func updateIssueOrdinals(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var ios updating.IssueOrdinals
@@ -233,6 +454,36 @@ func updateIssueComment(service updating.Service) func(w http.ResponseWriter, r
 	}
 }
 
+func updateIssueWorklog(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var iw updating.IssueWorklog
+
+		vars := mux.Vars(r)
+		issueID := vars["issueId"]
+		worklogID := vars["worklogId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = json.NewDecoder(r.Body).Decode(&iw)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.UpdateIssueWorklog(userID, &issueID, &worklogID, &iw)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Issue worklog updated successfully", w)
+	}
+}
+
 func updateIssueStatus(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var is updating.IssueStatus
@@ -256,85 +507,696 @@ func updateIssueStatus(service updating.Service) func(w http.ResponseWriter, r *
 	}
 }
 
-func updatePriorityType(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+func updateBoardType(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var pt updating.PriorityType
+		var bt updating.BoardType
 
 		vars := mux.Vars(r)
 		id := vars["id"]
 
-		err := json.NewDecoder(r.Body).Decode(&pt)
+		err := json.NewDecoder(r.Body).Decode(&bt)
 		if err != nil {
 			handleRequestError(err, w)
 			return
 		}
 
-		err = service.UpdatePriorityType(id, &pt)
+		err = service.UpdateBoardType(id, &bt)
 		if err != nil {
 			handleServiceError(err, w)
 			return
 		}
 
-		sendSuccessResponse("Priority tyoe updated successfully", w)
+		sendSuccessResponse("Board type updated successfully", w)
 	}
 }
 
-func updateProject(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+func updateIssueType(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var p updating.Project
+		var it updating.IssueType
 
 		vars := mux.Vars(r)
-		projectID := vars["id"]
-
-		userID, err := getUserFromRequestContext(r)
-		if err != nil {
-			handleRequestError(err, w)
-			return
-		}
+		id := vars["id"]
 
-		err = json.NewDecoder(r.Body).Decode(&p)
+		err := json.NewDecoder(r.Body).Decode(&it)
 		if err != nil {
 			handleRequestError(err, w)
 			return
 		}
 
-		err = service.UpdateProject(userID, projectID, &p)
+		err = service.UpdateIssueType(id, &it)
 		if err != nil {
 			handleServiceError(err, w)
 			return
 		}
 
-		sendSuccessResponse("Project updated successfully", w)
+		sendSuccessResponse("Issue type updated successfully", w)
 	}
 }
 
-func updateProjectBoardSprint(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+func updateLabel(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var s updating.Sprint
+		var l updating.Label
 
 		vars := mux.Vars(r)
-		projectID := vars["projectId"]
-		boardID := vars["boardId"]
-		sprintID := vars["sprintId"]
+		id := vars["id"]
 
-		userID, err := getUserFromRequestContext(r)
+		err := json.NewDecoder(r.Body).Decode(&l)
 		if err != nil {
 			handleRequestError(err, w)
 			return
 		}
 
-		err = json.NewDecoder(r.Body).Decode(&s)
+		err = service.UpdateLabel(id, &l)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Label updated successfully", w)
+	}
+}
+
+func updatePriorityType(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var pt updating.PriorityType
+
+		vars := mux.Vars(r)
+		id := vars["id"]
+
+		err := json.NewDecoder(r.Body).Decode(&pt)
 		if err != nil {
 			handleRequestError(err, w)
 			return
 		}
 
-		err = service.UpdateProjectBoardSprint(userID, &projectID, &boardID, &sprintID, &s)
+		err = service.UpdatePriorityType(id, &pt)
 		if err != nil {
 			handleServiceError(err, w)
 			return
 		}
 
-		sendSuccessResponse("Sprint updated successfully", w)
+		sendSuccessResponse("Priority tyoe updated successfully", w)
+	}
+}
+
+func updateProject(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var p updating.Project
+
+		vars := mux.Vars(r)
+		projectID := vars["id"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = json.NewDecoder(r.Body).Decode(&p)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.UpdateProject(userID, projectID, &p)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Project updated successfully", w)
+	}
+}
+
+func updateProjectComponent(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var c updating.Component
+
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		componentID := vars["componentId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = json.NewDecoder(r.Body).Decode(&c)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.UpdateProjectComponent(userID, &projectID, &componentID, &c)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Component updated successfully", w)
+	}
+}
+
+func updateProjectIssueTemplate(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var t updating.IssueTemplate
+
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		templateID := vars["issueTemplateId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = json.NewDecoder(r.Body).Decode(&t)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.UpdateProjectIssueTemplate(userID, &projectID, &templateID, &t)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Issue template updated successfully", w)
+	}
+}
+
+func updateProjectSLAPolicy(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var p updating.SLAPolicy
+
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		policyID := vars["slaPolicyId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = json.NewDecoder(r.Body).Decode(&p)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.UpdateProjectSLAPolicy(userID, &projectID, &policyID, &p)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("SLA policy updated successfully", w)
+	}
+}
+
+func updateProjectIssueSchedule(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var s updating.IssueSchedule
+
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		scheduleID := vars["issueScheduleId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = json.NewDecoder(r.Body).Decode(&s)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.UpdateIssueSchedule(userID, &projectID, &scheduleID, &s)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Issue schedule updated successfully", w)
+	}
+}
+
+func readNotification(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		notificationID := vars["notificationId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.ReadNotification(userID, &notificationID)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Notification read successfully", w)
+	}
+}
+
+func updateProjectIssueTypeScheme(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var its updating.ProjectIssueTypeScheme
+
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = json.NewDecoder(r.Body).Decode(&its)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.UpdateProjectIssueTypeScheme(userID, projectID, &its)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Project issue type scheme updated successfully", w)
+	}
+}
+
+func updateProjectBoard(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var b updating.Board
+
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		boardID := vars["boardId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = json.NewDecoder(r.Body).Decode(&b)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.UpdateProjectBoard(userID, &projectID, &boardID, &b)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Board updated successfully", w)
+	}
+}
+
+func updateProjectBoardSwimlanes(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var sl updating.Swimlanes
+
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		boardID := vars["boardId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = json.NewDecoder(r.Body).Decode(&sl)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.UpdateProjectBoardSwimlanes(userID, &projectID, &boardID, &sl)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Board swimlanes updated successfully", w)
+	}
+}
+
+func updateProjectDefaultBoard(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		boardID := vars["boardId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.UpdateProjectDefaultBoard(userID, &projectID, &boardID)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Default board updated successfully", w)
+	}
+}
+
+func addProjectBoardColumn(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var c updating.BoardColumn
+
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		boardID := vars["boardId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = json.NewDecoder(r.Body).Decode(&c)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.AddProjectBoardColumn(userID, &projectID, &boardID, &c)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Column added successfully", w)
+	}
+}
+
+func moveProjectBoardColumn(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var m updating.ColumnMove
+
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		boardID := vars["boardId"]
+
+		ordinal, err := strconv.Atoi(vars["ordinal"])
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = json.NewDecoder(r.Body).Decode(&m)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.MoveProjectBoardColumn(userID, &projectID, &boardID, int32(ordinal), &m)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Column moved successfully", w)
+	}
+}
+
+func removeProjectBoardColumn(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		boardID := vars["boardId"]
+
+		ordinal, err := strconv.Atoi(vars["ordinal"])
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.RemoveProjectBoardColumn(userID, &projectID, &boardID, int32(ordinal))
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Column removed successfully", w)
+	}
+}
+
+func splitProjectBoardColumn(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var c updating.BoardColumn
+
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		boardID := vars["boardId"]
+
+		ordinal, err := strconv.Atoi(vars["ordinal"])
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = json.NewDecoder(r.Body).Decode(&c)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.SplitProjectBoardColumn(userID, &projectID, &boardID, int32(ordinal), &c)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Column split successfully", w)
+	}
+}
+
+func updateProjectBoardColumn(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var c updating.BoardColumn
+
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		boardID := vars["boardId"]
+
+		ordinal, err := strconv.Atoi(vars["ordinal"])
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = json.NewDecoder(r.Body).Decode(&c)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.UpdateProjectBoardColumn(userID, &projectID, &boardID, int32(ordinal), &c)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Column updated successfully", w)
+	}
+}
+
+func updateProjectBoardColumnWIPLimit(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var l updating.WIPLimit
+
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		boardID := vars["boardId"]
+
+		ordinal, err := strconv.Atoi(vars["ordinal"])
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = json.NewDecoder(r.Body).Decode(&l)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.UpdateProjectBoardColumnWIPLimit(userID, &projectID, &boardID, int32(ordinal), &l)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Column WIP limit updated successfully", w)
+	}
+}
+
+func updateProjectBoardSprint(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var s updating.Sprint
+
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		boardID := vars["boardId"]
+		sprintID := vars["sprintId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = json.NewDecoder(r.Body).Decode(&s)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.UpdateProjectBoardSprint(userID, &projectID, &boardID, &sprintID, &s)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Sprint updated successfully", w)
+	}
+}
+
+func updateProjectBoardStatusMapping(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var m updating.StatusMapping
+
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		boardID := vars["boardId"]
+		statusID := vars["statusId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = json.NewDecoder(r.Body).Decode(&m)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.UpdateProjectBoardStatusMapping(userID, &projectID, &boardID, statusID, &m)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Status mapping updated successfully", w)
+	}
+}
+
+func updateProjectVersion(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var v updating.Version
+
+		vars := mux.Vars(r)
+		projectID := vars["projectId"]
+		versionID := vars["versionId"]
+
+		userID, err := getUserFromRequestContext(r)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = json.NewDecoder(r.Body).Decode(&v)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.UpdateProjectVersion(userID, &projectID, &versionID, &v)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Version updated successfully", w)
+	}
+}
+
+func updateWorkflowTransitions(service updating.Service) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var wt updating.WorkflowTransitions
+
+		vars := mux.Vars(r)
+		id, err := strconv.ParseInt(vars["id"], 10, 32)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = json.NewDecoder(r.Body).Decode(&wt)
+		if err != nil {
+			handleRequestError(err, w)
+			return
+		}
+
+		err = service.UpdateWorkflowTransitions(int32(id), wt.Transitions)
+		if err != nil {
+			handleServiceError(err, w)
+			return
+		}
+
+		sendSuccessResponse("Workflow transitions updated successfully", w)
 	}
 }
@@ -4,16 +4,33 @@ import "time"
 
 // Board defines the listing form of a board entity.
 type Board struct {
-	ID               string        `json:"id"`
-	Type             string        `json:"type"`
-	Name             string        `json:"name"`
-	Description      string        `json:"description"`
-	IsBacklogVisible bool          `json:"isBacklogVisible"`
-	IsBoardVisible   bool          `json:"isBoardVisible"`
-	Columns          []BoardColumn `json:"columns"`
-	Sprints          []Sprint      `json:"sprints"`
-	CreatedAt        *time.Time    `json:"createdAt"`
-	UpdatedAt        *time.Time    `json:"updatedAt"`
+	ID               string          `json:"id"`
+	Type             string          `json:"type"`
+	Name             string          `json:"name"`
+	Description      string          `json:"description"`
+	IsBacklogVisible bool            `json:"isBacklogVisible"`
+	IsBoardVisible   bool            `json:"isBoardVisible"`
+	ParallelSprints  bool            `json:"parallelSprints"`
+	Filters          []BoardFilter   `json:"filters"`
+	Columns          []BoardColumn   `json:"columns"`
+	UnmappedStatuses []string        `json:"unmappedStatuses"`
+	SwimlaneType     string          `json:"swimlaneType"`
+	SwimlaneQueries  []SwimlaneQuery `json:"swimlaneQueries"`
+	Sprints          []Sprint        `json:"sprints"`
+	CreatedAt        *time.Time      `json:"createdAt"`
+	UpdatedAt        *time.Time      `json:"updatedAt"`
+}
+
+// BoardFilter defines the listing form of a board issue filter Value Object.
+type BoardFilter struct {
+	Field string `json:"field"`
+	Value string `json:"value"`
+}
+
+// SwimlaneQuery defines the listing form of a named swimlane query Value Object.
+type SwimlaneQuery struct {
+	Name    string        `json:"name"`
+	Filters []BoardFilter `json:"filters"`
 }
 
 // BoardColumn defines the listing form of a board column Value Object.
@@ -21,26 +38,93 @@ type BoardColumn struct {
 	Name          string   `json:"name"`
 	IssueStatuses []string `json:"issueStatuses"`
 	Ordinal       int32    `json:"ordinal"`
+	MinWIP        int32    `json:"minWip,omitempty"`
+	MaxWIP        int32    `json:"maxWip,omitempty"`
+	WIPPolicy     string   `json:"wipPolicy,omitempty"`
+	IssueCount    int64    `json:"issueCount"`
+	WIPState      string   `json:"wipState,omitempty"`
+}
+
+// WIP states of a board column.
+const (
+	WIPUnder = "UNDER"
+	WIPOver  = "OVER"
+)
+
+// SetIssueCount sets the number of issues in a board column, and whether it is under or over its WIP limits.
+func (c *BoardColumn) SetIssueCount(count int64) {
+	c.IssueCount = count
+	c.WIPState = ""
+	if c.MaxWIP > 0 && count > int64(c.MaxWIP) {
+		c.WIPState = WIPOver
+	} else if c.MinWIP > 0 && count < int64(c.MinWIP) {
+		c.WIPState = WIPUnder
+	}
+}
+
+// BoardIssues defines the listing form of the issues on a board, grouped into a cell for each swimlane and column.
+type BoardIssues struct {
+	SwimlaneType string     `json:"swimlaneType"`
+	Columns      []string   `json:"columns"`
+	Swimlanes    []Swimlane `json:"swimlanes"`
+}
+
+// Swimlane defines the listing form of a board swimlane, with the issues of each column in column order. An empty
+// key is the lane of the issues without a value, such as unassigned issues.
+type Swimlane struct {
+	Key        string         `json:"key"`
+	Name       string         `json:"name"`
+	IssueCount int64          `json:"issueCount"`
+	Points     int64          `json:"points"`
+	Cells      []SwimlaneCell `json:"cells"`
+}
+
+// SwimlaneCell defines the listing form of the issues in one column of a board swimlane.
+type SwimlaneCell struct {
+	Column     string  `json:"column"`
+	Ordinal    int32   `json:"ordinal"`
+	IssueCount int64   `json:"issueCount"`
+	Points     int64   `json:"points"`
+	Issues     []Issue `json:"issues"`
+}
+
+// AddIssue adds an issue to the cell of a swimlane at a column ordinal, counting it and its points towards both.
+func (sl *Swimlane) AddIssue(ordinal int32, i *Issue) {
+	c := &sl.Cells[ordinal]
+	c.Issues = append(c.Issues, *i)
+	c.IssueCount++
+	c.Points += int64(i.Points)
+	sl.IssueCount++
+	sl.Points += int64(i.Points)
 }
 
 // BoardType defines the listing form of a board type entity.
 type BoardType struct {
-	ID        string `json:"id"`
-	Name      string `json:"name"`
-	IsDefault bool   `json:"default"`
+	ID               string        `json:"id"`
+	Name             string        `json:"name"`
+	IsDefault        bool          `json:"default"`
+	IsSprintable     bool          `json:"isSprintable"`
+	IsBacklogVisible bool          `json:"isBacklogVisible"`
+	IsBoardVisible   bool          `json:"isBoardVisible"`
+	WorkflowID       int32         `json:"workflowId"`
+	Columns          []BoardColumn `json:"columns"`
+	UnmappedStatuses []string      `json:"unmappedStatuses"`
 }
 
 // Sprint defines the listing form of a sprint entity.
 type Sprint struct {
-	ID        string     `json:"id"`
-	Name      string     `json:"name"`
-	Goal      string     `json:"goal,omitempty"`
-	Ordinal   int32      `json:"ordinal,omitempty"`
-	StartAt   *time.Time `json:"startAt,omitempty"`
-	EndAt     *time.Time `json:"endAt,omitempty"`
-	CreatedAt *time.Time `json:"createdAt"`
-	UpdatedAt *time.Time `json:"updatedAt"`
-	CreatedBy string     `json:"createdBy"`
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Goal        string     `json:"goal,omitempty"`
+	Ordinal     int32      `json:"ordinal,omitempty"`
+	State       string     `json:"state"`
+	StartAt     *time.Time `json:"startAt,omitempty"`
+	EndAt       *time.Time `json:"endAt,omitempty"`
+	StartedAt   *time.Time `json:"startedAt,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	CreatedAt   *time.Time `json:"createdAt"`
+	UpdatedAt   *time.Time `json:"updatedAt"`
+	CreatedBy   string     `json:"createdBy"`
 }
 
 // WorkflowStep defines the listing form of a workflow step Value Object.
@@ -51,10 +135,27 @@ type WorkflowStep struct {
 	StatusIds  []string `json:"statusIds"`
 }
 
+// TransitionRule defines the listing form of a rule attached to a workflow transition Value Object.
+type TransitionRule struct {
+	Type   string            `json:"type"`
+	Params map[string]string `json:"params,omitempty"`
+}
+
+// WorkflowTransition defines the listing form of a workflow transition Value Object.
+type WorkflowTransition struct {
+	Name          string           `json:"name"`
+	FromStatusIDs []string         `json:"fromStatusIds"`
+	ToStatusID    string           `json:"toStatusId"`
+	Conditions    []TransitionRule `json:"conditions"`
+	Validators    []TransitionRule `json:"validators"`
+	PostFunctions []TransitionRule `json:"postFunctions"`
+}
+
 // Workflow defines the listing form of a workflow entity.
 type Workflow struct {
-	ID       int32          `json:"id"`
-	Name     string         `json:"name"`
-	IsLocked bool           `json:"isLocked"`
-	Steps    []WorkflowStep `json:"steps"`
+	ID          int32                `json:"id"`
+	Name        string               `json:"name"`
+	IsLocked    bool                 `json:"isLocked"`
+	Steps       []WorkflowStep       `json:"steps"`
+	Transitions []WorkflowTransition `json:"transitions"`
 }
@@ -0,0 +1,17 @@
+package listing
+
+import "time"
+
+// CustomField defines the listing form of a custom field entity.
+type CustomField struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Type        string    `json:"type"`
+	Options     []string  `json:"options"`
+	IsRequired  bool      `json:"isRequired"`
+	ProjectIDs  []string  `json:"projectIds"`
+	IssueTypes  []string  `json:"issueTypes"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,268 @@
+package listing
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IssueExport defines an export of the issues matching a query, written as CSV, a JSON array, or NDJSON with one
+// issue per line. Each issue is written as the given columns, in order, where custom fields are referenced as
+// "cf.{id}".
+type IssueExport struct {
+	Format  string     `json:"format"`
+	Columns []string   `json:"columns"`
+	Query   IssueQuery `json:"query"`
+}
+
+// Issue export formats.
+const (
+	// ExportCSV is the format of an export written as CSV, with a header row of its columns.
+	ExportCSV = "csv"
+	// ExportJSON is the format of an export written as a JSON array of objects.
+	ExportJSON = "json"
+	// ExportNDJSON is the format of an export written as newline delimited JSON objects.
+	ExportNDJSON = "ndjson"
+)
+
+// defaultExportColumns are the columns of an export that does not choose its own.
+var defaultExportColumns = []string{
+	"projectRef", "type", "summary", "status", "priority", "points", "assignee", "reporter", "labels", "createdAt",
+	"updatedAt",
+}
+
+// exportColumns are the columns an export can choose from, besides custom fields. The "status", "assignee" and
+// "reporter" columns have names, and the columns ending in "Id" have ids.
+var exportColumns = map[string]bool{
+	"id": true, "projectId": true, "projectRef": true, "type": true, "summary": true, "description": true,
+	"status": true, "statusId": true, "priority": true, "points": true, "originalEstimate": true,
+	"remainingEstimate": true, "timeSpent": true, "dueDate": true, "createdAt": true, "updatedAt": true,
+	"reporter": true, "reporterId": true, "assignee": true, "assigneeId": true, "sprintId": true, "epicId": true,
+	"labels": true, "components": true, "fixVersions": true,
+}
+
+// exportBatchSize is how many issues an export holds in memory at once, while the names of their users are looked up.
+const exportBatchSize = 200
+
+func validateIssueExport(e *IssueExport) error {
+	if e == nil {
+		return fmt.Errorf("Issue export is nil")
+	}
+	if len(e.Format) == 0 {
+		e.Format = ExportCSV
+	}
+	if e.Format != ExportCSV && e.Format != ExportJSON && e.Format != ExportNDJSON {
+		return fmt.Errorf("Unknown export format %v", e.Format)
+	}
+	if len(e.Columns) == 0 {
+		e.Columns = defaultExportColumns
+	}
+	for _, c := range e.Columns {
+		if !exportColumns[c] && !(strings.HasPrefix(c, "cf.") && len(c) > len("cf.")) {
+			return fmt.Errorf("Unknown export column %v", c)
+		}
+	}
+
+	return validateIssueQuery(&e.Query)
+}
+
+// getExportValue returns the value of a column of an exported issue, with statuses and users given by name.
+func getExportValue(i *Issue, column string, statuses map[string]string, users map[string]string) interface{} {
+	switch column {
+	case "id":
+		return i.ID
+	case "projectId":
+		return i.ProjectID
+	case "projectRef":
+		return i.ProjectRef
+	case "type":
+		return i.Type
+	case "summary":
+		return i.Summary
+	case "description":
+		return i.Description
+	case "status":
+		if name, ok := statuses[i.Status]; ok {
+			return name
+		}
+		return i.Status
+	case "statusId":
+		return i.Status
+	case "priority":
+		return i.Priority
+	case "points":
+		return i.Points
+	case "originalEstimate":
+		return i.OriginalEstimate
+	case "remainingEstimate":
+		return i.RemainingEstimate
+	case "timeSpent":
+		return i.TimeSpent
+	case "dueDate":
+		if i.DueDate == nil {
+			return nil
+		}
+		return *i.DueDate
+	case "createdAt":
+		return i.CreatedAt
+	case "updatedAt":
+		return i.UpdatedAt
+	case "reporter":
+		return users[i.ReporterID]
+	case "reporterId":
+		return i.ReporterID
+	case "assignee":
+		return users[i.AssigneeID]
+	case "assigneeId":
+		return i.AssigneeID
+	case "sprintId":
+		return i.SprintID
+	case "epicId":
+		return i.EpicID
+	case "labels":
+		return i.Labels
+	case "components":
+		return i.Components
+	case "fixVersions":
+		return i.FixVersions
+	default:
+		return i.CustomFields[strings.TrimPrefix(column, "cf.")]
+	}
+}
+
+// issueExportWriter writes the rows of an export in its format. Rows are buffered until they are flushed.
+type issueExportWriter interface {
+	writeRow([]interface{}) error
+	flush() error
+	close() error
+}
+
+func newIssueExportWriter(format string, columns []string, w io.Writer) (issueExportWriter, error) {
+	if format == ExportCSV {
+		ew := csvExportWriter{w: csv.NewWriter(w)}
+		return &ew, ew.w.Write(columns)
+	}
+
+	ew := jsonExportWriter{w: bufio.NewWriter(w), columns: columns, lines: format == ExportNDJSON}
+	if !ew.lines {
+		_, err := ew.w.WriteString("[")
+		return &ew, err
+	}
+
+	return &ew, nil
+}
+
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+func (ew *csvExportWriter) writeRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for n, v := range values {
+		record[n] = escapeExportFormula(formatExportValue(v))
+	}
+
+	return ew.w.Write(record)
+}
+
+func (ew *csvExportWriter) flush() error {
+	ew.w.Flush()
+	return ew.w.Error()
+}
+
+func (ew *csvExportWriter) close() error {
+	return ew.flush()
+}
+
+// formatExportValue formats a value for a CSV cell. Lists are separated by commas, and times are in RFC 3339 format.
+func formatExportValue(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case time.Time:
+		return value.Format(time.RFC3339)
+	case []string:
+		return strings.Join(value, ", ")
+	case []interface{}:
+		values := make([]string, len(value))
+		for n, item := range value {
+			values[n] = formatExportValue(item)
+		}
+		return strings.Join(values, ", ")
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// escapeExportFormula prefixes a CSV cell that a spreadsheet would read as a formula with a quote, so that it is shown
+// as text. Negative numbers are left as they are.
+func escapeExportFormula(cell string) string {
+	if len(cell) == 0 || !strings.ContainsAny(cell[:1], "=+-@") {
+		return cell
+	}
+	if _, err := strconv.ParseFloat(cell, 64); err == nil {
+		return cell
+	}
+	return "'" + cell
+}
+
+type jsonExportWriter struct {
+	w       *bufio.Writer
+	columns []string
+	lines   bool
+	rows    int
+}
+
+// writeRow writes a row as a JSON object, with its keys in the order of the export's columns.
+func (ew *jsonExportWriter) writeRow(values []interface{}) error {
+	if ew.rows > 0 && !ew.lines {
+		ew.w.WriteString(",")
+	}
+	ew.rows++
+
+	ew.w.WriteString("{")
+	for n, v := range values {
+		if n > 0 {
+			ew.w.WriteString(",")
+		}
+
+		key, err := json.Marshal(ew.columns[n])
+		if err != nil {
+			return err
+		}
+		value, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+
+		ew.w.Write(key)
+		ew.w.WriteString(":")
+		ew.w.Write(value)
+	}
+	ew.w.WriteString("}")
+
+	if ew.lines {
+		ew.w.WriteString("\n")
+	}
+
+	return nil
+}
+
+func (ew *jsonExportWriter) flush() error {
+	return ew.w.Flush()
+}
+
+func (ew *jsonExportWriter) close() error {
+	if !ew.lines {
+		ew.w.WriteString("]")
+	}
+
+	return ew.flush()
+}
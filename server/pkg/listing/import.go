@@ -0,0 +1,23 @@
+package listing
+
+import "time"
+
+// IssueImport defines the listing form of an issue import job entity.
+type IssueImport struct {
+	ID        string             `json:"id"`
+	ProjectID string             `json:"projectId"`
+	Status    string             `json:"status"`
+	Total     int                `json:"total"`
+	Processed int                `json:"processed"`
+	Added     int                `json:"added"`
+	Errors    []IssueImportError `json:"errors"`
+	CreatedBy string             `json:"createdBy"`
+	CreatedAt time.Time          `json:"createdAt"`
+	UpdatedAt time.Time          `json:"updatedAt"`
+}
+
+// IssueImportError defines the listing form of a row of an issue import job that could not be added.
+type IssueImportError struct {
+	Row     int    `json:"row"`
+	Message string `json:"message"`
+}
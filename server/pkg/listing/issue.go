@@ -1,6 +1,7 @@
 package listing
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -11,24 +12,39 @@ type Category struct {
 	Ordinal int32  `json:"ordinal"`
 }
 
-// Issue defines the listing form of an issue entity.
+// Issue defines the listing form of an issue entity. Issues are ordered within the backlog, or a sprint, by rank, and
+// the ordinal is the position of the issue in a list of its backlog's or sprint's issues.
 type Issue struct {
-	ID          string    `json:"id"`
-	ProjectID   string    `json:"projectId"`
-	SprintID    string    `json:"sprintId,omitempty"`
-	ProjectRef  string    `json:"projectRef"`
-	Type        string    `json:"type"`
-	Summary     string    `json:"summary"`
-	Description string    `json:"description,omitempty"`
-	Status      string    `json:"status"`
-	Priority    string    `json:"priority"`
-	Points      int32     `json:"points,omitempty"`
-	Ordinal     int32     `json:"ordinal"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
-	ReporterID  string    `json:"reporterId"`
-	AssigneeID  string    `json:"assigneeId,omitempty"`
-	Labels      []string  `json:"labels,omitempty"`
+	ID                string                 `json:"id"`
+	ProjectID         string                 `json:"projectId"`
+	SprintID          string                 `json:"sprintId,omitempty"`
+	ProjectRef        string                 `json:"projectRef"`
+	Type              string                 `json:"type"`
+	Summary           string                 `json:"summary"`
+	Description       string                 `json:"description,omitempty"`
+	Status            string                 `json:"status"`
+	Priority          string                 `json:"priority"`
+	Points            int32                  `json:"points,omitempty"`
+	OriginalEstimate  int64                  `json:"originalEstimate,omitempty"`
+	RemainingEstimate int64                  `json:"remainingEstimate,omitempty"`
+	TimeSpent         int64                  `json:"timeSpent,omitempty"`
+	DueDate           *time.Time             `json:"dueDate,omitempty"`
+	Ordinal           int32                  `json:"ordinal"`
+	Rank              string                 `json:"rank"`
+	CreatedAt         time.Time              `json:"createdAt"`
+	UpdatedAt         time.Time              `json:"updatedAt"`
+	ReporterID        string                 `json:"reporterId"`
+	AssigneeID        string                 `json:"assigneeId,omitempty"`
+	EpicID            string                 `json:"epicId,omitempty"`
+	Labels            []string               `json:"labels,omitempty"`
+	Components        []string               `json:"components,omitempty"`
+	FixVersions       []string               `json:"fixVersions,omitempty"`
+	CustomFields      map[string]interface{} `json:"customFields,omitempty"`
+	SLAs              []IssueSLA             `json:"slas,omitempty"`
+	ScheduleID        string                 `json:"scheduleId,omitempty"`
+	ScheduledAt       *time.Time             `json:"scheduledAt,omitempty"`
+	PreviousRefs      []string               `json:"previousRefs,omitempty"`
+	ClonedFromID      string                 `json:"clonedFromId,omitempty"`
 	// DevAssigneeID
 	// QaAssigneeID
 	// SprintID
@@ -45,6 +61,17 @@ type IssueComment struct {
 	UpdatedAt time.Time `json:"updatedAt"`
 }
 
+// IssueWorklog defines the listing form of an issue work log entry. Durations are in seconds.
+type IssueWorklog struct {
+	ID        string    `json:"id"`
+	User      User      `json:"user"`
+	StartedAt time.Time `json:"startedAt"`
+	Duration  int64     `json:"duration"`
+	Comment   string    `json:"comment,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
 // IssueStatus defines the listing form of an issue status entity.
 type IssueStatus struct {
 	ID          string `json:"id"`
@@ -60,6 +87,8 @@ type IssueType struct {
 	ID          string `json:"id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	Icon        string `json:"icon,omitempty"`
+	IsSubTask   bool   `json:"subTask"`
 	IsDefault   bool   `json:"default"`
 }
 
@@ -67,6 +96,7 @@ type IssueType struct {
 type Label struct {
 	ID    string `json:"id"`
 	Label string `json:"label"`
+	Count int64  `json:"count"`
 }
 
 // PriorityType defines the listing form of a priotity type entity.
@@ -79,6 +109,36 @@ type PriorityType struct {
 	IsDefault   bool   `json:"default"`
 }
 
+// IssueFilter defines the listing form of an issue query filter. Custom fields are filtered using a field
+// of the form "cf.{customFieldId}".
+type IssueFilter struct {
+	Field string `json:"field"`
+	Value string `json:"value"`
+}
+
+// IssueQuery defines the listing form of an issue query.
+type IssueQuery struct {
+	Filters []IssueFilter `json:"filters,omitempty"`
+	Sort    string        `json:"sort,omitempty"`
+	Order   int32         `json:"order,omitempty"`
+}
+
+func validateIssueQuery(q *IssueQuery) error {
+	if q == nil {
+		return nil
+	}
+	if q.Order != 0 && q.Order != 1 && q.Order != -1 {
+		return fmt.Errorf("'order' must be 1 or -1")
+	}
+	for _, f := range q.Filters {
+		if len(f.Field) == 0 {
+			return fmt.Errorf("Filter 'field' is empty")
+		}
+	}
+
+	return nil
+}
+
 // Pagination defines the listing form of a pagination.
 type Pagination struct {
 	PageSize int    `json:"pageSize"`
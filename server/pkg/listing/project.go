@@ -13,15 +13,29 @@ type ProjectType struct {
 
 // Project defines the listing form of a project entity.
 type Project struct {
-	ID                string    `json:"id"`
-	Key               string    `json:"key"`
-	Name              string    `json:"name"`
-	Type              string    `json:"type"`
-	Description       string    `json:"description"`
-	LeadID            string    `json:"leadId"`
-	DefaultAssigneeID string    `json:"defaultAssigneeId"`
-	DefaultBoardID    string    `json:"defaultBoardId"`
-	Boards            []Board   `json:"boards"`
-	CreatedAt         time.Time `json:"createdAt"`
-	UpdatedAt         time.Time `json:"updatedAt"`
+	ID                string      `json:"id"`
+	Key               string      `json:"key"`
+	Name              string      `json:"name"`
+	Type              string      `json:"type"`
+	Description       string      `json:"description"`
+	LeadID            string      `json:"leadId"`
+	DefaultAssigneeID string      `json:"defaultAssigneeId"`
+	DefaultBoardID    string      `json:"defaultBoardId"`
+	Boards            []Board     `json:"boards"`
+	IssueTypes        []string    `json:"issueTypes"`
+	Components        []Component `json:"components"`
+	Versions          []Version   `json:"versions"`
+	CreatedAt         time.Time   `json:"createdAt"`
+	UpdatedAt         time.Time   `json:"updatedAt"`
+}
+
+// Component defines the listing form of a project component entity.
+type Component struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	Description  string    `json:"description"`
+	LeadID       string    `json:"leadId"`
+	AssigneeType string    `json:"assigneeType"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
 }
@@ -0,0 +1,39 @@
+package listing
+
+import "time"
+
+// IssueSchedule defines the listing form of a project issue schedule entity.
+type IssueSchedule struct {
+	ID        string         `json:"id"`
+	ProjectID string         `json:"projectId"`
+	Name      string         `json:"name"`
+	Rule      string         `json:"rule"`
+	Timezone  string         `json:"timezone"`
+	StartAt   time.Time      `json:"startAt"`
+	CatchUp   string         `json:"catchUp"`
+	IsEnabled bool           `json:"enabled"`
+	Issue     ScheduledIssue `json:"issue"`
+	NextRunAt *time.Time     `json:"nextRunAt,omitempty"`
+	LastRunAt *time.Time     `json:"lastRunAt,omitempty"`
+	LastError string         `json:"lastError,omitempty"`
+	CreatedBy string         `json:"createdBy"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+}
+
+// ScheduledIssue defines the listing form of the issue template of an issue schedule.
+type ScheduledIssue struct {
+	Type             string                 `json:"type"`
+	Summary          string                 `json:"summary"`
+	Description      string                 `json:"description,omitempty"`
+	Status           string                 `json:"status"`
+	Priority         string                 `json:"priority"`
+	Points           int32                  `json:"points,omitempty"`
+	OriginalEstimate int64                  `json:"originalEstimate,omitempty"`
+	DueIn            int64                  `json:"dueIn,omitempty"`
+	AssigneeID       string                 `json:"assigneeId,omitempty"`
+	EpicID           string                 `json:"epicId,omitempty"`
+	Labels           []string               `json:"labels,omitempty"`
+	Components       []string               `json:"components,omitempty"`
+	CustomFields     map[string]interface{} `json:"customFields,omitempty"`
+}
@@ -1,23 +1,37 @@
 package listing
 
+import "io"
+
 // Service provides entity listing operations.
 type Service interface {
+	// ExportIssues writes the issues matching a query, of a project or of every project, to a writer as they are read.
+	ExportIssues(*string, *IssueExport, io.Writer) error
+	// GetBoardType returns a board type entity by id.
+	GetBoardType(string) (*BoardType, error)
 	// GetBoardTypes returns all board type entities.
 	GetBoardTypes() ([]BoardType, error)
 	// GetCategories returns all category entities..
 	GetCategories() ([]Category, error)
+	// GetCustomFields returns all custom field entities, or those available to a project and issue type.
+	GetCustomFields(*string, *string) ([]CustomField, error)
 	// GetIssue returns an issue entity by id.
 	GetIssue(string) (Issue, error)
+	// GetIssueByRef returns an issue entity by its project reference, or by a reference it had before it was moved.
+	GetIssueByRef(string) (Issue, error)
 	// GetIssueComments returns a paginated slice of issue comment entities.
 	GetIssueComments(*string, *Pagination) ([]IssueComment, int64, error)
-	// GetIssues returns a paginated slice of issue entities.
-	GetIssues(*Pagination) ([]Issue, int64, error)
+	// GetIssues returns a paginated, and optionally filtered and sorted, slice of issue entities.
+	GetIssues(*Pagination, *IssueQuery) ([]Issue, int64, error)
 	// GetIssueStatuses returns all, or a filtered slice of issue status entities.
 	GetIssueStatuses(*string) ([]IssueStatus, error)
 	// GetIssueTypes returns all issue type entities.
 	GetIssueTypes() ([]IssueType, error)
+	// GetIssueWorklogs returns a paginated slice of issue worklog entities.
+	GetIssueWorklogs(*string, *Pagination) ([]IssueWorklog, int64, error)
 	// GetLabels returns all, or a filtered slice of label entities.
 	GetLabels(*string) ([]Label, error)
+	// GetNotifications returns a paginated slice of a user's notification entities, optionally only the unread ones.
+	GetNotifications(*string, bool, *Pagination) ([]Notification, int64, error)
 	// GetPriorityTypes returns all priority type entities.
 	GetPriorityTypes() ([]PriorityType, error)
 	// GetProject returns a project entity by id.
@@ -26,14 +40,34 @@ type Service interface {
 	GetProjectBacklogIssues(*string, *Pagination) ([]Issue, int64, error)
 	// GetProjectBoard returns a project board entity by project and board ids.
 	GetProjectBoard(*string, *string) (*Board, error)
-	// GetProjectIssues returns a paginated slice of project issue entities.
-	GetProjectIssues(*string, *Pagination) ([]Issue, int64, error)
+	// GetProjectBoardIssues returns the issues on a project board, grouped by swimlane and column.
+	GetProjectBoardIssues(*string, *string) (*BoardIssues, error)
+	// GetProjectBoards returns the board entities of a project.
+	GetProjectBoards(*string) ([]Board, error)
+	// GetProjectComponents returns the component entities of a project.
+	GetProjectComponents(*string) ([]Component, error)
+	// GetProjectIssues returns a paginated, and optionally filtered and sorted, slice of project issue entities.
+	GetProjectIssues(*string, *Pagination, *IssueQuery) ([]Issue, int64, error)
+	// GetProjectIssueImport returns an issue import job entity of a project.
+	GetProjectIssueImport(*string, *string) (*IssueImport, error)
+	// GetProjectIssueSchedules returns the issue schedule entities of a project.
+	GetProjectIssueSchedules(*string) ([]IssueSchedule, error)
+	// GetProjectIssueTemplates returns the issue template entities of a project, optionally only those of an issue type.
+	GetProjectIssueTemplates(*string, string) ([]IssueTemplate, error)
+	// GetProjectIssueTypes returns the issue type entities allowed by a project's issue type scheme.
+	GetProjectIssueTypes(*string) ([]IssueType, error)
 	// GetProjects returns a paginated slice of project entities.
 	GetProjects(*Pagination) ([]Project, int64, error)
+	// GetProjectSLAPolicies returns the SLA policy entities of a project.
+	GetProjectSLAPolicies(*string) ([]SLAPolicy, error)
 	// GetProjectSprintIssues returns a paginated slice of project sprint issue entities.
 	GetProjectSprintIssues(*string, *string, *Pagination) ([]Issue, int64, error)
 	// GetProjectTypes returns all, or a filtered slice of project type entities.
 	GetProjectTypes(*string) ([]ProjectType, error)
+	// GetProjectVersionNotes returns the release notes of a project version, grouped by issue type.
+	GetProjectVersionNotes(*string, *string) (*ReleaseNotes, error)
+	// GetProjectVersions returns the version entities of a project.
+	GetProjectVersions(*string) ([]Version, error)
 	// GetUsers returns all, or a filtered slice of user entities.
 	GetUsers(*string) ([]User, error)
 	// GetWorkflows returns all, or a filtered slice of workflow entities.
@@ -42,42 +76,77 @@ type Service interface {
 
 // Repository provides access to issue storage.
 type Repository interface {
+	// GetBoardType returns a board type entity by id from the respository.
+	GetBoardType(string) (*BoardType, error)
 	// GetBoardTypes returns all board type entities from the respository.
 	GetBoardTypes() ([]BoardType, error)
 	// GetCategories returns all category entities from the respository.
 	GetCategories() ([]Category, error)
+	// GetCustomFields returns all custom field entities, or those available to a project and issue type, from the repository.
+	GetCustomFields(*string, *string) ([]CustomField, error)
 	// GetIssue returns an issue entity by id from the repository.
 	GetIssue(string) (Issue, error)
+	// GetIssueByRef returns an issue entity by its project reference, or by a reference it had before it was moved, from the repository.
+	GetIssueByRef(string) (Issue, error)
 	// GetIssueComments returns a paginated slice of issue comment entities from the repository.
 	GetIssueComments(*string, *Pagination) ([]IssueComment, int64, error)
-	// GetIssues returns a paginated slice of issue entities from the repository.
-	GetIssues(*Pagination) ([]Issue, int64, error)
+	// GetIssues returns a paginated, and optionally filtered and sorted, slice of issue entities from the repository.
+	GetIssues(*Pagination, *IssueQuery) ([]Issue, int64, error)
 	// GetIssueStatuses returns all, or a filtered slice of issue status entities from the repository.
 	GetIssueStatuses(*string) ([]IssueStatus, error)
 	// GetIssueTypes returns all issue type entities from the repository.
 	GetIssueTypes() ([]IssueType, error)
+	// GetIssueWorklogs returns a paginated slice of issue worklog entities from the repository.
+	GetIssueWorklogs(*string, *Pagination) ([]IssueWorklog, int64, error)
 	// GetLabels returns all, or a filtered slice of label entities from the repository.
 	GetLabels(*string) ([]Label, error)
+	// GetNotifications returns a paginated slice of a user's notification entities from the repository.
+	GetNotifications(*string, bool, *Pagination) ([]Notification, int64, error)
 	// GetPriorityTypes returns all priority type entities from the repository.
 	GetPriorityTypes() ([]PriorityType, error)
 	// GetProjectBoard returns a project board entity from the repository.
 	GetProjectBoard(*string, *string) (*Board, error)
+	// GetProjectBoardIssues returns the issues on a project board, grouped by swimlane and column, from the repository.
+	GetProjectBoardIssues(*string, *string) (*BoardIssues, error)
+	// GetProjectBoards returns the board entities of a project from the repository.
+	GetProjectBoards(*string) ([]Board, error)
 	// GetProject returns a project entity by id from the respository.
 	GetProjectByID(string) (Project, error)
 	// GetProjectBacklogIssues returns a paginated slice of project backlog issue entities from the repository.
 	GetProjectBacklogIssues(*string, *Pagination) ([]Issue, int64, error)
-	// GetProjectIssues returns a paginated slice of project issue entities from the repository.
-	GetProjectIssues(*string, *Pagination) ([]Issue, int64, error)
+	// GetProjectComponents returns the component entities of a project from the repository.
+	GetProjectComponents(*string) ([]Component, error)
+	// GetProjectIssues returns a paginated, and optionally filtered and sorted, slice of project issue entities from the repository.
+	GetProjectIssues(*string, *Pagination, *IssueQuery) ([]Issue, int64, error)
+	// GetProjectIssueImport returns an issue import job entity of a project from the repository.
+	GetProjectIssueImport(*string, *string) (*IssueImport, error)
+	// GetProjectIssueSchedules returns the issue schedule entities of a project from the repository.
+	GetProjectIssueSchedules(*string) ([]IssueSchedule, error)
+	// GetProjectIssueTemplates returns the issue template entities of a project, optionally only those of an issue type, from the repository.
+	GetProjectIssueTemplates(*string, string) ([]IssueTemplate, error)
+	// GetProjectIssueTypes returns the issue type entities allowed by a project's issue type scheme from the repository.
+	GetProjectIssueTypes(*string) ([]IssueType, error)
 	// GetProjects returns a paginated slice of project entities from the respository.
 	GetProjects(*Pagination) ([]Project, int64, error)
+	// GetProjectSLAPolicies returns the SLA policy entities of a project from the repository.
+	GetProjectSLAPolicies(*string) ([]SLAPolicy, error)
 	// GetProjectSprintIssues returns a paginated slice of project sprint issue entities from the respository.
 	GetProjectSprintIssues(*string, *string, *Pagination) ([]Issue, int64, error)
 	// GetProjectTypes returns all, or a filtered slice of project type entities from the repository.
 	GetProjectTypes(*string) ([]ProjectType, error)
+	// GetProjectVersionNotes returns the issues of a project version, grouped by issue type, from the repository.
+	GetProjectVersionNotes(*string, *string) (*ReleaseNotes, error)
+	// GetProjectVersions returns the version entities of a project from the repository.
+	GetProjectVersions(*string) ([]Version, error)
+	// GetUserNames returns the full names of users, keyed by user id, from the repository.
+	GetUserNames([]string) (map[string]string, error)
 	// GetUsers returns all, or a filtered slice of user entities from the repository.
 	GetUsers(*string) ([]User, error)
 	// GetWorkflows returns all, or a filtered slice of workflow entities from the repository.
 	GetWorkflows() ([]Workflow, error)
+	// StreamIssues calls a function with each issue matching a query, of a project or of every project, as it is read
+	// from the repository, stopping at the first error.
+	StreamIssues(*string, *IssueQuery, func(*Issue) error) error
 }
 
 type service struct {
@@ -89,6 +158,92 @@ func NewService(r Repository) Service {
 	return &service{r}
 }
 
+// ExportIssues writes the issues matching a query to a writer in batches, so that large projects are never held in
+// memory. Nothing is written until the first batch has been read, so that an export that cannot start fails cleanly.
+func (s *service) ExportIssues(projectID *string, e *IssueExport, w io.Writer) error {
+	err := validateIssueExport(e)
+	if err != nil {
+		return err
+	}
+
+	term := ""
+	issueStatuses, err := s.repo.GetIssueStatuses(&term)
+	if err != nil {
+		return err
+	}
+
+	statuses := make(map[string]string)
+	for _, is := range issueStatuses {
+		statuses[is.ID] = is.Name
+	}
+
+	ew, err := newIssueExportWriter(e.Format, e.Columns, w)
+	if err != nil {
+		return err
+	}
+
+	users := make(map[string]string)
+	batch := make([]Issue, 0, exportBatchSize)
+
+	writeBatch := func() error {
+		missing := []string{}
+		for _, i := range batch {
+			for _, id := range []string{i.ReporterID, i.AssigneeID} {
+				if _, ok := users[id]; !ok && len(id) > 0 {
+					users[id] = ""
+					missing = append(missing, id)
+				}
+			}
+		}
+
+		if len(missing) > 0 {
+			names, err := s.repo.GetUserNames(missing)
+			if err != nil {
+				return err
+			}
+			for id, name := range names {
+				users[id] = name
+			}
+		}
+
+		for n := range batch {
+			values := make([]interface{}, len(e.Columns))
+			for c, column := range e.Columns {
+				values[c] = getExportValue(&batch[n], column, statuses, users)
+			}
+			if err := ew.writeRow(values); err != nil {
+				return err
+			}
+		}
+
+		batch = batch[:0]
+
+		return ew.flush()
+	}
+
+	err = s.repo.StreamIssues(projectID, &e.Query, func(i *Issue) error {
+		batch = append(batch, *i)
+		if len(batch) < exportBatchSize {
+			return nil
+		}
+		return writeBatch()
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = writeBatch(); err != nil {
+		return err
+	}
+
+	return ew.close()
+}
+
+func (s *service) GetBoardType(id string) (*BoardType, error) {
+	r, err := s.repo.GetBoardType(id)
+	return r, err
+}
+
 func (s *service) GetBoardTypes() ([]BoardType, error) {
 	r, err := s.repo.GetBoardTypes()
 	return r, err
@@ -99,20 +254,32 @@ func (s *service) GetCategories() ([]Category, error) {
 	return r, err
 }
 
+func (s *service) GetCustomFields(projectID *string, issueType *string) ([]CustomField, error) {
+	r, err := s.repo.GetCustomFields(projectID, issueType)
+	return r, err
+}
+
 func (s *service) GetIssue(id string) (Issue, error) {
 	// TODO: Validation for GetIssue
 	return s.repo.GetIssue(id)
 }
 
+func (s *service) GetIssueByRef(ref string) (Issue, error) {
+	return s.repo.GetIssueByRef(ref)
+}
+
 func (s *service) GetIssueComments(issueID *string, p *Pagination) ([]IssueComment, int64, error) {
 	// TODO: Validation for GetIssueComments
 	r, c, err := s.repo.GetIssueComments(issueID, p)
 	return r, c, err
 }
 
-func (s *service) GetIssues(p *Pagination) ([]Issue, int64, error) {
+func (s *service) GetIssues(p *Pagination, q *IssueQuery) ([]Issue, int64, error) {
 	// TODO: Validation for GetIssues
-	r, c, err := s.repo.GetIssues(p)
+	if err := validateIssueQuery(q); err != nil {
+		return nil, 0, err
+	}
+	r, c, err := s.repo.GetIssues(p, q)
 	return r, c, err
 }
 
@@ -126,11 +293,21 @@ func (s *service) GetIssueTypes() ([]IssueType, error) {
 	return r, err
 }
 
+func (s *service) GetIssueWorklogs(issueID *string, p *Pagination) ([]IssueWorklog, int64, error) {
+	r, c, err := s.repo.GetIssueWorklogs(issueID, p)
+	return r, c, err
+}
+
 func (s *service) GetLabels(term *string) ([]Label, error) {
 	r, err := s.repo.GetLabels(term)
 	return r, err
 }
 
+func (s *service) GetNotifications(userID *string, unread bool, p *Pagination) ([]Notification, int64, error) {
+	r, c, err := s.repo.GetNotifications(userID, unread, p)
+	return r, c, err
+}
+
 func (s *service) GetPriorityTypes() ([]PriorityType, error) {
 	r, err := s.repo.GetPriorityTypes()
 	return r, err
@@ -142,12 +319,40 @@ func (s *service) GetProject(id string) (Project, error) {
 	return r, err
 }
 
-func (s *service) GetProjectIssues(projectID *string, p *Pagination) ([]Issue, int64, error) {
+func (s *service) GetProjectComponents(projectID *string) ([]Component, error) {
+	r, err := s.repo.GetProjectComponents(projectID)
+	return r, err
+}
+
+func (s *service) GetProjectSLAPolicies(projectID *string) ([]SLAPolicy, error) {
+	r, err := s.repo.GetProjectSLAPolicies(projectID)
+	return r, err
+}
+
+func (s *service) GetProjectIssues(projectID *string, p *Pagination, q *IssueQuery) ([]Issue, int64, error) {
 	// TODO: Validation for GetProjectIssues
-	r, c, err := s.repo.GetProjectIssues(projectID, p)
+	if err := validateIssueQuery(q); err != nil {
+		return nil, 0, err
+	}
+	r, c, err := s.repo.GetProjectIssues(projectID, p, q)
 	return r, c, err
 }
 
+func (s *service) GetProjectIssueImport(projectID *string, importID *string) (*IssueImport, error) {
+	r, err := s.repo.GetProjectIssueImport(projectID, importID)
+	return r, err
+}
+
+func (s *service) GetProjectIssueSchedules(projectID *string) ([]IssueSchedule, error) {
+	r, err := s.repo.GetProjectIssueSchedules(projectID)
+	return r, err
+}
+
+func (s *service) GetProjectIssueTemplates(projectID *string, issueType string) ([]IssueTemplate, error) {
+	r, err := s.repo.GetProjectIssueTemplates(projectID, issueType)
+	return r, err
+}
+
 func (s *service) GetProjectBacklogIssues(projectID *string, p *Pagination) ([]Issue, int64, error) {
 	// TODO: Validation for GetProjectBacklogIssues
 	r, c, err := s.repo.GetProjectBacklogIssues(projectID, p)
@@ -160,6 +365,21 @@ func (s *service) GetProjectBoard(projectID *string, boardID *string) (*Board, e
 	return b, err
 }
 
+func (s *service) GetProjectBoardIssues(projectID *string, boardID *string) (*BoardIssues, error) {
+	r, err := s.repo.GetProjectBoardIssues(projectID, boardID)
+	return r, err
+}
+
+func (s *service) GetProjectBoards(projectID *string) ([]Board, error) {
+	r, err := s.repo.GetProjectBoards(projectID)
+	return r, err
+}
+
+func (s *service) GetProjectIssueTypes(projectID *string) ([]IssueType, error) {
+	r, err := s.repo.GetProjectIssueTypes(projectID)
+	return r, err
+}
+
 func (s *service) GetProjects(p *Pagination) ([]Project, int64, error) {
 	// TODO: Validation for GetProjects
 	r, c, err := s.repo.GetProjects(p)
@@ -177,6 +397,20 @@ func (s *service) GetProjectTypes(term *string) ([]ProjectType, error) {
 	return r, err
 }
 
+func (s *service) GetProjectVersionNotes(projectID *string, versionID *string) (*ReleaseNotes, error) {
+	r, err := s.repo.GetProjectVersionNotes(projectID, versionID)
+	if err != nil {
+		return r, err
+	}
+	r.Markdown = r.markdown()
+	return r, nil
+}
+
+func (s *service) GetProjectVersions(projectID *string) ([]Version, error) {
+	r, err := s.repo.GetProjectVersions(projectID)
+	return r, err
+}
+
 func (s *service) GetUsers(term *string) ([]User, error) {
 	r, err := s.repo.GetUsers(term)
 	return r, err
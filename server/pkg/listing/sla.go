@@ -0,0 +1,45 @@
+package listing
+
+import "time"
+
+// SLAPolicy defines the listing form of a project SLA policy entity.
+type SLAPolicy struct {
+	ID              string      `json:"id"`
+	Name            string      `json:"name"`
+	StartCategories []string    `json:"startCategories"`
+	PauseCategories []string    `json:"pauseCategories"`
+	StopCategories  []string    `json:"stopCategories"`
+	Targets         []SLATarget `json:"targets"`
+	CreatedAt       time.Time   `json:"createdAt"`
+	UpdatedAt       time.Time   `json:"updatedAt"`
+}
+
+// SLATarget defines the listing form of the time an SLA policy allows issues of a priority. Durations are in seconds.
+type SLATarget struct {
+	Priority string `json:"priority"`
+	Duration int64  `json:"duration"`
+}
+
+// IssueSLA defines the listing form of an SLA policy's clock for an issue. Times are in seconds, and the remaining time
+// is negative once the target has been breached.
+type IssueSLA struct {
+	PolicyID  string     `json:"policyId"`
+	Name      string     `json:"name"`
+	State     string     `json:"state"`
+	Target    int64      `json:"target"`
+	Elapsed   int64      `json:"elapsed"`
+	Remaining int64      `json:"remaining"`
+	Breached  bool       `json:"breached"`
+	BreachAt  *time.Time `json:"breachAt,omitempty"`
+}
+
+// Notification defines the listing form of a user notification entity.
+type Notification struct {
+	ID        string     `json:"id"`
+	ProjectID string     `json:"projectId,omitempty"`
+	IssueID   string     `json:"issueId,omitempty"`
+	Type      string     `json:"type"`
+	Message   string     `json:"message"`
+	ReadAt    *time.Time `json:"readAt,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
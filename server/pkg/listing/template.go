@@ -0,0 +1,17 @@
+package listing
+
+import "time"
+
+// IssueTemplate defines the listing form of a project issue template entity.
+type IssueTemplate struct {
+	ID            string                 `json:"id"`
+	Name          string                 `json:"name"`
+	IssueType     string                 `json:"issueType"`
+	SummaryPrefix string                 `json:"summaryPrefix,omitempty"`
+	Description   string                 `json:"description,omitempty"`
+	Priority      string                 `json:"priority,omitempty"`
+	Labels        []string               `json:"labels,omitempty"`
+	CustomFields  map[string]interface{} `json:"customFields,omitempty"`
+	CreatedAt     time.Time              `json:"createdAt"`
+	UpdatedAt     time.Time              `json:"updatedAt"`
+}
@@ -0,0 +1,55 @@
+package listing
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Version defines the listing form of a project version entity.
+type Version struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Status      string     `json:"status"`
+	StartAt     *time.Time `json:"startAt,omitempty"`
+	ReleaseAt   *time.Time `json:"releaseAt,omitempty"`
+	ReleasedAt  *time.Time `json:"releasedAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// ReleaseNotes defines the listing form of the release notes of a project version.
+type ReleaseNotes struct {
+	Version  Version             `json:"version"`
+	Groups   []ReleaseNotesGroup `json:"groups"`
+	Markdown string              `json:"markdown"`
+}
+
+// ReleaseNotesGroup defines the listing form of the issues of one issue type in a set of release notes.
+type ReleaseNotesGroup struct {
+	IssueType string  `json:"issueType"`
+	Name      string  `json:"name"`
+	Issues    []Issue `json:"issues"`
+}
+
+func (rn *ReleaseNotes) markdown() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %v\n", rn.Version.Name)
+	if rn.Version.ReleasedAt != nil {
+		fmt.Fprintf(&b, "\nReleased %v\n", rn.Version.ReleasedAt.Format("2006-01-02"))
+	}
+	if len(rn.Version.Description) > 0 {
+		fmt.Fprintf(&b, "\n%v\n", rn.Version.Description)
+	}
+
+	for _, g := range rn.Groups {
+		fmt.Fprintf(&b, "\n## %v\n\n", g.Name)
+		for _, i := range g.Issues {
+			fmt.Fprintf(&b, "- [%v] %v\n", i.ProjectRef, i.Summary)
+		}
+	}
+
+	return b.String()
+}
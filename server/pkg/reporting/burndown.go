@@ -0,0 +1,178 @@
+package reporting
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Burndown modes, the value each issue contributes to a burndown.
+const (
+	StoryPoints  = "STORY_POINTS"
+	IssueCount   = "ISSUE_COUNT"
+	TimeEstimate = "TIME_ESTIMATE"
+)
+
+// Scope change types.
+const (
+	ScopeAdded           = "ADDED"
+	ScopeRemoved         = "REMOVED"
+	ScopeEstimateChanged = "ESTIMATE_CHANGED"
+)
+
+// Burndown defines the burndown and burnup data of a sprint.
+type Burndown struct {
+	SprintID     string        `json:"sprintId"`
+	Mode         string        `json:"mode"`
+	StartAt      time.Time     `json:"startAt"`
+	EndAt        time.Time     `json:"endAt"`
+	Committed    int64         `json:"committed"`
+	Days         []BurndownDay `json:"days"`
+	ScopeChanges []ScopeChange `json:"scopeChanges"`
+}
+
+// BurndownDay defines the state of a sprint at the end of a day. Values are in the unit of the burndown's mode.
+type BurndownDay struct {
+	Date            time.Time `json:"date"`
+	Remaining       int64     `json:"remaining"`
+	Completed       int64     `json:"completed"`
+	Scope           int64     `json:"scope"`
+	Guideline       float64   `json:"guideline"`
+	RemainingIssues int       `json:"remainingIssues"`
+	CompletedIssues int       `json:"completedIssues"`
+}
+
+// ScopeChange defines a change to the scope of a sprint after it started.
+type ScopeChange struct {
+	Date       time.Time `json:"date"`
+	IssueID    string    `json:"issueId"`
+	ProjectRef string    `json:"projectRef"`
+	Type       string    `json:"type"`
+	Value      int64     `json:"value"`
+}
+
+const day = 24 * time.Hour
+
+func (st *IssueState) value(mode string) int64 {
+	switch mode {
+	case IssueCount:
+		return 1
+	case TimeEstimate:
+		return st.OriginalEstimate
+	}
+	return st.Points
+}
+
+func validateBurndownMode(mode string) error {
+	switch mode {
+	case StoryPoints, IssueCount, TimeEstimate:
+		return nil
+	}
+	return fmt.Errorf("Unknown burndown mode %v", mode)
+}
+
+// buildBurndown replays the history of the issues that have been in a sprint to build its daily burndown series, up
+// to the sprint's completion or now.
+func buildBurndown(sprint *Sprint, histories []IssueHistory, categories map[string]string, mode string, now time.Time) (*Burndown, error) {
+	if sprint.StartAt.IsZero() || sprint.EndAt.IsZero() {
+		return nil, fmt.Errorf("Sprint %v has no start and end dates", sprint.Name)
+	}
+
+	committedAt := sprint.StartAt
+	if sprint.StartedAt != nil {
+		committedAt = *sprint.StartedAt
+	}
+
+	until := now
+	if sprint.CompletedAt != nil && sprint.CompletedAt.Before(until) {
+		until = *sprint.CompletedAt
+	}
+
+	b := &Burndown{
+		SprintID:     sprint.ID,
+		Mode:         mode,
+		StartAt:      sprint.StartAt,
+		EndAt:        sprint.EndAt,
+		Days:         []BurndownDay{},
+		ScopeChanges: []ScopeChange{},
+	}
+
+	snapshot := func(t time.Time) BurndownDay {
+		d := BurndownDay{}
+		for i := range histories {
+			st, ok := histories[i].stateAt(t)
+			if !ok || st.SprintID != sprint.ID {
+				continue
+			}
+			v := st.value(mode)
+			d.Scope += v
+			if categories[st.Status] == "DONE" {
+				d.Completed += v
+				d.CompletedIssues++
+			} else {
+				d.Remaining += v
+				d.RemainingIssues++
+			}
+		}
+		return d
+	}
+
+	b.Committed = snapshot(committedAt).Scope
+
+	first := sprint.StartAt.UTC().Truncate(day)
+	last := sprint.EndAt.UTC().Truncate(day)
+	total := last.Sub(first).Hours() / 24
+
+	for date := first; !date.After(last) && !date.After(until); date = date.Add(day) {
+		t := date.Add(day - time.Nanosecond)
+		if t.After(until) {
+			t = until
+		}
+
+		d := snapshot(t)
+		d.Date = date
+		if total > 0 {
+			d.Guideline = float64(b.Committed) * (1 - date.Sub(first).Hours()/24/total)
+		}
+
+		b.Days = append(b.Days, d)
+	}
+
+	for i := range histories {
+		h := &histories[i]
+
+		if h.CreatedAt.After(committedAt) && !h.CreatedAt.After(until) {
+			if st, _ := h.stateAt(h.CreatedAt); st.SprintID == sprint.ID {
+				b.ScopeChanges = append(b.ScopeChanges, ScopeChange{h.CreatedAt, h.ID, h.ProjectRef, ScopeAdded, st.value(mode)})
+			}
+		}
+
+		for j, c := range h.Changes {
+			if !c.CreatedAt.After(committedAt) || c.CreatedAt.After(until) {
+				continue
+			}
+			// Changes written by the same update are compared as one
+			if j > 0 && h.Changes[j-1].CreatedAt.Equal(c.CreatedAt) {
+				continue
+			}
+
+			before, _ := h.stateAt(c.CreatedAt.Add(-time.Nanosecond))
+			after, _ := h.stateAt(c.CreatedAt)
+
+			switch {
+			case before.SprintID != sprint.ID && after.SprintID == sprint.ID:
+				b.ScopeChanges = append(b.ScopeChanges, ScopeChange{c.CreatedAt, h.ID, h.ProjectRef, ScopeAdded, after.value(mode)})
+			case before.SprintID == sprint.ID && after.SprintID != sprint.ID:
+				b.ScopeChanges = append(b.ScopeChanges, ScopeChange{c.CreatedAt, h.ID, h.ProjectRef, ScopeRemoved, -before.value(mode)})
+			case after.SprintID == sprint.ID && before.value(mode) != after.value(mode):
+				b.ScopeChanges = append(b.ScopeChanges, ScopeChange{c.CreatedAt, h.ID, h.ProjectRef, ScopeEstimateChanged, after.value(mode) - before.value(mode)})
+			}
+		}
+	}
+
+	sort.Slice(b.ScopeChanges, func(i, j int) bool {
+		return b.ScopeChanges[i].Date.Before(b.ScopeChanges[j].Date)
+	})
+
+	return b, nil
+}
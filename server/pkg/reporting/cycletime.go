@@ -0,0 +1,184 @@
+package reporting
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// IssueCycleTime defines the lead time and cycle time of an issue. Lead time runs from creation, and cycle time from
+// the first move into the "IN_PROGRESS" category, until the issue was last moved into the "DONE" category.
+type IssueCycleTime struct {
+	IssueID        string     `json:"issueId"`
+	ProjectRef     string     `json:"projectRef"`
+	Type           string     `json:"type"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	StartedAt      *time.Time `json:"startedAt,omitempty"`
+	DoneAt         *time.Time `json:"doneAt,omitempty"`
+	LeadTimeHours  *float64   `json:"leadTimeHours,omitempty"`
+	CycleTimeHours *float64   `json:"cycleTimeHours,omitempty"`
+}
+
+// ControlChartQuery defines the reporting control chart request. Issues done within the date range, of any of the
+// types and with any of the labels, are included.
+type ControlChartQuery struct {
+	From   time.Time
+	To     time.Time
+	Types  []string
+	Labels []string
+}
+
+// ControlChart defines the cycle times of the issues done within a date range, and their percentiles.
+type ControlChart struct {
+	From      time.Time        `json:"from"`
+	To        time.Time        `json:"to"`
+	Issues    []IssueCycleTime `json:"issues"`
+	LeadTime  Percentiles      `json:"leadTime"`
+	CycleTime Percentiles      `json:"cycleTime"`
+}
+
+// Percentiles defines the 50th, 85th and 95th percentiles of a set of durations, in hours.
+type Percentiles struct {
+	P50 float64 `json:"p50"`
+	P85 float64 `json:"p85"`
+	P95 float64 `json:"p95"`
+}
+
+func validateControlChartQuery(q *ControlChartQuery) error {
+	if q == nil {
+		return fmt.Errorf("Control chart query is nil")
+	}
+	if q.To.Before(q.From) {
+		return fmt.Errorf("'to' must not be before 'from'")
+	}
+
+	return nil
+}
+
+// buildIssueCycleTime replays the status changes of an issue through the category of each status.
+func buildIssueCycleTime(h *IssueHistory, categories map[string]string) IssueCycleTime {
+	ct := IssueCycleTime{
+		IssueID:    h.ID,
+		ProjectRef: h.ProjectRef,
+		Type:       h.Type,
+		CreatedAt:  h.CreatedAt,
+	}
+
+	enter := func(status string, at time.Time) {
+		switch categories[status] {
+		case "IN_PROGRESS":
+			if ct.StartedAt == nil {
+				ct.StartedAt = &at
+			}
+			ct.DoneAt = nil
+		case "DONE":
+			if ct.DoneAt == nil {
+				ct.DoneAt = &at
+			}
+		default:
+			ct.DoneAt = nil
+		}
+	}
+
+	st, _ := h.stateAt(h.CreatedAt)
+	enter(st.Status, h.CreatedAt)
+
+	for _, c := range h.Changes {
+		if c.Field == "status" {
+			status, _ := c.To.(string)
+			enter(status, c.CreatedAt)
+		}
+	}
+
+	if ct.DoneAt != nil {
+		lead := ct.DoneAt.Sub(ct.CreatedAt).Hours()
+		ct.LeadTimeHours = &lead
+		if ct.StartedAt != nil {
+			cycle := ct.DoneAt.Sub(*ct.StartedAt).Hours()
+			ct.CycleTimeHours = &cycle
+		}
+	}
+
+	return ct
+}
+
+func (q *ControlChartQuery) matches(h *IssueHistory) bool {
+	if len(q.Types) > 0 && !contains(q.Types, h.Type) {
+		return false
+	}
+	if len(q.Labels) == 0 {
+		return true
+	}
+	for _, l := range h.Labels {
+		if contains(q.Labels, l) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildControlChart returns the cycle times of the issues done within the query's date range.
+func buildControlChart(histories []IssueHistory, categories map[string]string, q *ControlChartQuery) *ControlChart {
+	cc := &ControlChart{
+		From:   q.From,
+		To:     q.To,
+		Issues: []IssueCycleTime{},
+	}
+
+	var leadTimes, cycleTimes []float64
+	for i := range histories {
+		h := &histories[i]
+		if !q.matches(h) {
+			continue
+		}
+
+		ct := buildIssueCycleTime(h, categories)
+		if ct.DoneAt == nil || ct.DoneAt.Before(q.From) || ct.DoneAt.After(q.To) {
+			continue
+		}
+
+		cc.Issues = append(cc.Issues, ct)
+		leadTimes = append(leadTimes, *ct.LeadTimeHours)
+		if ct.CycleTimeHours != nil {
+			cycleTimes = append(cycleTimes, *ct.CycleTimeHours)
+		}
+	}
+
+	sort.Slice(cc.Issues, func(i, j int) bool {
+		return cc.Issues[i].DoneAt.Before(*cc.Issues[j].DoneAt)
+	})
+
+	cc.LeadTime = getPercentiles(leadTimes)
+	cc.CycleTime = getPercentiles(cycleTimes)
+
+	return cc
+}
+
+// getPercentiles returns the nearest-rank percentiles of a set of values.
+func getPercentiles(values []float64) Percentiles {
+	if len(values) == 0 {
+		return Percentiles{}
+	}
+
+	sort.Float64s(values)
+
+	rank := func(p float64) float64 {
+		i := int(math.Ceil(p/100*float64(len(values)))) - 1
+		if i < 0 {
+			i = 0
+		}
+		return values[i]
+	}
+
+	return Percentiles{P50: rank(50), P85: rank(85), P95: rank(95)}
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+package reporting
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// maxFlowDays is the longest date range a cumulative flow can be requested for.
+const maxFlowDays = 366
+
+// Board defines the reporting form of a board entity, its current columns and the changes made to them, oldest first.
+// Sprints is nil for boards without sprints.
+type Board struct {
+	ID            string
+	Name          string
+	Columns       []BoardColumn
+	ColumnChanges []BoardColumnChange
+	Sprints       []Sprint
+}
+
+// BoardColumn defines the reporting form of a board column.
+type BoardColumn struct {
+	Name          string
+	IssueStatuses []string
+	Ordinal       int32
+}
+
+// BoardColumnChange defines the reporting form of a change to the columns of a board.
+type BoardColumnChange struct {
+	From      []BoardColumn
+	CreatedAt time.Time
+}
+
+// CumulativeFlowQuery defines the reporting cumulative flow request.
+type CumulativeFlowQuery struct {
+	From time.Time
+	To   time.Time
+}
+
+// CumulativeFlow defines the daily number of issues in each column of a board.
+type CumulativeFlow struct {
+	BoardID string              `json:"boardId"`
+	From    time.Time           `json:"from"`
+	To      time.Time           `json:"to"`
+	Columns []string            `json:"columns"`
+	Days    []CumulativeFlowDay `json:"days"`
+}
+
+// CumulativeFlowDay defines the number of issues in each column of a board at the end of a day, keyed by column name.
+type CumulativeFlowDay struct {
+	Date   time.Time      `json:"date"`
+	Counts map[string]int `json:"counts"`
+}
+
+func validateCumulativeFlowQuery(q *CumulativeFlowQuery) error {
+	if q == nil {
+		return fmt.Errorf("Cumulative flow query is nil")
+	}
+	if q.To.Before(q.From) {
+		return fmt.Errorf("'to' must not be before 'from'")
+	}
+	if q.To.Sub(q.From) > maxFlowDays*day {
+		return fmt.Errorf("Cumulative flow is limited to %v days", maxFlowDays)
+	}
+
+	return nil
+}
+
+// columnsAt replays the column changes of a board back to a point in time.
+func (b *Board) columnsAt(t time.Time) []BoardColumn {
+	for _, c := range b.ColumnChanges {
+		if c.CreatedAt.After(t) {
+			return c.From
+		}
+	}
+	return b.Columns
+}
+
+// activeSprintsAt returns the ids of the sprints of a board that were active at a point in time, whose issues were the
+// only ones on it. It returns nil for boards without sprints, which show every issue of their project.
+func (b *Board) activeSprintsAt(t time.Time) map[string]bool {
+	if b.Sprints == nil {
+		return nil
+	}
+
+	results := make(map[string]bool)
+	for _, sprint := range b.Sprints {
+		if sprint.StartedAt == nil || sprint.StartedAt.After(t) {
+			continue
+		}
+		if sprint.CompletedAt != nil && !sprint.CompletedAt.After(t) {
+			continue
+		}
+		results[sprint.ID] = true
+	}
+
+	return results
+}
+
+// buildCumulativeFlow counts the issues in each column of a board at the end of every day in a date range, using the
+// status of each issue and the column mapping of the board as they were on that day. Issues on sprintable boards are
+// only counted on the days they were in one of the board's active sprints.
+func buildCumulativeFlow(b *Board, histories []IssueHistory, q *CumulativeFlowQuery, now time.Time) *CumulativeFlow {
+	cf := &CumulativeFlow{
+		BoardID: b.ID,
+		From:    q.From.UTC().Truncate(day),
+		To:      q.To.UTC().Truncate(day),
+		Columns: []string{},
+		Days:    []CumulativeFlowDay{},
+	}
+
+	seen := make(map[string]bool)
+	addColumns := func(columns []BoardColumn) {
+		sorted := append([]BoardColumn{}, columns...)
+		sort.Slice(sorted, func(i, j int) bool {
+			return sorted[i].Ordinal < sorted[j].Ordinal
+		})
+		for _, c := range sorted {
+			if !seen[c.Name] {
+				seen[c.Name] = true
+				cf.Columns = append(cf.Columns, c.Name)
+			}
+		}
+	}
+
+	for date := cf.From; !date.After(cf.To) && !date.After(now); date = date.Add(day) {
+		t := date.Add(day - time.Nanosecond)
+		if t.After(now) {
+			t = now
+		}
+
+		columns := b.columnsAt(t)
+		addColumns(columns)
+
+		activeSprints := b.activeSprintsAt(t)
+
+		statusColumns := make(map[string]string)
+		for _, c := range columns {
+			for _, status := range c.IssueStatuses {
+				statusColumns[status] = c.Name
+			}
+		}
+
+		d := CumulativeFlowDay{Date: date, Counts: make(map[string]int)}
+		for _, c := range columns {
+			d.Counts[c.Name] = 0
+		}
+
+		for i := range histories {
+			st, ok := histories[i].stateAt(t)
+			if !ok {
+				continue
+			}
+			if activeSprints != nil && !activeSprints[st.SprintID] {
+				continue
+			}
+			if column, ok := statusColumns[st.Status]; ok {
+				d.Counts[column]++
+			}
+		}
+
+		cf.Days = append(cf.Days, d)
+	}
+
+	return cf
+}
@@ -0,0 +1,66 @@
+package reporting
+
+import "time"
+
+// IssueChange defines the reporting form of a change to one of the tracked fields of an issue.
+type IssueChange struct {
+	Field     string
+	From      interface{}
+	To        interface{}
+	CreatedAt time.Time
+}
+
+// IssueState defines the tracked fields of an issue at a point in time.
+type IssueState struct {
+	Status            string
+	SprintID          string
+	Points            int64
+	OriginalEstimate  int64
+	RemainingEstimate int64
+}
+
+// IssueHistory defines the reporting form of an issue, its current state and the changes to its tracked fields,
+// oldest first.
+type IssueHistory struct {
+	ID         string
+	ProjectRef string
+	Type       string
+	AssigneeID string
+	Labels     []string
+	CreatedAt  time.Time
+	State      IssueState
+	Changes    []IssueChange
+}
+
+func (st *IssueState) set(field string, v interface{}) {
+	switch field {
+	case "status":
+		st.Status, _ = v.(string)
+	case "sprintId":
+		st.SprintID, _ = v.(string)
+	case "points":
+		st.Points, _ = v.(int64)
+	case "originalEstimate":
+		st.OriginalEstimate, _ = v.(int64)
+	case "remainingEstimate":
+		st.RemainingEstimate, _ = v.(int64)
+	}
+}
+
+// stateAt replays the changes of an issue back to a point in time. It returns false where the issue did not yet exist.
+func (h *IssueHistory) stateAt(t time.Time) (IssueState, bool) {
+	if h.CreatedAt.After(t) {
+		return IssueState{}, false
+	}
+
+	st := h.State
+	for i := len(h.Changes) - 1; i >= 0; i-- {
+		c := h.Changes[i]
+		if !c.CreatedAt.After(t) {
+			break
+		}
+		st.set(c.Field, c.From)
+	}
+
+	return st, true
+}
@@ -0,0 +1,223 @@
+package reporting
+
+import "time"
+
+// Service provides entity reporting operations.
+type Service interface {
+	// GetIssueCycleTime returns the lead time and cycle time of an issue.
+	GetIssueCycleTime(*string) (*IssueCycleTime, error)
+	// GetProjectBoardCumulativeFlow returns the daily number of issues in each column of a board.
+	GetProjectBoardCumulativeFlow(*string, *string, *CumulativeFlowQuery) (*CumulativeFlow, error)
+	// GetProjectBoardVelocity returns the committed and completed points of a board's last completed sprints.
+	GetProjectBoardVelocity(*string, *string, *VelocityQuery) (*Velocity, error)
+	// GetProjectControlChart returns the lead times and cycle times of a project's issues done within a date range.
+	GetProjectControlChart(*string, *ControlChartQuery) (*ControlChart, error)
+	// GetProjectEpicTimeTracking returns the estimates and time spent rolled up over an epic and its issues.
+	GetProjectEpicTimeTracking(*string, *string) (*TimeTracking, error)
+	// GetProjectSprintBurndown returns the burndown and burnup data of a sprint.
+	GetProjectSprintBurndown(*string, *string, string) (*Burndown, error)
+	// GetProjectSprintTimeTracking returns the estimates and time spent rolled up over the issues of a sprint.
+	GetProjectSprintTimeTracking(*string, *string) (*TimeTracking, error)
+	// GetUserTimesheet returns the time a user logged each day of a date range.
+	GetUserTimesheet(*string, *TimesheetQuery) (*Timesheet, error)
+}
+
+// Repository provides access to the reporting repository.
+type Repository interface {
+	// GetIssueHistory returns the history of an issue.
+	GetIssueHistory(*string) (*IssueHistory, error)
+	// GetIssueStatusCategories returns the category of every issue status, keyed by issue status id.
+	GetIssueStatusCategories() (map[string]string, error)
+	// GetProjectBoardColumnHistory returns a project's board, and the changes made to its columns.
+	GetProjectBoardColumnHistory(*string, *string) (*Board, error)
+	// GetProjectBoardIssueHistories returns the history of every issue matching a board's filters created before a
+	// point in time.
+	GetProjectBoardIssueHistories(*string, *string, time.Time) ([]IssueHistory, error)
+	// GetProjectBoardSprints returns the sprints of a project's board.
+	GetProjectBoardSprints(*string, *string) ([]Sprint, error)
+	// GetProjectEpicIssueTimes returns the estimates and time spent of an epic and of each of its issues.
+	GetProjectEpicIssueTimes(*string, *string) ([]IssueTime, error)
+	// GetProjectIssueHistories returns the history of every issue of a project created before a point in time.
+	GetProjectIssueHistories(*string, time.Time) ([]IssueHistory, error)
+	// GetProjectSprint returns a sprint of one of a project's boards.
+	GetProjectSprint(*string, *string) (*Sprint, error)
+	// GetProjectSprintIssueHistories returns the history of every issue that has been in a sprint.
+	GetProjectSprintIssueHistories(*string, *string) ([]IssueHistory, error)
+	// GetProjectSprintIssueTimes returns the estimates and time spent of each issue in a sprint.
+	GetProjectSprintIssueTimes(*string, *string) ([]IssueTime, error)
+	// GetUserWorklogs returns the work a user logged between two points in time.
+	GetUserWorklogs(*string, time.Time, time.Time) ([]Worklog, error)
+}
+
+type service struct {
+	repo Repository
+}
+
+// NewService creates a reporting service with the necessary dependencies.
+func NewService(r Repository) Service {
+	return &service{r}
+}
+
+// GetIssueCycleTime returns the lead time and cycle time of an issue, which are only set once it is done.
+func (s *service) GetIssueCycleTime(issueID *string) (*IssueCycleTime, error) {
+	history, err := s.repo.GetIssueHistory(issueID)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.repo.GetIssueStatusCategories()
+	if err != nil {
+		return nil, err
+	}
+
+	ct := buildIssueCycleTime(history, categories)
+
+	return &ct, nil
+}
+
+// GetProjectBoardCumulativeFlow returns the daily number of issues in each column of a board over a date range.
+func (s *service) GetProjectBoardCumulativeFlow(projectID *string, boardID *string, q *CumulativeFlowQuery) (*CumulativeFlow, error) {
+	err := validateCumulativeFlowQuery(q)
+	if err != nil {
+		return nil, err
+	}
+
+	board, err := s.repo.GetProjectBoardColumnHistory(projectID, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	histories, err := s.repo.GetProjectBoardIssueHistories(projectID, boardID, q.To.UTC().Truncate(day).Add(day))
+	if err != nil {
+		return nil, err
+	}
+
+	return buildCumulativeFlow(board, histories, q, time.Now()), nil
+}
+
+// GetProjectBoardVelocity returns the committed and completed points of a board's last completed sprints, with their
+// rolling average and standard deviation.
+func (s *service) GetProjectBoardVelocity(projectID *string, boardID *string, q *VelocityQuery) (*Velocity, error) {
+	err := validateVelocityQuery(q)
+	if err != nil {
+		return nil, err
+	}
+
+	sprints, err := s.repo.GetProjectBoardSprints(projectID, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.repo.GetIssueStatusCategories()
+	if err != nil {
+		return nil, err
+	}
+
+	v := &Velocity{BoardID: *boardID, Sprints: []SprintVelocity{}}
+
+	for _, sprint := range lastCompletedSprints(sprints, q.Sprints) {
+		histories, err := s.repo.GetProjectSprintIssueHistories(projectID, &sprint.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		v.Sprints = append(v.Sprints, buildSprintVelocity(&sprint, histories, categories, q))
+	}
+
+	v.summarise()
+
+	return v, nil
+}
+
+// GetProjectControlChart returns the lead times and cycle times of a project's issues done within a date range, with
+// their 50th, 85th and 95th percentiles.
+func (s *service) GetProjectControlChart(projectID *string, q *ControlChartQuery) (*ControlChart, error) {
+	err := validateControlChartQuery(q)
+	if err != nil {
+		return nil, err
+	}
+
+	histories, err := s.repo.GetProjectIssueHistories(projectID, q.To)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.repo.GetIssueStatusCategories()
+	if err != nil {
+		return nil, err
+	}
+
+	return buildControlChart(histories, categories, q), nil
+}
+
+// GetProjectSprintBurndown returns the burndown and burnup data of a sprint, in story points, issue count or time
+// estimate mode.
+func (s *service) GetProjectSprintBurndown(projectID *string, sprintID *string, mode string) (*Burndown, error) {
+	if len(mode) == 0 {
+		mode = StoryPoints
+	}
+
+	err := validateBurndownMode(mode)
+	if err != nil {
+		return nil, err
+	}
+
+	sprint, err := s.repo.GetProjectSprint(projectID, sprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	histories, err := s.repo.GetProjectSprintIssueHistories(projectID, sprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.repo.GetIssueStatusCategories()
+	if err != nil {
+		return nil, err
+	}
+
+	return buildBurndown(sprint, histories, categories, mode, time.Now())
+}
+
+// GetProjectEpicTimeTracking returns the estimates and time spent rolled up over an epic and the issues grouped under
+// it.
+func (s *service) GetProjectEpicTimeTracking(projectID *string, epicID *string) (*TimeTracking, error) {
+	issues, err := s.repo.GetProjectEpicIssueTimes(projectID, epicID)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildTimeTracking(issues), nil
+}
+
+// GetProjectSprintTimeTracking returns the estimates and time spent rolled up over the issues of a sprint.
+func (s *service) GetProjectSprintTimeTracking(projectID *string, sprintID *string) (*TimeTracking, error) {
+	_, err := s.repo.GetProjectSprint(projectID, sprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	issues, err := s.repo.GetProjectSprintIssueTimes(projectID, sprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildTimeTracking(issues), nil
+}
+
+// GetUserTimesheet returns the time a user logged each day of a date range, in total and per issue.
+func (s *service) GetUserTimesheet(userID *string, q *TimesheetQuery) (*Timesheet, error) {
+	err := validateTimesheetQuery(q)
+	if err != nil {
+		return nil, err
+	}
+
+	from := q.From.UTC().Truncate(day)
+	worklogs, err := s.repo.GetUserWorklogs(userID, from, q.To.UTC().Truncate(day).Add(day))
+	if err != nil {
+		return nil, err
+	}
+
+	return buildTimesheet(*userID, worklogs, q), nil
+}
@@ -0,0 +1,15 @@
+package reporting
+
+import "time"
+
+// Sprint defines the reporting form of a sprint entity.
+type Sprint struct {
+	ID          string
+	BoardID     string
+	Name        string
+	State       string
+	StartAt     time.Time
+	EndAt       time.Time
+	StartedAt   *time.Time
+	CompletedAt *time.Time
+}
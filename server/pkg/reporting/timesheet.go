@@ -0,0 +1,112 @@
+package reporting
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// maxTimesheetDays is the longest date range a timesheet can be requested for.
+const maxTimesheetDays = 92
+
+// Worklog defines the reporting form of an issue work log entry, with the issue it was logged against.
+type Worklog struct {
+	IssueID    string
+	ProjectID  string
+	ProjectRef string
+	Summary    string
+	StartedAt  time.Time
+	Duration   int64
+}
+
+// TimesheetQuery defines the reporting timesheet request. Dates are whole UTC days, and both are included.
+type TimesheetQuery struct {
+	From time.Time
+	To   time.Time
+}
+
+// Timesheet defines the time a user logged each day of a date range, in seconds, in total and per issue.
+type Timesheet struct {
+	UserID string           `json:"userId"`
+	From   time.Time        `json:"from"`
+	To     time.Time        `json:"to"`
+	Total  int64            `json:"total"`
+	Days   []TimesheetDay   `json:"days"`
+	Issues []TimesheetIssue `json:"issues"`
+}
+
+// TimesheetDay defines the time a user logged on a day.
+type TimesheetDay struct {
+	Date  time.Time `json:"date"`
+	Total int64     `json:"total"`
+}
+
+// TimesheetIssue defines the time a user logged against an issue, with one entry per day of the timesheet.
+type TimesheetIssue struct {
+	IssueID    string  `json:"issueId"`
+	ProjectID  string  `json:"projectId"`
+	ProjectRef string  `json:"projectRef"`
+	Summary    string  `json:"summary"`
+	Total      int64   `json:"total"`
+	Days       []int64 `json:"days"`
+}
+
+func validateTimesheetQuery(q *TimesheetQuery) error {
+	if q == nil {
+		return fmt.Errorf("Timesheet query is nil")
+	}
+	if q.To.Before(q.From) {
+		return fmt.Errorf("'to' must not be before 'from'")
+	}
+	if q.To.Sub(q.From) > maxTimesheetDays*day {
+		return fmt.Errorf("Timesheets are limited to %v days", maxTimesheetDays)
+	}
+
+	return nil
+}
+
+// buildTimesheet totals the work a user logged in a date range by day and by issue. Work is counted on the day it
+// was started.
+func buildTimesheet(userID string, worklogs []Worklog, q *TimesheetQuery) *Timesheet {
+	from := q.From.UTC().Truncate(day)
+	to := q.To.UTC().Truncate(day)
+	days := int(to.Sub(from)/day) + 1
+
+	ts := &Timesheet{UserID: userID, From: from, To: to, Days: []TimesheetDay{}, Issues: []TimesheetIssue{}}
+	for d := 0; d < days; d++ {
+		ts.Days = append(ts.Days, TimesheetDay{Date: from.Add(time.Duration(d) * day)})
+	}
+
+	issues := make(map[string]*TimesheetIssue)
+
+	for _, w := range worklogs {
+		d := int(w.StartedAt.UTC().Sub(from) / day)
+		if w.StartedAt.Before(from) || d >= days {
+			continue
+		}
+
+		ti, ok := issues[w.IssueID]
+		if !ok {
+			ti = &TimesheetIssue{
+				IssueID:    w.IssueID,
+				ProjectID:  w.ProjectID,
+				ProjectRef: w.ProjectRef,
+				Summary:    w.Summary,
+				Days:       make([]int64, days),
+			}
+			issues[w.IssueID] = ti
+		}
+
+		ti.Days[d] += w.Duration
+		ti.Total += w.Duration
+		ts.Days[d].Total += w.Duration
+		ts.Total += w.Duration
+	}
+
+	for _, ti := range issues {
+		ts.Issues = append(ts.Issues, *ti)
+	}
+	sort.Slice(ts.Issues, func(i, j int) bool { return ts.Issues[i].ProjectRef < ts.Issues[j].ProjectRef })
+
+	return ts
+}
@@ -0,0 +1,29 @@
+package reporting
+
+// IssueTime defines the reporting form of the estimates of an issue and the time spent on it, in seconds.
+type IssueTime struct {
+	ID                string
+	OriginalEstimate  int64
+	RemainingEstimate int64
+	TimeSpent         int64
+}
+
+// TimeTracking defines the estimates and time spent rolled up over the issues of an epic or a sprint, in seconds.
+type TimeTracking struct {
+	IssueCount        int   `json:"issueCount"`
+	OriginalEstimate  int64 `json:"originalEstimate"`
+	RemainingEstimate int64 `json:"remainingEstimate"`
+	TimeSpent         int64 `json:"timeSpent"`
+}
+
+func buildTimeTracking(issues []IssueTime) *TimeTracking {
+	tt := &TimeTracking{IssueCount: len(issues)}
+
+	for _, i := range issues {
+		tt.OriginalEstimate += i.OriginalEstimate
+		tt.RemainingEstimate += i.RemainingEstimate
+		tt.TimeSpent += i.TimeSpent
+	}
+
+	return tt
+}
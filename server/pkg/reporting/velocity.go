@@ -0,0 +1,168 @@
+package reporting
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Velocity breakdowns.
+const (
+	ByAssignee  = "assignee"
+	ByIssueType = "type"
+)
+
+// rollingSprints is the number of sprints each rolling average is taken over.
+const rollingSprints = 3
+
+// VelocityQuery defines the reporting velocity request.
+type VelocityQuery struct {
+	Sprints    int
+	Breakdowns []string
+}
+
+// Velocity defines the committed and completed points of a board's last completed sprints, oldest first.
+type Velocity struct {
+	BoardID           string           `json:"boardId"`
+	Sprints           []SprintVelocity `json:"sprints"`
+	Average           float64          `json:"average"`
+	StandardDeviation float64          `json:"standardDeviation"`
+}
+
+// SprintVelocity defines the committed and completed points of a completed sprint. Breakdowns use the current
+// assignee and issue type of each issue.
+type SprintVelocity struct {
+	SprintID       string                        `json:"sprintId"`
+	Name           string                        `json:"name"`
+	CompletedAt    *time.Time                    `json:"completedAt,omitempty"`
+	Committed      int64                         `json:"committed"`
+	Completed      int64                         `json:"completed"`
+	RollingAverage float64                       `json:"rollingAverage"`
+	Assignees      map[string]*VelocityBreakdown `json:"assignees,omitempty"`
+	IssueTypes     map[string]*VelocityBreakdown `json:"issueTypes,omitempty"`
+}
+
+// VelocityBreakdown defines the committed and completed points of part of a sprint.
+type VelocityBreakdown struct {
+	Committed int64 `json:"committed"`
+	Completed int64 `json:"completed"`
+}
+
+func validateVelocityQuery(q *VelocityQuery) error {
+	if q == nil {
+		return fmt.Errorf("Velocity query is nil")
+	}
+	if q.Sprints <= 0 {
+		return fmt.Errorf("'sprints' must be greater than 0")
+	}
+	for _, b := range q.Breakdowns {
+		if b != ByAssignee && b != ByIssueType {
+			return fmt.Errorf("Unknown velocity breakdown %v", b)
+		}
+	}
+
+	return nil
+}
+
+// lastCompletedSprints returns up to n closed sprints, ordered by completion, oldest first.
+func lastCompletedSprints(sprints []Sprint, n int) []Sprint {
+	results := []Sprint{}
+	for _, s := range sprints {
+		if s.State == "CLOSED" && s.CompletedAt != nil {
+			results = append(results, s)
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].CompletedAt.Before(*results[j].CompletedAt)
+	})
+
+	if len(results) > n {
+		results = results[len(results)-n:]
+	}
+
+	return results
+}
+
+// buildSprintVelocity replays the history of the issues that have been in a completed sprint. Issues in the sprint
+// when it started are committed, and those still in it and done when it completed are completed.
+func buildSprintVelocity(sprint *Sprint, histories []IssueHistory, categories map[string]string, q *VelocityQuery) SprintVelocity {
+	sv := SprintVelocity{
+		SprintID:    sprint.ID,
+		Name:        sprint.Name,
+		CompletedAt: sprint.CompletedAt,
+	}
+
+	committedAt := sprint.StartAt
+	if sprint.StartedAt != nil {
+		committedAt = *sprint.StartedAt
+	}
+
+	breakdowns := make(map[string]map[string]*VelocityBreakdown)
+	for _, b := range q.Breakdowns {
+		breakdowns[b] = make(map[string]*VelocityBreakdown)
+	}
+
+	for i := range histories {
+		h := &histories[i]
+
+		var committed, completed int64
+		if st, ok := h.stateAt(committedAt); ok && st.SprintID == sprint.ID {
+			committed = st.Points
+		}
+		if st, ok := h.stateAt(*sprint.CompletedAt); ok && st.SprintID == sprint.ID && categories[st.Status] == "DONE" {
+			completed = st.Points
+		}
+
+		sv.Committed += committed
+		sv.Completed += completed
+
+		for b, m := range breakdowns {
+			key := h.Type
+			if b == ByAssignee {
+				key = h.AssigneeID
+			}
+			if m[key] == nil {
+				m[key] = &VelocityBreakdown{}
+			}
+			m[key].Committed += committed
+			m[key].Completed += completed
+		}
+	}
+
+	sv.Assignees = breakdowns[ByAssignee]
+	sv.IssueTypes = breakdowns[ByIssueType]
+
+	return sv
+}
+
+// summarise sets the rolling average of each sprint, and the average and standard deviation of completed points.
+func (v *Velocity) summarise() {
+	if len(v.Sprints) == 0 {
+		return
+	}
+
+	var sum float64
+	for i := range v.Sprints {
+		sum += float64(v.Sprints[i].Completed)
+
+		var rolling float64
+		from := i - rollingSprints + 1
+		if from < 0 {
+			from = 0
+		}
+		for _, s := range v.Sprints[from : i+1] {
+			rolling += float64(s.Completed)
+		}
+		v.Sprints[i].RollingAverage = rolling / float64(i+1-from)
+	}
+
+	v.Average = sum / float64(len(v.Sprints))
+
+	var variance float64
+	for _, s := range v.Sprints {
+		variance += math.Pow(float64(s.Completed)-v.Average, 2)
+	}
+	v.StandardDeviation = math.Sqrt(variance / float64(len(v.Sprints)))
+}
@@ -2,10 +2,13 @@ package mongo
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/njehyde/issue-tracker/pkg/adding"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -31,7 +34,7 @@ func (s *Storage) AddBoard(b *adding.Board) (primitive.ObjectID, error) {
 		return objectID, err
 	}
 
-	// Set the board columns
+	// Set the board columns, from the template or the workflow steps unless the board has its own
 	var columns = []BoardColumn{}
 	for _, c := range w.Steps {
 		c := BoardColumn{
@@ -41,14 +44,53 @@ func (s *Storage) AddBoard(b *adding.Board) (primitive.ObjectID, error) {
 		}
 		columns = append(columns, c)
 	}
+	unmappedStatuses := b.UnmappedStatuses
+	if len(bt.Columns) > 0 && len(b.Columns) == 0 {
+		columns = bt.Columns
+		unmappedStatuses = bt.UnmappedStatuses
+	}
+	if len(b.Columns) > 0 {
+		columns = []BoardColumn{}
+		for i, c := range b.Columns {
+			columns = append(columns, BoardColumn{
+				Name:          c.Name,
+				Ordinal:       int32(i),
+				IssueStatuses: c.IssueStatuses,
+			})
+		}
+
+		if err = s.checkBoardStatuses(bt.WorkflowID, columns, b.UnmappedStatuses); err != nil {
+			return objectID, err
+		}
+	}
 
 	board := Board{
 		Type:             b.Type,
 		Name:             bt.Name,
+		Description:      b.Description,
 		IsBacklogVisible: bt.IsBacklogVisible,
 		IsBoardVisible:   bt.IsBoardVisible,
+		WorkflowID:       bt.WorkflowID,
 		Issues:           []primitive.ObjectID{},
 		Columns:          columns,
+		UnmappedStatuses: unmappedStatuses,
+	}
+
+	if len(b.Name) > 0 {
+		board.Name = b.Name
+	}
+	if b.IsBacklogVisible != nil {
+		board.IsBacklogVisible = *b.IsBacklogVisible
+	}
+	if b.IsBoardVisible != nil {
+		board.IsBoardVisible = *b.IsBoardVisible
+	}
+
+	for _, f := range b.Filters {
+		board.Filters = append(board.Filters, BoardFilter{Field: f.Field, Value: f.Value})
+	}
+	if _, err = s.getBoardIssueQuery(&board); err != nil {
+		return objectID, err
 	}
 
 	if bt.IsSprintable {
@@ -65,8 +107,135 @@ func (s *Storage) AddBoard(b *adding.Board) (primitive.ObjectID, error) {
 	return objectID, nil
 }
 
+// AddBoardType adds a board type entity to the database's "board_templates" collection.
+func (s *Storage) AddBoardType(bt *adding.BoardType) error {
+	ID := strings.ToUpper(strings.ReplaceAll(bt.Name, " ", "_"))
+
+	if _, err := s.repo.GetBoardTemplate(&ID); err == nil {
+		return fmt.Errorf("Board type %v already exists", ID)
+	}
+
+	if _, err := s.repo.GetWorkflow(&bt.WorkflowID); err != nil {
+		return fmt.Errorf("Workflow %v not found", bt.WorkflowID)
+	}
+
+	columns := []BoardColumn{}
+	for i, c := range bt.Columns {
+		columns = append(columns, BoardColumn{Name: c.Name, Ordinal: int32(i), IssueStatuses: c.IssueStatuses})
+	}
+	if len(columns) > 0 {
+		if err := s.checkBoardStatuses(bt.WorkflowID, columns, bt.UnmappedStatuses); err != nil {
+			return err
+		}
+	}
+
+	boardTemplate := BoardTemplate{
+		ID:               ID,
+		Name:             bt.Name,
+		IsSprintable:     bt.IsSprintable,
+		IsBacklogVisible: bt.IsBacklogVisible,
+		IsBoardVisible:   bt.IsBoardVisible,
+		WorkflowID:       bt.WorkflowID,
+		IsDefault:        bt.IsDefault,
+		Columns:          columns,
+		UnmappedStatuses: bt.UnmappedStatuses,
+	}
+
+	return s.addBoardTemplate(&boardTemplate)
+}
+
+// AddBoardTypeFromBoard adds a board type entity to the database's "board_templates" collection, copying the
+// workflow, columns, WIP limits and visibility of a project board.
+func (s *Storage) AddBoardTypeFromBoard(projectID *string, boardID *string, bt *adding.BoardTypeFromBoard) error {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return err
+	}
+
+	boardIDAsObjectID, err := primitive.ObjectIDFromHex(*boardID)
+	if err != nil {
+		return err
+	}
+
+	b, err := s.getProjectBoard(projectIDAsObjectID, boardIDAsObjectID)
+	if err != nil {
+		return err
+	}
+
+	ID := strings.ToUpper(strings.ReplaceAll(bt.Name, " ", "_"))
+
+	if _, err := s.repo.GetBoardTemplate(&ID); err == nil {
+		return fmt.Errorf("Board type %v already exists", ID)
+	}
+
+	w, err := s.getBoardWorkflow(b)
+	if err != nil {
+		return err
+	}
+
+	columns := append([]BoardColumn{}, b.Columns...)
+	sort.Slice(columns, func(i, j int) bool { return columns[i].Ordinal < columns[j].Ordinal })
+	for i := range columns {
+		columns[i].Ordinal = int32(i)
+	}
+
+	boardTemplate := BoardTemplate{
+		ID:               ID,
+		Name:             bt.Name,
+		IsSprintable:     b.Sprints != nil,
+		IsBacklogVisible: b.IsBacklogVisible,
+		IsBoardVisible:   b.IsBoardVisible,
+		WorkflowID:       w.ID,
+		IsDefault:        bt.IsDefault,
+		Columns:          columns,
+		UnmappedStatuses: b.UnmappedStatuses,
+	}
+
+	return s.addBoardTemplate(&boardTemplate)
+}
+
+// AddCustomField adds a custom field entity to the database's "custom_fields" collection.
+func (s *Storage) AddCustomField(cf *adding.CustomField) error {
+	projectIDs := []primitive.ObjectID{}
+	for _, id := range cf.ProjectIDs {
+		projectIDAsObjectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return err
+		}
+		projectIDs = append(projectIDs, projectIDAsObjectID)
+	}
+
+	issueTypes := cf.IssueTypes
+	if issueTypes == nil {
+		issueTypes = []string{}
+	}
+
+	customField := CustomField{
+		Name:        cf.Name,
+		Description: cf.Description,
+		Type:        cf.Type,
+		Options:     cf.Options,
+		IsRequired:  cf.IsRequired,
+		ProjectIDs:  projectIDs,
+		IssueTypes:  issueTypes,
+	}
+
+	err := s.repo.AddCustomField(&customField)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // AddIssue adds an issue entity to the database's "issues" collection.
 func (s *Storage) AddIssue(i *adding.Issue) error {
+	return s.addIssue(i, nil)
+}
+
+// addIssue adds an issue to the database's "issues" collection, letting prepare set any fields of the issue that the
+// adding form does not have before it is saved.
+func (s *Storage) addIssue(i *adding.Issue, prepare func(*Issue)) error {
 
 	// Load project to get the key
 	project, err := s.GetProjectByID(i.ProjectID)
@@ -74,6 +243,32 @@ func (s *Storage) AddIssue(i *adding.Issue) error {
 		return err
 	}
 
+	var projectIDAsObjectID primitive.ObjectID
+	if projectIDAsObjectID, err = primitive.ObjectIDFromHex(i.ProjectID); err != nil {
+		return err
+	}
+
+	// Fill in the issue from its template before it is validated
+	if len(i.TemplateID) > 0 {
+		p, err := s.repo.GetProject(projectIDAsObjectID)
+		if err != nil {
+			return err
+		}
+		if err = applyIssueTemplate(p, i); err != nil {
+			return err
+		}
+	}
+
+	// Validate the issue type and custom field values before the project counter is incremented
+	if err = s.checkIssueType(i.Type, project.IssueTypes); err != nil {
+		return err
+	}
+
+	customFields, err := s.getIssueCustomFieldValues(projectIDAsObjectID, i.Type, i.CustomFields)
+	if err != nil {
+		return err
+	}
+
 	// Increment project counter, then get the counter value
 	count, err := s.UpdateProjectCounter(project.Key)
 
@@ -98,33 +293,81 @@ func (s *Storage) AddIssue(i *adding.Issue) error {
 		return err
 	}
 
-	var projectIDAsObjectID primitive.ObjectID
-	if projectIDAsObjectID, err = primitive.ObjectIDFromHex(i.ProjectID); err != nil {
+	var reporterIDAsObjectID primitive.ObjectID
+	if reporterIDAsObjectID, err = primitive.ObjectIDFromHex(i.ReporterID); err != nil {
 		return err
 	}
 
-	var reporterIDAsObjectID primitive.ObjectID
-	if reporterIDAsObjectID, err = primitive.ObjectIDFromHex(i.ReporterID); err != nil {
+	p, err := s.repo.GetProject(projectIDAsObjectID)
+	if err != nil {
+		return err
+	}
+
+	components, err := getProjectComponentIDs(p, i.Components)
+	if err != nil {
+		return err
+	}
+
+	fixVersions, err := getProjectVersionIDs(p, i.FixVersions, nil)
+	if err != nil {
 		return err
 	}
 
+	var epicIDAsObjectID primitive.ObjectID
+	if len(i.EpicID) > 0 {
+		if i.Type == issueTypeEpic {
+			return fmt.Errorf("Epics cannot belong to an epic")
+		}
+		if epicIDAsObjectID, err = s.getProjectEpicID(projectIDAsObjectID, i.EpicID); err != nil {
+			return err
+		}
+	}
+
+	// Issues without an assignee are assigned from their components, or the project
 	var assigneeIDAsObjectID primitive.ObjectID
-	if assigneeIDAsObjectID, err = primitive.ObjectIDFromHex(i.AssigneeID); err != nil {
+	if len(i.AssigneeID) == 0 {
+		assigneeIDAsObjectID = getComponentAssignee(p, components)
+	} else if assigneeIDAsObjectID, err = primitive.ObjectIDFromHex(i.AssigneeID); err != nil {
+		return err
+	}
+
+	// New issues are ranked at the bottom of the backlog
+	ranks, err := s.getIssueListLastRanks(issueList{projectID: projectIDAsObjectID}, 1)
+	if err != nil {
 		return err
 	}
 
 	newIssue := Issue{
-		ProjectID:   projectIDAsObjectID,
-		ProjectRef:  projectRef,
-		Type:        i.Type,
-		Summary:     i.Summary,
-		Description: i.Description,
-		Status:      i.Status,
-		Priority:    i.Priority,
-		Points:      i.Points,
-		Labels:      labels,
-		ReporterID:  reporterIDAsObjectID,
-		AssigneeID:  assigneeIDAsObjectID,
+		ProjectID:         projectIDAsObjectID,
+		ProjectRef:        projectRef,
+		Rank:              ranks[0],
+		Type:              i.Type,
+		Summary:           i.Summary,
+		Description:       i.Description,
+		Status:            i.Status,
+		Priority:          i.Priority,
+		Points:            i.Points,
+		OriginalEstimate:  i.OriginalEstimate,
+		RemainingEstimate: i.OriginalEstimate,
+		DueDate:           i.DueDate,
+		Labels:            labels,
+		Components:        components,
+		FixVersions:       fixVersions,
+		ReporterID:        reporterIDAsObjectID,
+		AssigneeID:        assigneeIDAsObjectID,
+		EpicID:            epicIDAsObjectID,
+	}
+
+	if i.RemainingEstimate != nil {
+		newIssue.RemainingEstimate = *i.RemainingEstimate
+	}
+
+	if len(customFields) > 0 {
+		newIssue.CustomFields = customFields
+	}
+
+	if prepare != nil {
+		prepare(&newIssue)
 	}
 
 	err = s.repo.AddIssue(&newIssue)
@@ -190,6 +433,37 @@ func (s *Storage) AddIssueStatus(is *adding.IssueStatus) error {
 	return nil
 }
 
+// AddIssueType adds an issue type entity to the database's "issue_types" collection. Its id is its name in upper case,
+// with any character other than letters, digits and hyphens replaced by an underscore.
+func (s *Storage) AddIssueType(it *adding.IssueType) error {
+
+	ID := strings.Map(func(r rune) rune {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(it.Name))
+
+	if _, err := s.repo.GetIssueType(ID); err == nil {
+		return fmt.Errorf("Issue type %v already exists", ID)
+	}
+
+	issueType := IssueType{
+		ID:          ID,
+		Name:        it.Name,
+		Description: it.Description,
+		Icon:        it.Icon,
+		IsSubTask:   it.IsSubTask,
+	}
+
+	err := s.repo.AddIssueType(&issueType)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // AddPriorityType adds an priority type entity to the database's "priority_types" collection.
 func (s *Storage) AddPriorityType(pt *adding.PriorityType) error {
 
@@ -247,6 +521,9 @@ func (s *Storage) AddProject(p *adding.Project) error {
 		DefaultAssigneeID: defaultAssigneeIDAsObjectID,
 		DefaultBoardID:    boardID,
 		Boards:            boards,
+		IssueTypes:        []string{},
+		Components:        []Component{},
+		Versions:          []Version{},
 	}
 
 	// Add the project
@@ -266,6 +543,95 @@ func (s *Storage) AddProject(p *adding.Project) error {
 	return nil
 }
 
+// AddProjectComponent adds a component child entity to a project in the database's "projects" collection.
+func (s *Storage) AddProjectComponent(projectID *string, c *adding.Component) error {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return err
+	}
+
+	var leadIDAsObjectID primitive.ObjectID
+	if len(c.LeadID) > 0 {
+		if leadIDAsObjectID, err = primitive.ObjectIDFromHex(c.LeadID); err != nil {
+			return err
+		}
+		if _, err = s.repo.GetUserByID(&leadIDAsObjectID); err != nil {
+			return fmt.Errorf("User %v not found", c.LeadID)
+		}
+	}
+
+	component := Component{
+		Name:         c.Name,
+		Description:  c.Description,
+		LeadID:       leadIDAsObjectID,
+		AssigneeType: c.AssigneeType,
+	}
+
+	err = s.repo.AddProjectComponent(projectIDAsObjectID, &component)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AddProjectVersion adds a version child entity to a project in the database's "projects" collection.
+func (s *Storage) AddProjectVersion(projectID *string, v *adding.Version) error {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return err
+	}
+
+	version := Version{
+		Name:        v.Name,
+		Description: v.Description,
+		Status:      versionUnreleased,
+		StartAt:     v.StartAt,
+		ReleaseAt:   v.ReleaseAt,
+	}
+
+	err = s.repo.AddProjectVersion(projectIDAsObjectID, &version)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AddProjectBoard adds a board entity to the database's "boards" collection, and adds it to the boards of a project in
+// the database's "projects" collection.
+func (s *Storage) AddProjectBoard(projectID *string, b *adding.Board) error {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return err
+	}
+
+	if _, err = s.repo.GetProject(projectIDAsObjectID); err != nil {
+		return fmt.Errorf("Project %v not found", *projectID)
+	}
+
+	boardID, err := s.AddBoard(b)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"updatedAt": time.Now(),
+		},
+		"$push": bson.M{
+			"boards": boardID,
+		},
+	}
+
+	err = s.repo.UpdateProject(projectIDAsObjectID, update)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // AddProjectBoardSprint adds a sprint child entity to a target board in the database's "boards" collection.
 func (s *Storage) AddProjectBoardSprint(projectID *string, boardID *string, userID *string) error {
 	var err error
@@ -19,8 +19,14 @@ type Board struct {
 	Description      string               `bson:"description"`
 	IsBacklogVisible bool                 `bson:"isBacklogVisible"`
 	IsBoardVisible   bool                 `bson:"isBoardVisible"`
+	ParallelSprints  bool                 `bson:"parallelSprints"`
 	Issues           []primitive.ObjectID `bson:"issues"`
+	Filters          []BoardFilter        `bson:"filters,omitempty"`
+	WorkflowID       int32                `bson:"workflowId"`
 	Columns          []BoardColumn        `bson:"columns"`
+	UnmappedStatuses []string             `bson:"unmappedStatuses,omitempty"`
+	SwimlaneType     string               `bson:"swimlaneType,omitempty"`
+	SwimlaneQueries  []SwimlaneQuery      `bson:"swimlaneQueries,omitempty"`
 	Sprints          []Sprint             `bson:"sprints"`
 	CreatedAt        time.Time            `bson:"createdAt"`
 	UpdatedAt        time.Time            `bson:"updatedAt"`
@@ -47,6 +53,22 @@ func (r *Repository) AddBoard(b *Board) error {
 	return nil
 }
 
+// DeleteBoard ...
+func (r *Repository) DeleteBoard(ID primitive.ObjectID) error {
+	collection := r.db.Collection("boards")
+
+	filter := bson.M{"_id": ID}
+
+	deleteResult, err := collection.DeleteOne(context.Background(), filter)
+	if err != nil {
+		return err
+	}
+
+	slog.Infof("Deleted board %v: %+v", ID.Hex(), deleteResult)
+
+	return nil
+}
+
 // GetBoard ...
 func (r *Repository) GetBoard(id *primitive.ObjectID) (*Board, error) {
 	var b Board
@@ -92,22 +114,161 @@ func (r *Repository) GetBoardsByIds(ids *[]primitive.ObjectID) (*[]Board, error)
 	return &results, nil
 }
 
+// BoardFilter defines the storage form of a board issue filter, using the fields of an issue query.
+type BoardFilter struct {
+	Field string `bson:"field"`
+	Value string `bson:"value"`
+}
+
+// SwimlaneQuery defines the storage form of a named swimlane query, using the same filters as a board.
+type SwimlaneQuery struct {
+	Name    string        `bson:"name"`
+	Filters []BoardFilter `bson:"filters"`
+}
+
 // BoardColumn ...
 type BoardColumn struct {
 	Name          string   `bson:"name"`
 	IssueStatuses []string `bson:"issueStatuses"`
 	Ordinal       int32    `bson:"ordinal"`
+	MinWIP        int32    `bson:"minWip,omitempty"`
+	MaxWIP        int32    `bson:"maxWip,omitempty"`
+	WIPPolicy     string   `bson:"wipPolicy,omitempty"`
 }
 
-// BoardTemplate defines the storage form of an board template entity.
+// UpdateBoard ...
+func (r *Repository) UpdateBoard(ID primitive.ObjectID, update primitive.M) error {
+	collection := r.db.Collection("boards")
+
+	filter := bson.M{"_id": ID}
+
+	updateResult, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return fmt.Errorf("Board %v not found", ID.Hex())
+	}
+
+	slog.Infof("Updated board %v: %+v", ID.Hex(), updateResult)
+
+	return nil
+}
+
+// BoardColumnChange defines the storage form of a change to the columns of a board, so that reports can map issue
+// statuses onto the columns a board had at any point in time.
+type BoardColumnChange struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	BoardID   primitive.ObjectID `bson:"boardId"`
+	From      []BoardColumn      `bson:"from"`
+	To        []BoardColumn      `bson:"to"`
+	CreatedAt time.Time          `bson:"createdAt"`
+}
+
+// AddBoardColumnChange ...
+func (r *Repository) AddBoardColumnChange(c *BoardColumnChange) error {
+	collection := r.db.Collection("board_column_changes")
+
+	c.ID = primitive.NewObjectID()
+	c.CreatedAt = time.Now()
+
+	insertResult, err := collection.InsertOne(context.Background(), c)
+	if err != nil {
+		return err
+	}
+
+	slog.Infof("Added board column change %v: %+v", c.ID.Hex(), insertResult)
+
+	return nil
+}
+
+// GetBoardColumnChanges ...
+func (r *Repository) GetBoardColumnChanges(boardID primitive.ObjectID) (*[]BoardColumnChange, error) {
+	var changes = []BoardColumnChange{}
+
+	collection := r.db.Collection("board_column_changes")
+
+	filter := bson.M{"boardId": boardID}
+	findOptions := options.Find().SetSort(
+		bson.D{
+			primitive.E{Key: "createdAt", Value: 1},
+		},
+	)
+
+	cur, err := collection.Find(context.Background(), filter, findOptions)
+	defer cur.Close(context.Background())
+	if err != nil {
+		return &changes, err
+	}
+
+	for cur.Next(context.Background()) {
+		var c BoardColumnChange
+
+		err = cur.Decode(&c)
+		if err != nil {
+			return &changes, err
+		}
+
+		changes = append(changes, c)
+	}
+
+	return &changes, nil
+}
+
+// BoardTemplate defines the storage form of an board template entity. Templates without columns give their boards a
+// column for each step of the template's workflow.
 type BoardTemplate struct {
-	ID               string `bson:"_id"`
-	Name             string `bson:"name"`
-	IsSprintable     bool   `bson:"isSprintable"`
-	IsBacklogVisible bool   `bson:"isBacklogVisible"`
-	IsBoardVisible   bool   `bson:"isBoardVisible"`
-	WorkflowID       int32  `bson:"workflowId"`
-	IsDefault        bool   `bson:"isDefault"`
+	ID               string        `bson:"_id"`
+	Name             string        `bson:"name"`
+	IsSprintable     bool          `bson:"isSprintable"`
+	IsBacklogVisible bool          `bson:"isBacklogVisible"`
+	IsBoardVisible   bool          `bson:"isBoardVisible"`
+	WorkflowID       int32         `bson:"workflowId"`
+	IsDefault        bool          `bson:"isDefault"`
+	Columns          []BoardColumn `bson:"columns,omitempty"`
+	UnmappedStatuses []string      `bson:"unmappedStatuses,omitempty"`
+}
+
+// AddBoardTemplate ...
+func (r *Repository) AddBoardTemplate(bt *BoardTemplate) error {
+	collection := r.db.Collection("board_templates")
+
+	insertResult, err := collection.InsertOne(context.Background(), bt)
+	if err != nil {
+		return err
+	}
+
+	slog.Infof("Added board template %v: %+v", bt.ID, insertResult)
+
+	return nil
+}
+
+// CountBoards ...
+func (r *Repository) CountBoards(filter bson.M) (int64, error) {
+	collection := r.db.Collection("boards")
+
+	return collection.CountDocuments(context.Background(), filter)
+}
+
+// DeleteBoardTemplate ...
+func (r *Repository) DeleteBoardTemplate(ID string) error {
+	collection := r.db.Collection("board_templates")
+
+	filter := bson.M{"_id": ID}
+
+	deleteResult, err := collection.DeleteOne(context.Background(), filter)
+	if err != nil {
+		return err
+	}
+
+	if deleteResult.DeletedCount == 0 {
+		return fmt.Errorf("Board template %v could not be deleted", ID)
+	}
+
+	slog.Infof("Deleted board template %v: %+v", ID, deleteResult)
+
+	return nil
 }
 
 // GetBoardTemplate ...
@@ -158,6 +319,40 @@ func (r *Repository) GetBoardTemplates() (*[]BoardTemplate, error) {
 	return &boardTemplates, nil
 }
 
+// UpdateBoardTemplate ...
+func (r *Repository) UpdateBoardTemplate(ID string, update primitive.M) error {
+	collection := r.db.Collection("board_templates")
+
+	filter := bson.M{"_id": ID}
+
+	updateResult, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return fmt.Errorf("Board template %v not found", ID)
+	}
+
+	slog.Infof("Updated board template %v: %+v", ID, updateResult)
+
+	return nil
+}
+
+// UpdateBoardTemplates ...
+func (r *Repository) UpdateBoardTemplates(filter primitive.M, update primitive.M) error {
+	collection := r.db.Collection("board_templates")
+
+	updateResult, err := collection.UpdateMany(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	slog.Infof("Updated board templates: %+v", updateResult)
+
+	return nil
+}
+
 // WorkflowStep defines the listing form of a workflow step entity.
 type WorkflowStep struct {
 	Name       string   `bson:"name"`
@@ -166,12 +361,29 @@ type WorkflowStep struct {
 	StatusIds  []string `bson:"statusIds"`
 }
 
+// TransitionRule defines the storage form of a rule attached to a workflow transition.
+type TransitionRule struct {
+	Type   string            `bson:"type"`
+	Params map[string]string `bson:"params,omitempty"`
+}
+
+// WorkflowTransition defines the storage form of a workflow transition.
+type WorkflowTransition struct {
+	Name          string           `bson:"name"`
+	FromStatusIDs []string         `bson:"fromStatusIds"`
+	ToStatusID    string           `bson:"toStatusId"`
+	Conditions    []TransitionRule `bson:"conditions"`
+	Validators    []TransitionRule `bson:"validators"`
+	PostFunctions []TransitionRule `bson:"postFunctions"`
+}
+
 // Workflow defines the listing form of a workflow entity.
 type Workflow struct {
-	ID       int32          `bson:"_id"`
-	Name     string         `bson:"name"`
-	IsLocked bool           `bson:"isLocked"`
-	Steps    []WorkflowStep `bson:"steps"`
+	ID          int32                `bson:"_id"`
+	Name        string               `bson:"name"`
+	IsLocked    bool                 `bson:"isLocked"`
+	Steps       []WorkflowStep       `bson:"steps"`
+	Transitions []WorkflowTransition `bson:"transitions"`
 }
 
 // GetWorkflow ...
@@ -223,17 +435,54 @@ func (r *Repository) GetWorkflows() (*[]Workflow, error) {
 	return &workflows, nil
 }
 
+// UpdateWorkflow ...
+func (r *Repository) UpdateWorkflow(ID int32, update primitive.M) error {
+	collection := r.db.Collection("workflows")
+
+	filter := bson.M{"_id": ID}
+
+	updateResult, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return fmt.Errorf("Workflow %v not found", ID)
+	}
+
+	slog.Infof("Updated workflow %v: %+v", ID, updateResult)
+
+	return nil
+}
+
 // Sprint ...
 type Sprint struct {
-	ID        primitive.ObjectID `bson:"_id"`
-	Name      string             `bson:"name"`
-	Goal      string             `bson:"goal,omitempty"`
-	Ordinal   int32              `bson:"ordinal"`
-	StartAt   time.Time          `bson:"startAt,omitempty"`
-	EndAt     time.Time          `bson:"endAt,omitempty"`
-	CreatedAt time.Time          `bson:"createdAt,omitempty"`
-	UpdatedAt time.Time          `bson:"updatedAt,omitempty"`
-	CreatedBy primitive.ObjectID `bson:"createdBy"`
+	ID          primitive.ObjectID `bson:"_id"`
+	Name        string             `bson:"name"`
+	Goal        string             `bson:"goal,omitempty"`
+	Ordinal     int32              `bson:"ordinal"`
+	State       string             `bson:"state,omitempty"`
+	StartAt     time.Time          `bson:"startAt,omitempty"`
+	EndAt       time.Time          `bson:"endAt,omitempty"`
+	StartedAt   *time.Time         `bson:"startedAt,omitempty"`
+	CompletedAt *time.Time         `bson:"completedAt,omitempty"`
+	CreatedAt   time.Time          `bson:"createdAt,omitempty"`
+	UpdatedAt   time.Time          `bson:"updatedAt,omitempty"`
+	CreatedBy   primitive.ObjectID `bson:"createdBy"`
+}
+
+// Sprint states. Sprints without a state have not been started.
+const (
+	sprintFuture = "FUTURE"
+	sprintActive = "ACTIVE"
+	sprintClosed = "CLOSED"
+)
+
+func (s *Sprint) state() string {
+	if len(s.State) == 0 {
+		return sprintFuture
+	}
+	return s.State
 }
 
 // AddBoardSprint ...
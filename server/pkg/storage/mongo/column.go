@@ -0,0 +1,128 @@
+package mongo
+
+import (
+	"sort"
+	"time"
+
+	"github.com/njehyde/issue-tracker/pkg/updating"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetProjectBoardColumns returns the column configuration of a project's board, in ordinal order, from the database's
+// "boards" collection.
+func (s *Storage) GetProjectBoardColumns(projectID *string, boardID *string) (*updating.BoardColumns, error) {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	boardIDAsObjectID, err := primitive.ObjectIDFromHex(*boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	board, err := s.getProjectBoard(projectIDAsObjectID, boardIDAsObjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := append([]BoardColumn{}, board.Columns...)
+	sort.SliceStable(columns, func(i, j int) bool {
+		return columns[i].Ordinal < columns[j].Ordinal
+	})
+
+	result := updating.BoardColumns{
+		Columns:          []updating.BoardColumn{},
+		UnmappedStatuses: append([]string{}, board.UnmappedStatuses...),
+	}
+
+	for _, c := range columns {
+		result.Columns = append(result.Columns, updating.BoardColumn{
+			Name:          c.Name,
+			IssueStatuses: append([]string{}, c.IssueStatuses...),
+			MinWIP:        c.MinWIP,
+			MaxWIP:        c.MaxWIP,
+			WIPPolicy:     c.WIPPolicy,
+		})
+	}
+
+	return &result, nil
+}
+
+// UpdateProjectBoardColumns replaces the column configuration of a project's board in the database's "boards"
+// collection, and records the change in the database's "board_column_changes" collection.
+func (s *Storage) UpdateProjectBoardColumns(projectID *string, boardID *string, bc *updating.BoardColumns) error {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return err
+	}
+
+	boardIDAsObjectID, err := primitive.ObjectIDFromHex(*boardID)
+	if err != nil {
+		return err
+	}
+
+	board, err := s.getProjectBoard(projectIDAsObjectID, boardIDAsObjectID)
+	if err != nil {
+		return err
+	}
+
+	return s.updateBoardColumns(board, bc.Columns, bc.UnmappedStatuses, bson.M{})
+}
+
+// updateBoardColumns checks a board's new columns against its workflow, then saves them, numbered in order, with the
+// rest of an update. The columns the board had before are recorded so that reports can still use them.
+func (s *Storage) updateBoardColumns(b *Board, cs []updating.BoardColumn, unmapped []string, set bson.M) error {
+	columns := []BoardColumn{}
+	for i, c := range cs {
+		issueStatuses := c.IssueStatuses
+		if issueStatuses == nil {
+			issueStatuses = []string{}
+		}
+
+		columns = append(columns, BoardColumn{
+			Name:          c.Name,
+			IssueStatuses: issueStatuses,
+			Ordinal:       int32(i),
+			MinWIP:        c.MinWIP,
+			MaxWIP:        c.MaxWIP,
+			WIPPolicy:     c.WIPPolicy,
+		})
+	}
+
+	// Boards added before they had their own workflow use the workflow of their template
+	w, err := s.getBoardWorkflow(b)
+	if err != nil {
+		return err
+	}
+
+	err = s.checkBoardStatuses(w.ID, columns, unmapped)
+	if err != nil {
+		return err
+	}
+
+	if unmapped == nil {
+		unmapped = []string{}
+	}
+
+	set["columns"] = columns
+	set["unmappedStatuses"] = unmapped
+	set["updatedAt"] = time.Now()
+
+	err = s.repo.UpdateBoard(b.ID, bson.M{"$set": set})
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.AddBoardColumnChange(&BoardColumnChange{
+		BoardID: b.ID,
+		From:    b.Columns,
+		To:      columns,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,366 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/njehyde/issue-tracker/libraries/slog"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CustomField defines the storage form of a custom field entity.
+type CustomField struct {
+	ID          primitive.ObjectID   `bson:"_id"`
+	Name        string               `bson:"name"`
+	Description string               `bson:"description"`
+	Type        string               `bson:"type"`
+	Options     []string             `bson:"options"`
+	IsRequired  bool                 `bson:"isRequired"`
+	ProjectIDs  []primitive.ObjectID `bson:"projectIds"`
+	IssueTypes  []string             `bson:"issueTypes"`
+	CreatedAt   time.Time            `bson:"createdAt"`
+	UpdatedAt   time.Time            `bson:"updatedAt"`
+}
+
+// AddCustomField ...
+func (r *Repository) AddCustomField(cf *CustomField) error {
+	collection := r.db.Collection("custom_fields")
+
+	now := time.Now()
+
+	cf.ID = primitive.NewObjectID()
+	cf.CreatedAt = now
+	cf.UpdatedAt = now
+
+	insertResult, err := collection.InsertOne(context.Background(), cf)
+	if err != nil {
+		return err
+	}
+
+	slog.Infof("Added custom field %v: %+v", cf.ID.Hex(), insertResult)
+
+	return nil
+}
+
+// DeleteCustomField ...
+func (r *Repository) DeleteCustomField(ID primitive.ObjectID) error {
+	collection := r.db.Collection("custom_fields")
+
+	filter := bson.M{"_id": ID}
+
+	deleteResult, err := collection.DeleteOne(context.Background(), filter)
+	if err != nil {
+		return err
+	}
+
+	if deleteResult.DeletedCount == 0 {
+		return fmt.Errorf("Custom field could not be deleted")
+	}
+
+	slog.Infof("Deleted custom field %v: %+v", ID.Hex(), deleteResult)
+
+	return nil
+}
+
+// GetCustomField ...
+func (r *Repository) GetCustomField(ID primitive.ObjectID) (*CustomField, error) {
+	var cf CustomField
+
+	collection := r.db.Collection("custom_fields")
+
+	filter := bson.M{"_id": ID}
+
+	err := collection.FindOne(context.Background(), filter).Decode(&cf)
+	if err != nil {
+		return &cf, err
+	}
+
+	return &cf, nil
+}
+
+// GetCustomFields returns the custom fields available to a project and issue type. A nil project id or an
+// empty issue type returns the custom fields of every project or issue type respectively.
+func (r *Repository) GetCustomFields(projectID *primitive.ObjectID, issueType string) (*[]CustomField, error) {
+	var customFields = []CustomField{}
+
+	collection := r.db.Collection("custom_fields")
+
+	var and []bson.M
+	if projectID != nil {
+		and = append(and, bson.M{"$or": []bson.M{
+			{"projectIds": bson.M{"$size": 0}},
+			{"projectIds": projectID},
+		}})
+	}
+	if len(issueType) > 0 {
+		and = append(and, bson.M{"$or": []bson.M{
+			{"issueTypes": bson.M{"$size": 0}},
+			{"issueTypes": issueType},
+		}})
+	}
+
+	filter := bson.M{}
+	if len(and) > 0 {
+		filter["$and"] = and
+	}
+
+	findOptions := options.Find().SetSort(
+		bson.D{
+			primitive.E{Key: "name", Value: 1},
+		},
+	)
+
+	cur, err := collection.Find(context.Background(), filter, findOptions)
+	defer cur.Close(context.Background())
+	if err != nil {
+		return &customFields, err
+	}
+
+	for cur.Next(context.Background()) {
+		var cf CustomField
+
+		err = cur.Decode(&cf)
+		if err != nil {
+			return &customFields, err
+		}
+
+		customFields = append(customFields, cf)
+	}
+
+	return &customFields, nil
+}
+
+// UpdateCustomField ...
+func (r *Repository) UpdateCustomField(ID primitive.ObjectID, update primitive.M) error {
+	collection := r.db.Collection("custom_fields")
+
+	filter := bson.M{"_id": ID}
+
+	updateResult, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return fmt.Errorf("Custom field %v not found", ID.Hex())
+	}
+
+	slog.Infof("Updated custom field %v: %+v", ID.Hex(), updateResult)
+
+	return nil
+}
+
+// UpdateManyCustomFields ...
+func (r *Repository) UpdateManyCustomFields(filter primitive.M, update primitive.M) error {
+	collection := r.db.Collection("custom_fields")
+
+	updateResult, err := collection.UpdateMany(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	slog.Infof("Updated %v custom fields: %+v", updateResult.ModifiedCount, updateResult)
+
+	return nil
+}
+
+// Custom field types.
+const (
+	customFieldText        = "TEXT"
+	customFieldNumber      = "NUMBER"
+	customFieldDate        = "DATE"
+	customFieldSelect      = "SELECT"
+	customFieldMultiSelect = "MULTI_SELECT"
+	customFieldUser        = "USER"
+)
+
+func parseCustomFieldDate(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", v)
+}
+
+func hasCustomFieldOption(cf *CustomField, v string) bool {
+	for _, o := range cf.Options {
+		if o == v {
+			return true
+		}
+	}
+	return false
+}
+
+// getIssueCustomFieldValues validates a set of custom field values, keyed by custom field id, against the custom
+// fields available to a project and issue type, and returns them in their storage form.
+func (s *Storage) getIssueCustomFieldValues(projectID primitive.ObjectID, issueType string, values map[string]interface{}) (map[string]interface{}, error) {
+	return s.getCustomFieldValues(projectID, issueType, values, true)
+}
+
+// getCustomFieldValues validates a set of custom field values as getIssueCustomFieldValues does, only checking that
+// required custom fields have a value where required is set.
+func (s *Storage) getCustomFieldValues(projectID primitive.ObjectID, issueType string, values map[string]interface{}, required bool) (map[string]interface{}, error) {
+	customFields, err := s.repo.GetCustomFields(&projectID, issueType)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]interface{})
+	available := make(map[string]bool)
+
+	for _, cf := range *customFields {
+		id := cf.ID.Hex()
+		available[id] = true
+
+		v, ok := values[id]
+		if !ok || v == nil || v == "" {
+			if cf.IsRequired && required {
+				return nil, fmt.Errorf("Custom field '%v' is required", cf.Name)
+			}
+			continue
+		}
+
+		invalid := fmt.Errorf("Invalid value %v for custom field '%v'", v, cf.Name)
+
+		switch cf.Type {
+		case customFieldText:
+			text, ok := v.(string)
+			if !ok {
+				return nil, invalid
+			}
+			results[id] = text
+		case customFieldNumber:
+			number, ok := v.(float64)
+			if !ok {
+				return nil, invalid
+			}
+			results[id] = number
+		case customFieldDate:
+			text, ok := v.(string)
+			if !ok {
+				return nil, invalid
+			}
+			date, err := parseCustomFieldDate(text)
+			if err != nil {
+				return nil, invalid
+			}
+			results[id] = date
+		case customFieldSelect:
+			option, ok := v.(string)
+			if !ok || !hasCustomFieldOption(&cf, option) {
+				return nil, invalid
+			}
+			results[id] = option
+		case customFieldMultiSelect:
+			items, ok := v.([]interface{})
+			if !ok {
+				return nil, invalid
+			}
+			selected := []string{}
+			for _, item := range items {
+				option, ok := item.(string)
+				if !ok || !hasCustomFieldOption(&cf, option) {
+					return nil, invalid
+				}
+				selected = append(selected, option)
+			}
+			results[id] = selected
+		case customFieldUser:
+			text, ok := v.(string)
+			if !ok {
+				return nil, invalid
+			}
+			userID, err := primitive.ObjectIDFromHex(text)
+			if err != nil {
+				return nil, invalid
+			}
+			if _, err = s.repo.GetUserByID(&userID); err != nil {
+				return nil, fmt.Errorf("User %v not found for custom field '%v'", text, cf.Name)
+			}
+			results[id] = userID
+		default:
+			return nil, fmt.Errorf("Unknown type %v for custom field '%v'", cf.Type, cf.Name)
+		}
+	}
+
+	for id := range values {
+		if !available[id] {
+			return nil, fmt.Errorf("Custom field %v is not available for issue type %v", id, issueType)
+		}
+	}
+
+	return results, nil
+}
+
+// getCustomFieldQueryValue converts a custom field query string value to its storage form.
+func (s *Storage) getCustomFieldQueryValue(id string, v string) (interface{}, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	cf, err := s.repo.GetCustomField(objectID)
+	if err != nil {
+		return nil, fmt.Errorf("Custom field %v not found", id)
+	}
+
+	switch cf.Type {
+	case customFieldNumber:
+		return strconv.ParseFloat(v, 64)
+	case customFieldDate:
+		date, err := parseCustomFieldDate(v)
+		if err != nil {
+			return nil, err
+		}
+		day := date.Truncate(24 * time.Hour)
+		return bson.M{"$gte": day, "$lt": day.Add(24 * time.Hour)}, nil
+	case customFieldUser:
+		return primitive.ObjectIDFromHex(v)
+	case customFieldText:
+		return primitive.Regex{Pattern: "^" + regexp.QuoteMeta(v) + "$", Options: "i"}, nil
+	}
+
+	return v, nil
+}
+
+// transformCustomFieldValues converts stored custom field values to their listing form.
+func transformCustomFieldValues(values map[string]interface{}) map[string]interface{} {
+	if len(values) == 0 {
+		return nil
+	}
+
+	results := make(map[string]interface{})
+
+	for id, v := range values {
+		switch value := v.(type) {
+		case primitive.ObjectID:
+			results[id] = value.Hex()
+		case primitive.DateTime:
+			results[id] = value.Time().UTC()
+		case primitive.A:
+			results[id] = []interface{}(value)
+		default:
+			results[id] = value
+		}
+	}
+
+	return results
+}
+
+// getCustomFieldFormValues converts custom field values from their storage form back to the form they are added in,
+// so that stored values can be validated and added to an issue again.
+func getCustomFieldFormValues(values map[string]interface{}) map[string]interface{} {
+	results := transformCustomFieldValues(values)
+
+	for id, v := range results {
+		if date, ok := v.(time.Time); ok {
+			results[id] = date.Format(time.RFC3339)
+		}
+	}
+
+	return results
+}
@@ -2,10 +2,59 @@ package mongo
 
 import (
 	"fmt"
+	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// DeleteBoardType deletes a board type entity from the database's "board_templates" collection. The default board
+// type, and board types that boards were created from, cannot be deleted.
+func (s *Storage) DeleteBoardType(ID string) error {
+	bt, err := s.repo.GetBoardTemplate(&ID)
+	if err != nil {
+		return fmt.Errorf("Board type %v not found", ID)
+	}
+	if bt.IsDefault {
+		return fmt.Errorf("Cannot delete the default board type")
+	}
+
+	count, err := s.repo.CountBoards(bson.M{"type": ID})
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("Board type %v is used by %v boards", bt.Name, count)
+	}
+
+	return s.repo.DeleteBoardTemplate(ID)
+}
+
+// DeleteCustomField ...
+func (s *Storage) DeleteCustomField(id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.DeleteCustomField(objectID)
+	if err != nil {
+		return err
+	}
+
+	// Remove the custom field's values from any issues
+	key := "customFields." + objectID.Hex()
+	err = s.repo.UpdateManyIssues(
+		bson.M{key: bson.M{"$exists": true}},
+		bson.M{"$unset": bson.M{key: ""}},
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // DeleteIssue ...
 func (s *Storage) DeleteIssue(id string) error {
 	objectID, err := primitive.ObjectIDFromHex(id)
@@ -18,6 +67,11 @@ func (s *Storage) DeleteIssue(id string) error {
 		return err
 	}
 
+	err = s.repo.DeleteIssueWorklogs(objectID)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -41,6 +95,92 @@ func (s *Storage) DeleteIssueComment(issueID *string, commentID *string) error {
 	return nil
 }
 
+// DeleteIssueType deletes an issue type entity from the database's "issue_types" collection, after migrating its
+// issues, and any project issue type schemes and custom fields that reference it, to another issue type.
+func (s *Storage) DeleteIssueType(ID string, migrateTo string) error {
+	issueType, err := s.repo.GetIssueType(ID)
+	if err != nil {
+		return fmt.Errorf("Issue type %v not found", ID)
+	}
+
+	if _, err = s.repo.GetIssueType(migrateTo); err != nil {
+		return fmt.Errorf("Issue type %v not found", migrateTo)
+	}
+
+	// Migrate the issues
+	err = s.repo.UpdateManyIssues(
+		bson.M{"type": ID},
+		bson.M{"$set": bson.M{"type": migrateTo, "updatedAt": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Replace the issue type in project schemes and custom fields, so that migrated issues remain valid
+	filter := bson.M{"issueTypes": ID}
+	add := bson.M{"$addToSet": bson.M{"issueTypes": migrateTo}}
+	pull := bson.M{"$pull": bson.M{"issueTypes": ID}}
+
+	if err = s.repo.UpdateManyProjects(filter, add); err != nil {
+		return err
+	}
+	if err = s.repo.UpdateManyProjects(filter, pull); err != nil {
+		return err
+	}
+	if err = s.repo.UpdateManyCustomFields(filter, add); err != nil {
+		return err
+	}
+	if err = s.repo.UpdateManyCustomFields(filter, pull); err != nil {
+		return err
+	}
+
+	// Hand the default flag over to the issue type being migrated to
+	if issueType.IsDefault {
+		err = s.repo.UpdateIssueType(migrateTo, bson.M{"$set": bson.M{"isDefault": true}})
+		if err != nil {
+			return err
+		}
+	}
+
+	err = s.repo.DeleteIssueType(ID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteLabel deletes a label entity from the database's "labels" collection, and removes it from the issues using it.
+func (s *Storage) DeleteLabel(id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	label, err := s.repo.GetLabel(objectID)
+	if err != nil {
+		return fmt.Errorf("Label %v not found", id)
+	}
+
+	err = s.repo.UpdateManyIssues(
+		bson.M{"labels": label.Label},
+		bson.M{
+			"$pull": bson.M{"labels": label.Label},
+			"$set":  bson.M{"updatedAt": time.Now()},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.DeleteLabel(objectID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // DeleteProject ...
 func (s *Storage) DeleteProject(ID string) error {
 	objectID, err := primitive.ObjectIDFromHex(ID)
@@ -56,6 +196,125 @@ func (s *Storage) DeleteProject(ID string) error {
 	return nil
 }
 
+// DeleteProjectComponent deletes a component child entity of a project in the database's "projects" collection,
+// and removes it from the project's issues.
+func (s *Storage) DeleteProjectComponent(projectID *string, componentID *string) error {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return err
+	}
+
+	componentIDAsObjectID, err := primitive.ObjectIDFromHex(*componentID)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.DeleteProjectComponent(projectIDAsObjectID, componentIDAsObjectID)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.UpdateManyIssues(
+		bson.M{"projectId": projectIDAsObjectID, "components": componentIDAsObjectID},
+		bson.M{"$pull": bson.M{"components": componentIDAsObjectID}},
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteProjectVersion deletes a version child entity of a project in the database's "projects" collection, and
+// removes it from the project's issues.
+func (s *Storage) DeleteProjectVersion(projectID *string, versionID *string) error {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return err
+	}
+
+	versionIDAsObjectID, err := primitive.ObjectIDFromHex(*versionID)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.DeleteProjectVersion(projectIDAsObjectID, versionIDAsObjectID)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.UpdateManyIssues(
+		bson.M{"projectId": projectIDAsObjectID, "fixVersions": versionIDAsObjectID},
+		bson.M{"$pull": bson.M{"fixVersions": versionIDAsObjectID}},
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteProjectBoard deletes a board entity of a project from the database's "boards" collection, and removes it from
+// the project's boards. Issues are never deleted: the issues of the board's open sprints are sent to the backlog, and
+// those of its closed sprints are left as they are. A project's default board cannot be deleted.
+func (s *Storage) DeleteProjectBoard(projectID *string, boardID *string) error {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return err
+	}
+
+	boardIDAsObjectID, err := primitive.ObjectIDFromHex(*boardID)
+	if err != nil {
+		return err
+	}
+
+	project, err := s.repo.GetProject(projectIDAsObjectID)
+	if err != nil {
+		return err
+	}
+	if project.DefaultBoardID == boardIDAsObjectID {
+		return fmt.Errorf("Board %v is the default board of project %v", *boardID, *projectID)
+	}
+
+	board, err := s.getProjectBoard(projectIDAsObjectID, boardIDAsObjectID)
+	if err != nil {
+		return err
+	}
+
+	// Send the issues of any open sprints to the bottom of the backlog
+	for _, sprint := range board.Sprints {
+		if sprint.state() == sprintClosed {
+			continue
+		}
+		sprintID := sprint.ID
+		err = s.SendSprintIssuesToBacklog(&projectIDAsObjectID, &sprintID, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"updatedAt": time.Now(),
+		},
+		"$pull": bson.M{
+			"boards": boardIDAsObjectID,
+		},
+	}
+
+	err = s.repo.UpdateProject(projectIDAsObjectID, update)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.DeleteBoard(boardIDAsObjectID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // DeleteProjectBoardSprint ...
 func (s *Storage) DeleteProjectBoardSprint(projectID *string, boardID *string, sprintID *string) error {
 	var err error
@@ -105,13 +364,7 @@ func (s *Storage) DeleteProjectBoardSprint(projectID *string, boardID *string, s
 	}
 
 	// Send any related sprint issues to the bottom of the backlog
-	err = s.SendSprintIssuesToBacklog(&projectIDAsObjectID, &sprintIDAsObjectID)
-	if err != nil {
-		return err
-	}
-
-	// Clean all the backlog issue ordinals
-	err = s.CleanBacklogIssueOrdinals(&projectIDAsObjectID)
+	err = s.SendSprintIssuesToBacklog(&projectIDAsObjectID, &sprintIDAsObjectID, nil)
 	if err != nil {
 		return err
 	}
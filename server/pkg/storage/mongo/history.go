@@ -0,0 +1,186 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/njehyde/issue-tracker/libraries/slog"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// IssueChange defines the storage form of an issue change entity, a change to one of the tracked fields of an issue.
+type IssueChange struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	IssueID   primitive.ObjectID `bson:"issueId"`
+	ProjectID primitive.ObjectID `bson:"projectId"`
+	Field     string             `bson:"field"`
+	From      interface{}        `bson:"from"`
+	To        interface{}        `bson:"to"`
+	CreatedAt time.Time          `bson:"createdAt"`
+}
+
+// trackedIssueFields are the issue fields whose changes are recorded, so that reports can replay the state of an
+// issue at any point in time.
+var trackedIssueFields = []string{"status", "sprintId", "points", "originalEstimate", "remainingEstimate"}
+
+// AddIssueChanges ...
+func (r *Repository) AddIssueChanges(changes []IssueChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	collection := r.db.Collection("issue_changes")
+
+	documents := []interface{}{}
+	for i := range changes {
+		changes[i].ID = primitive.NewObjectID()
+		documents = append(documents, changes[i])
+	}
+
+	insertResult, err := collection.InsertMany(context.Background(), documents)
+	if err != nil {
+		return err
+	}
+
+	slog.Infof("Added %v issue changes", len(insertResult.InsertedIDs))
+
+	return nil
+}
+
+// GetIssueChanges ...
+func (r *Repository) GetIssueChanges(filter primitive.M) (*[]IssueChange, error) {
+	var changes = []IssueChange{}
+
+	collection := r.db.Collection("issue_changes")
+
+	findOptions := options.Find().SetSort(
+		bson.D{
+			primitive.E{Key: "createdAt", Value: 1},
+			primitive.E{Key: "_id", Value: 1},
+		},
+	)
+
+	cur, err := collection.Find(context.Background(), filter, findOptions)
+	defer cur.Close(context.Background())
+	if err != nil {
+		return &changes, err
+	}
+
+	for cur.Next(context.Background()) {
+		var c IssueChange
+
+		err = cur.Decode(&c)
+		if err != nil {
+			return &changes, err
+		}
+
+		changes = append(changes, c)
+	}
+
+	return &changes, nil
+}
+
+// UpdateManyIssueChanges ...
+func (r *Repository) UpdateManyIssueChanges(filter primitive.M, update primitive.M) error {
+	collection := r.db.Collection("issue_changes")
+
+	updateResult, err := collection.UpdateMany(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	slog.Infof("Updated %v issue changes", updateResult.ModifiedCount)
+
+	return nil
+}
+
+// getIssueFieldValue returns the value of a tracked field of an issue.
+func getIssueFieldValue(i *Issue, field string) interface{} {
+	switch field {
+	case "status":
+		return i.Status
+	case "sprintId":
+		return getIssueChangeValue(field, i.SprintID)
+	case "points":
+		return int64(i.Points)
+	case "originalEstimate":
+		return i.OriginalEstimate
+	case "remainingEstimate":
+		return i.RemainingEstimate
+	}
+	return nil
+}
+
+// getIssueChangeValue returns a tracked field value in the form it is recorded in, so that values set by different
+// update documents compare equal.
+func getIssueChangeValue(field string, v interface{}) interface{} {
+	switch value := v.(type) {
+	case primitive.ObjectID:
+		if value.IsZero() {
+			return nil
+		}
+		return value
+	case *primitive.ObjectID:
+		if value == nil || value.IsZero() {
+			return nil
+		}
+		return *value
+	case int32:
+		return int64(value)
+	case int:
+		return int64(value)
+	}
+	return v
+}
+
+// getIssueChanges returns the changes that an update document makes to the tracked fields of an issue.
+func getIssueChanges(i *Issue, update primitive.M, now time.Time) []IssueChange {
+	changes := []IssueChange{}
+
+	set, _ := update["$set"].(primitive.M)
+	unset, _ := update["$unset"].(primitive.M)
+
+	for _, field := range trackedIssueFields {
+		var to interface{}
+		if v, ok := set[field]; ok {
+			to = getIssueChangeValue(field, v)
+		} else if _, ok := unset[field]; !ok {
+			continue
+		}
+
+		from := getIssueFieldValue(i, field)
+		if from == to {
+			continue
+		}
+
+		changes = append(changes, IssueChange{
+			IssueID:   i.ID,
+			ProjectID: i.ProjectID,
+			Field:     field,
+			From:      from,
+			To:        to,
+			CreatedAt: now,
+		})
+	}
+
+	return changes
+}
+
+// recordIssueChanges saves the changes that a set of update documents, keyed by issue id, make to the tracked fields
+// of the given issues. It is called once the updates have been written.
+func (s *Storage) recordIssueChanges(issues []Issue, updates map[primitive.ObjectID]interface{}) error {
+	now := time.Now()
+
+	changes := []IssueChange{}
+	for i := range issues {
+		update, ok := updates[issues[i].ID].(primitive.M)
+		if !ok {
+			continue
+		}
+		changes = append(changes, getIssueChanges(&issues[i], update, now)...)
+	}
+
+	return s.repo.AddIssueChanges(changes)
+}
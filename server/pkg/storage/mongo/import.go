@@ -0,0 +1,333 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/njehyde/issue-tracker/libraries/slog"
+	"github.com/njehyde/issue-tracker/pkg/adding"
+	"github.com/njehyde/issue-tracker/pkg/listing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// IssueImport defines the storage form of an issue import job entity, which adds the issues of the rows of a CSV file
+// to a project.
+type IssueImport struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	ProjectID primitive.ObjectID `bson:"projectId"`
+	Status    string             `bson:"status"`
+	Total     int                `bson:"total"`
+	Processed int                `bson:"processed"`
+	Added     int                `bson:"added"`
+	Errors    []IssueImportError `bson:"errors,omitempty"`
+	CreatedBy primitive.ObjectID `bson:"createdBy"`
+	CreatedAt time.Time          `bson:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt"`
+}
+
+// IssueImportError defines the storage form of a row of an issue import job that could not be added.
+type IssueImportError struct {
+	Row     int    `bson:"row"`
+	Message string `bson:"message"`
+}
+
+// AddIssueImport ...
+func (r *Repository) AddIssueImport(ii *IssueImport) error {
+	collection := r.db.Collection("issue_imports")
+
+	now := time.Now()
+
+	ii.ID = primitive.NewObjectID()
+	ii.CreatedAt = now
+	ii.UpdatedAt = now
+
+	insertResult, err := collection.InsertOne(context.Background(), ii)
+	if err != nil {
+		return err
+	}
+
+	slog.Infof("Added issue import %v: %+v", ii.ID.Hex(), insertResult)
+
+	return nil
+}
+
+// GetIssueImport ...
+func (r *Repository) GetIssueImport(projectID *primitive.ObjectID, importID *primitive.ObjectID) (*IssueImport, error) {
+	var ii IssueImport
+
+	collection := r.db.Collection("issue_imports")
+
+	filter := bson.M{"_id": importID, "projectId": projectID}
+
+	err := collection.FindOne(context.Background(), filter).Decode(&ii)
+	if err == mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("Issue import %v not found for project %v", importID.Hex(), projectID.Hex())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &ii, nil
+}
+
+// UpdateIssueImport ...
+func (r *Repository) UpdateIssueImport(importID *primitive.ObjectID, update primitive.M) error {
+	collection := r.db.Collection("issue_imports")
+
+	filter := bson.M{"_id": importID}
+
+	updateResult, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return fmt.Errorf("Issue import %v not found", importID.Hex())
+	}
+
+	slog.Infof("Updated issue import %v: %+v", importID.Hex(), updateResult)
+
+	return nil
+}
+
+// AddIssueImport adds an issue import job entity to the database's "issue_imports" collection, and adds the labels of
+// its rows that are not yet in the database's "labels" collection, so that its issues can be added without them.
+func (s *Storage) AddIssueImport(projectID *string, userID *string, rows []adding.IssueImportRow) (string, error) {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return "", err
+	}
+
+	userIDAsObjectID, err := primitive.ObjectIDFromHex(*userID)
+	if err != nil {
+		return "", err
+	}
+
+	labels := []string{}
+	seen := make(map[string]bool)
+	for _, row := range rows {
+		for _, l := range row.Labels {
+			if !seen[l] {
+				seen[l] = true
+				labels = append(labels, l)
+			}
+		}
+	}
+
+	if err = s.addMissingLabels(labels); err != nil {
+		return "", err
+	}
+
+	newImport := IssueImport{
+		ProjectID: projectIDAsObjectID,
+		Status:    adding.IssueImportRunning,
+		Total:     len(rows),
+		CreatedBy: userIDAsObjectID,
+	}
+
+	err = s.repo.AddIssueImport(&newImport)
+	if err != nil {
+		return "", err
+	}
+
+	return newImport.ID.Hex(), nil
+}
+
+// CheckIssueImportRows checks the rows of an issue import against a project. Issue types must be allowed by the
+// project's issue type scheme, statuses must be in the workflow of the project's default board, and issue types with
+// required custom fields cannot be imported, as a CSV file has no custom field values. Issue types, statuses and
+// priorities given by name are replaced by their ids, and assignees are looked up by email.
+func (s *Storage) CheckIssueImportRows(projectID *string, rows []adding.IssueImportRow) error {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return err
+	}
+
+	p, err := s.repo.GetProject(projectIDAsObjectID)
+	if err != nil {
+		return err
+	}
+
+	issueTypes, err := s.repo.GetIssueTypes()
+	if err != nil {
+		return err
+	}
+
+	types := make(map[string]string)
+	for _, it := range *issueTypes {
+		if err = s.checkIssueType(it.ID, p.IssueTypes); err != nil {
+			continue
+		}
+		types[strings.ToLower(it.ID)] = it.ID
+		types[strings.ToLower(it.Name)] = it.ID
+	}
+
+	w, err := s.getProjectWorkflow(projectIDAsObjectID)
+	if err != nil {
+		return err
+	}
+
+	term := ""
+	issueStatuses, err := s.repo.GetIssueStatuses(&term, 1)
+	if err != nil {
+		return err
+	}
+
+	statuses := make(map[string]string)
+	for _, step := range w.Steps {
+		for _, id := range step.StatusIds {
+			statuses[strings.ToLower(id)] = id
+			for _, is := range issueStatuses {
+				if is.ID == id {
+					statuses[strings.ToLower(is.Name)] = id
+				}
+			}
+		}
+	}
+
+	priorityTypes, err := s.repo.GetPriorityTypes(1)
+	if err != nil {
+		return err
+	}
+
+	priorities := make(map[string]string)
+	for _, pt := range priorityTypes {
+		priorities[strings.ToLower(pt.ID)] = pt.ID
+		priorities[strings.ToLower(pt.Name)] = pt.ID
+	}
+
+	// Issue types and users are checked once, however many rows have them
+	typeErrors := make(map[string]error)
+	assignees := make(map[string]string)
+
+	for n := range rows {
+		row := &rows[n]
+
+		if len(row.Type) > 0 {
+			id, ok := types[strings.ToLower(row.Type)]
+			if !ok {
+				row.Errors = append(row.Errors, fmt.Sprintf("Issue type %v is not allowed in project %v", row.Type, p.Key))
+			} else {
+				row.Type = id
+
+				typeErr, checked := typeErrors[id]
+				if !checked {
+					_, typeErr = s.getIssueCustomFieldValues(projectIDAsObjectID, id, nil)
+					typeErrors[id] = typeErr
+				}
+				if typeErr != nil {
+					row.Errors = append(row.Errors, typeErr.Error())
+				}
+			}
+		}
+
+		if len(row.Status) > 0 {
+			id, ok := statuses[strings.ToLower(row.Status)]
+			if !ok {
+				row.Errors = append(row.Errors, fmt.Sprintf("Status %v is not in the workflow of project %v", row.Status, p.Key))
+			} else {
+				row.Status = id
+			}
+		}
+
+		if len(row.Priority) > 0 {
+			id, ok := priorities[strings.ToLower(row.Priority)]
+			if !ok {
+				row.Errors = append(row.Errors, fmt.Sprintf("Priority %v not found", row.Priority))
+			} else {
+				row.Priority = id
+			}
+		}
+
+		if len(row.AssigneeEmail) > 0 {
+			email := row.AssigneeEmail
+
+			id, ok := assignees[email]
+			if !ok {
+				u, err := s.repo.GetUserByEmail(&email)
+				if err != nil && err != mongo.ErrNoDocuments {
+					return err
+				}
+				if u != nil {
+					id = u.ID.Hex()
+				}
+				assignees[email] = id
+			}
+
+			if len(id) == 0 {
+				row.Errors = append(row.Errors, fmt.Sprintf("User %v not found", row.AssigneeEmail))
+			} else {
+				row.AssigneeID = id
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetProjectIssueImport returns an issue import job entity of a project from the repository.
+func (s *Storage) GetProjectIssueImport(projectID *string, importID *string) (*listing.IssueImport, error) {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	importIDAsObjectID, err := primitive.ObjectIDFromHex(*importID)
+	if err != nil {
+		return nil, err
+	}
+
+	ii, err := s.repo.GetIssueImport(&projectIDAsObjectID, &importIDAsObjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	errors := []listing.IssueImportError{}
+	for _, e := range ii.Errors {
+		errors = append(errors, listing.IssueImportError{Row: e.Row, Message: e.Message})
+	}
+
+	result := listing.IssueImport{
+		ID:        ii.ID.Hex(),
+		ProjectID: ii.ProjectID.Hex(),
+		Status:    ii.Status,
+		Total:     ii.Total,
+		Processed: ii.Processed,
+		Added:     ii.Added,
+		Errors:    errors,
+		CreatedBy: ii.CreatedBy.Hex(),
+		CreatedAt: ii.CreatedAt,
+		UpdatedAt: ii.UpdatedAt,
+	}
+
+	return &result, nil
+}
+
+// UpdateIssueImport updates the progress of an issue import job entity in the database's "issue_imports" collection.
+func (s *Storage) UpdateIssueImport(importID *string, progress *adding.IssueImportProgress) error {
+	importIDAsObjectID, err := primitive.ObjectIDFromHex(*importID)
+	if err != nil {
+		return err
+	}
+
+	errors := []IssueImportError{}
+	for _, e := range progress.Errors {
+		errors = append(errors, IssueImportError{Row: e.Row, Message: e.Message})
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":    progress.Status,
+			"processed": progress.Processed,
+			"added":     progress.Added,
+			"errors":    errors,
+			"updatedAt": time.Now(),
+		},
+	}
+
+	return s.repo.UpdateIssueImport(&importIDAsObjectID, update)
+}
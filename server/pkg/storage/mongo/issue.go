@@ -8,6 +8,7 @@ import (
 	"github.com/njehyde/issue-tracker/libraries/slog"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
@@ -53,22 +54,36 @@ func (r *Repository) GetCategories() (*[]Category, error) {
 
 // Issue defines the storage form of an issue entity.
 type Issue struct {
-	ID          primitive.ObjectID `bson:"_id"`
-	ProjectID   primitive.ObjectID `bson:"projectId"`
-	SprintID    primitive.ObjectID `bson:"sprintId,omitempty"`
-	ProjectRef  string             `bson:"projectRef"`
-	Type        string             `bson:"type"`
-	Summary     string             `bson:"summary"`
-	Description string             `bson:"description"`
-	Status      string             `bson:"status"`
-	Priority    string             `bson:"priority"`
-	Points      int32              `bson:"points,omitempty"`
-	ReporterID  primitive.ObjectID `bson:"reporterId"`
-	AssigneeID  primitive.ObjectID `bson:"assigneeId"`
-	Labels      []string           `bson:"labels"`
-	Ordinal     int32              `bson:"ordinal"`
-	CreatedAt   time.Time          `bson:"createdAt"`
-	UpdatedAt   time.Time          `bson:"updatedAt"`
+	ID                primitive.ObjectID     `bson:"_id"`
+	ProjectID         primitive.ObjectID     `bson:"projectId"`
+	SprintID          primitive.ObjectID     `bson:"sprintId,omitempty"`
+	ProjectRef        string                 `bson:"projectRef"`
+	Type              string                 `bson:"type"`
+	Summary           string                 `bson:"summary"`
+	Description       string                 `bson:"description"`
+	Status            string                 `bson:"status"`
+	Priority          string                 `bson:"priority"`
+	Points            int32                  `bson:"points,omitempty"`
+	OriginalEstimate  int64                  `bson:"originalEstimate,omitempty"`
+	RemainingEstimate int64                  `bson:"remainingEstimate,omitempty"`
+	TimeSpent         int64                  `bson:"timeSpent,omitempty"`
+	DueDate           *time.Time             `bson:"dueDate,omitempty"`
+	ReporterID        primitive.ObjectID     `bson:"reporterId"`
+	AssigneeID        primitive.ObjectID     `bson:"assigneeId"`
+	EpicID            primitive.ObjectID     `bson:"epicId,omitempty"`
+	Labels            []string               `bson:"labels"`
+	Components        []primitive.ObjectID   `bson:"components,omitempty"`
+	FixVersions       []primitive.ObjectID   `bson:"fixVersions,omitempty"`
+	Rank              string                 `bson:"rank"`
+	CreatedAt         time.Time              `bson:"createdAt"`
+	UpdatedAt         time.Time              `bson:"updatedAt"`
+	CustomFields      map[string]interface{} `bson:"customFields,omitempty"`
+	SLABreaches       []primitive.ObjectID   `bson:"slaBreaches,omitempty"`
+	IsOverdue         bool                   `bson:"isOverdue,omitempty"`
+	ScheduleID        primitive.ObjectID     `bson:"scheduleId,omitempty"`
+	ScheduledAt       *time.Time             `bson:"scheduledAt,omitempty"`
+	PreviousRefs      []string               `bson:"previousRefs,omitempty"`
+	ClonedFromID      primitive.ObjectID     `bson:"clonedFromId,omitempty"`
 }
 
 // AddIssue ...
@@ -123,27 +138,81 @@ func (r *Repository) GetIssue(ID primitive.ObjectID) (*Issue, error) {
 	return &i, nil
 }
 
+// GetIssueByRef ...
+func (r *Repository) GetIssueByRef(ref string) (*Issue, error) {
+	var i Issue
+
+	collection := r.db.Collection("issues")
+
+	// Issues moved between projects keep answering to the references they had before
+	filter := bson.M{"$or": []bson.M{{"projectRef": ref}, {"previousRefs": ref}}}
+
+	err := collection.FindOne(context.Background(), filter).Decode(&i)
+	if err == mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("Issue %v not found", ref)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &i, nil
+}
+
+// GetIssuesByIds ...
+func (r *Repository) GetIssuesByIds(ids *[]primitive.ObjectID) (*[]Issue, error) {
+	var issues []Issue
+
+	collection := r.db.Collection("issues")
+
+	filter := bson.M{
+		"_id": bson.M{"$in": *ids},
+	}
+
+	cur, err := collection.Find(context.Background(), filter)
+	defer cur.Close(context.Background())
+	if err != nil {
+		return &issues, err
+	}
+
+	for cur.Next(context.Background()) {
+		var i Issue
+
+		err = cur.Decode(&i)
+		if err != nil {
+			return &issues, err
+		}
+
+		issues = append(issues, i)
+	}
+
+	return &issues, nil
+}
+
 // GetIssues ...
-func (r *Repository) GetIssues(cursor *primitive.ObjectID, limit int64) (*[]Issue, int64, error) {
+func (r *Repository) GetIssues(cursor *primitive.ObjectID, limit int64, query bson.M, sort bson.D) (*[]Issue, int64, error) {
 	var issues []Issue
 	var count int64
 
 	collection := r.db.Collection("issues")
 
 	filter := bson.M{}
+	for k, v := range query {
+		filter[k] = v
+	}
 	if cursor != nil {
-		filter = bson.M{
-			"_id": bson.M{
-				"$gt": cursor,
-			},
+		filter["_id"] = bson.M{
+			"$gt": cursor,
 		}
 	}
 
-	findOptions := options.Find().SetLimit(limit).SetSort(
-		bson.D{
-			primitive.E{Key: "ordinal", Value: 1},
-		},
-	)
+	if sort == nil {
+		sort = bson.D{
+			primitive.E{Key: "rank", Value: 1},
+			primitive.E{Key: "_id", Value: 1},
+		}
+	}
+
+	findOptions := options.Find().SetLimit(limit).SetSort(sort)
 
 	cur, err := collection.Find(context.Background(), filter, findOptions)
 	defer cur.Close(context.Background())
@@ -166,13 +235,16 @@ func (r *Repository) GetIssues(cursor *primitive.ObjectID, limit int64) (*[]Issu
 }
 
 // GetProjectIssues ...
-func (r *Repository) GetProjectIssues(projectID *primitive.ObjectID, cursor *primitive.ObjectID, limit int64) (*[]Issue, int64, error) {
+func (r *Repository) GetProjectIssues(projectID *primitive.ObjectID, cursor *primitive.ObjectID, limit int64, query bson.M, sort bson.D) (*[]Issue, int64, error) {
 	var issues []Issue
 	var count int64
 
 	collection := r.db.Collection("issues")
 
 	filter := make(map[string]interface{})
+	for k, v := range query {
+		filter[k] = v
+	}
 
 	if cursor != nil {
 		filter["_id"] = bson.M{"$gt": cursor}
@@ -181,11 +253,14 @@ func (r *Repository) GetProjectIssues(projectID *primitive.ObjectID, cursor *pri
 		filter["projectId"] = projectID
 	}
 
-	findOptions := options.Find().SetLimit(limit).SetSort(
-		bson.D{
-			primitive.E{Key: "ordinal", Value: 1},
-		},
-	)
+	if sort == nil {
+		sort = bson.D{
+			primitive.E{Key: "rank", Value: 1},
+			primitive.E{Key: "_id", Value: 1},
+		}
+	}
+
+	findOptions := options.Find().SetLimit(limit).SetSort(sort)
 
 	cur, err := collection.Find(context.Background(), filter, findOptions)
 	defer cur.Close(context.Background())
@@ -212,6 +287,41 @@ func (r *Repository) GetProjectIssues(projectID *primitive.ObjectID, cursor *pri
 	return &issues, count, nil
 }
 
+// StreamIssues ...
+func (r *Repository) StreamIssues(query bson.M, sort bson.D, fn func(*Issue) error) error {
+	collection := r.db.Collection("issues")
+
+	if sort == nil {
+		sort = bson.D{
+			primitive.E{Key: "rank", Value: 1},
+			primitive.E{Key: "_id", Value: 1},
+		}
+	}
+
+	findOptions := options.Find().SetSort(sort)
+
+	cur, err := collection.Find(context.Background(), query, findOptions)
+	if err != nil {
+		return err
+	}
+	defer cur.Close(context.Background())
+
+	for cur.Next(context.Background()) {
+		var i Issue
+
+		err = cur.Decode(&i)
+		if err != nil {
+			return err
+		}
+
+		if err = fn(&i); err != nil {
+			return err
+		}
+	}
+
+	return cur.Err()
+}
+
 // CountProjectBacklogIssues ...
 func (r *Repository) CountProjectBacklogIssues(projectID *primitive.ObjectID) (count int64, err error) {
 	collection := r.db.Collection("issues")
@@ -223,6 +333,19 @@ func (r *Repository) CountProjectBacklogIssues(projectID *primitive.ObjectID) (c
 	return collection.CountDocuments(context.Background(), filter)
 }
 
+// CountProjectIssues ...
+func (r *Repository) CountProjectIssues(projectID *primitive.ObjectID, query bson.M) (count int64, err error) {
+	collection := r.db.Collection("issues")
+
+	filter := make(map[string]interface{})
+	for k, v := range query {
+		filter[k] = v
+	}
+	filter["projectId"] = projectID
+
+	return collection.CountDocuments(context.Background(), filter)
+}
+
 // CountProjectSprintIssues ...
 func (r *Repository) CountProjectSprintIssues(projectID *primitive.ObjectID, sprintID *primitive.ObjectID) (count int64, err error) {
 	collection := r.db.Collection("issues")
@@ -252,7 +375,8 @@ func (r *Repository) GetProjectSprintIssues(projectID *primitive.ObjectID, sprin
 
 	findOptions := options.Find().SetSort(
 		bson.D{
-			primitive.E{Key: "ordinal", Value: 1},
+			primitive.E{Key: "rank", Value: 1},
+			primitive.E{Key: "_id", Value: 1},
 		},
 	)
 
@@ -304,7 +428,8 @@ func (r *Repository) GetProjectBacklogIssues(projectID *primitive.ObjectID, curs
 
 	findOptions := options.Find().SetSort(
 		bson.D{
-			primitive.E{Key: "ordinal", Value: 1},
+			primitive.E{Key: "rank", Value: 1},
+			primitive.E{Key: "_id", Value: 1},
 		},
 	)
 
@@ -349,6 +474,34 @@ func (r *Repository) UpdateIssues(updates map[primitive.ObjectID]interface{}) er
 	return nil
 }
 
+// UpdateManyIssues ...
+func (r *Repository) UpdateManyIssues(filter primitive.M, update primitive.M) error {
+	collection := r.db.Collection("issues")
+
+	updateResult, err := collection.UpdateMany(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	slog.Infof("Updated %v issues: %+v", updateResult.ModifiedCount, updateResult)
+
+	return nil
+}
+
+// UpdateManyIssuesWithPipeline ...
+func (r *Repository) UpdateManyIssuesWithPipeline(filter primitive.M, pipeline mongo.Pipeline) error {
+	collection := r.db.Collection("issues")
+
+	updateResult, err := collection.UpdateMany(context.Background(), filter, pipeline)
+	if err != nil {
+		return err
+	}
+
+	slog.Infof("Updated %v issues: %+v", updateResult.ModifiedCount, updateResult)
+
+	return nil
+}
+
 // UpdateIssue ...
 func (r *Repository) UpdateIssue(ID primitive.ObjectID, update primitive.M) error {
 	collection := r.db.Collection("issues")
@@ -365,6 +518,46 @@ func (r *Repository) UpdateIssue(ID primitive.ObjectID, update primitive.M) erro
 	return nil
 }
 
+// UpdateIssueIfRank ...
+func (r *Repository) UpdateIssueIfRank(ID primitive.ObjectID, rank string, update primitive.M) (bool, error) {
+	collection := r.db.Collection("issues")
+
+	filter := bson.M{"_id": ID, "rank": rank}
+	if len(rank) == 0 {
+		// Issues saved before rank keys were introduced have no rank at all
+		filter["rank"] = bson.M{"$in": bson.A{"", nil}}
+	}
+
+	updateResult, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	slog.Infof("Updated issue %v: %+v", ID.Hex(), updateResult)
+
+	return updateResult.MatchedCount > 0, nil
+}
+
+// UpdateIssueIfRemainingEstimate ...
+func (r *Repository) UpdateIssueIfRemainingEstimate(ID primitive.ObjectID, remainingEstimate int64, update primitive.M) (bool, error) {
+	collection := r.db.Collection("issues")
+
+	filter := bson.M{"_id": ID, "remainingEstimate": remainingEstimate}
+	if remainingEstimate == 0 {
+		// Issues without an estimate have no remaining estimate at all
+		filter["remainingEstimate"] = bson.M{"$in": bson.A{0, nil}}
+	}
+
+	updateResult, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	slog.Infof("Updated issue %v: %+v", ID.Hex(), updateResult)
+
+	return updateResult.MatchedCount > 0, nil
+}
+
 // IssueComment defines the storage form of an issue comment entity.
 type IssueComment struct {
 	ID        primitive.ObjectID `bson:"_id"`
@@ -395,6 +588,30 @@ func (r *Repository) AddIssueComment(ic *IssueComment) error {
 	return nil
 }
 
+// AddIssueComments ...
+func (r *Repository) AddIssueComments(comments []IssueComment) error {
+	if len(comments) == 0 {
+		return nil
+	}
+
+	collection := r.db.Collection("issue_comments")
+
+	documents := []interface{}{}
+	for i := range comments {
+		comments[i].ID = primitive.NewObjectID()
+		documents = append(documents, comments[i])
+	}
+
+	insertResult, err := collection.InsertMany(context.Background(), documents)
+	if err != nil {
+		return err
+	}
+
+	slog.Infof("Added %v issue comments", len(insertResult.InsertedIDs))
+
+	return nil
+}
+
 // DeleteIssueComment ...
 func (r *Repository) DeleteIssueComment(issueID *primitive.ObjectID, commentID *primitive.ObjectID) error {
 	collection := r.db.Collection("issue_comments")
@@ -492,9 +709,61 @@ type IssueType struct {
 	ID          string `bson:"_id"`
 	Name        string `bson:"name"`
 	Description string `bson:"description"`
+	Icon        string `bson:"icon"`
+	IsSubTask   bool   `bson:"isSubTask"`
 	IsDefault   bool   `bson:"isDefault"`
 }
 
+// AddIssueType ...
+func (r *Repository) AddIssueType(it *IssueType) error {
+	collection := r.db.Collection("issue_types")
+
+	insertResult, err := collection.InsertOne(context.Background(), it)
+	if err != nil {
+		return err
+	}
+
+	slog.Infof("Added issue type %v: %+v", it.ID, insertResult)
+
+	return nil
+}
+
+// DeleteIssueType ...
+func (r *Repository) DeleteIssueType(ID string) error {
+	collection := r.db.Collection("issue_types")
+
+	filter := bson.M{"_id": ID}
+
+	deleteResult, err := collection.DeleteOne(context.Background(), filter)
+	if err != nil {
+		return err
+	}
+
+	if deleteResult.DeletedCount == 0 {
+		return fmt.Errorf("Issue type %v could not be deleted", ID)
+	}
+
+	slog.Infof("Deleted issue type %v: %+v", ID, deleteResult)
+
+	return nil
+}
+
+// GetIssueType ...
+func (r *Repository) GetIssueType(ID string) (*IssueType, error) {
+	var it IssueType
+
+	collection := r.db.Collection("issue_types")
+
+	filter := bson.M{"_id": ID}
+
+	err := collection.FindOne(context.Background(), filter).Decode(&it)
+	if err != nil {
+		return &it, err
+	}
+
+	return &it, nil
+}
+
 // GetIssueTypes ...
 func (r *Repository) GetIssueTypes() (*[]IssueType, error) {
 	var issueTypes = []IssueType{}
@@ -527,6 +796,26 @@ func (r *Repository) GetIssueTypes() (*[]IssueType, error) {
 	return &issueTypes, nil
 }
 
+// UpdateIssueType ...
+func (r *Repository) UpdateIssueType(ID string, update primitive.M) error {
+	collection := r.db.Collection("issue_types")
+
+	filter := bson.M{"_id": ID}
+
+	updateResult, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return fmt.Errorf("Issue type %v not found", ID)
+	}
+
+	slog.Infof("Updated issue type %v: %+v", ID, updateResult)
+
+	return nil
+}
+
 // IssueStatus defines the storage form of an issue status entity.
 type IssueStatus struct {
 	ID          string `bson:"_id"`
@@ -666,6 +955,92 @@ func (r *Repository) AddLabels(l *[]Label) error {
 	return nil
 }
 
+// DeleteLabel ...
+func (r *Repository) DeleteLabel(ID primitive.ObjectID) error {
+	collection := r.db.Collection("labels")
+
+	filter := bson.M{"_id": ID}
+
+	deleteResult, err := collection.DeleteOne(context.Background(), filter)
+	if err != nil {
+		return err
+	}
+
+	if deleteResult.DeletedCount == 0 {
+		return fmt.Errorf("Label %v could not be deleted", ID.Hex())
+	}
+
+	slog.Infof("Deleted label %v: %+v", ID.Hex(), deleteResult)
+
+	return nil
+}
+
+// GetLabel ...
+func (r *Repository) GetLabel(ID primitive.ObjectID) (*Label, error) {
+	var l Label
+
+	collection := r.db.Collection("labels")
+
+	filter := bson.M{"_id": ID}
+
+	err := collection.FindOne(context.Background(), filter).Decode(&l)
+	if err != nil {
+		return &l, err
+	}
+
+	return &l, nil
+}
+
+// GetLabelByValue ...
+func (r *Repository) GetLabelByValue(value string) (*Label, error) {
+	var l Label
+
+	collection := r.db.Collection("labels")
+
+	filter := bson.M{"label": value}
+
+	err := collection.FindOne(context.Background(), filter).Decode(&l)
+	if err != nil {
+		return &l, err
+	}
+
+	return &l, nil
+}
+
+// GetLabelCounts returns the number of issues using each label, keyed by label.
+func (r *Repository) GetLabelCounts() (map[string]int64, error) {
+	counts := make(map[string]int64)
+
+	collection := r.db.Collection("issues")
+
+	pipeline := []bson.M{
+		{"$unwind": "$labels"},
+		{"$group": bson.M{"_id": "$labels", "count": bson.M{"$sum": 1}}},
+	}
+
+	cur, err := collection.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return counts, err
+	}
+	defer cur.Close(context.Background())
+
+	for cur.Next(context.Background()) {
+		var c struct {
+			Label string `bson:"_id"`
+			Count int64  `bson:"count"`
+		}
+
+		err = cur.Decode(&c)
+		if err != nil {
+			return counts, err
+		}
+
+		counts[c.Label] = c.Count
+	}
+
+	return counts, nil
+}
+
 // GetLabels ...
 func (r *Repository) GetLabels(term *string) (*[]Label, error) {
 	var labels []Label
@@ -708,6 +1083,26 @@ func (r *Repository) GetLabels(term *string) (*[]Label, error) {
 	return &labels, nil
 }
 
+// UpdateLabel ...
+func (r *Repository) UpdateLabel(ID primitive.ObjectID, update primitive.M) error {
+	collection := r.db.Collection("labels")
+
+	filter := bson.M{"_id": ID}
+
+	updateResult, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return fmt.Errorf("Label %v not found", ID.Hex())
+	}
+
+	slog.Infof("Updated label %v: %+v", ID.Hex(), updateResult)
+
+	return nil
+}
+
 // PriorityType defines the storage form of a priority type entity.
 type PriorityType struct {
 	ID          string `bson:"_id"`
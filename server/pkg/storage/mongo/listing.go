@@ -2,11 +2,27 @@ package mongo
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/njehyde/issue-tracker/pkg/listing"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// GetBoardType returns a board type entity by id from the respository.
+func (s *Storage) GetBoardType(id string) (*listing.BoardType, error) {
+	bt, err := s.repo.GetBoardTemplate(&id)
+	if err != nil {
+		return nil, fmt.Errorf("Board type %v not found", id)
+	}
+
+	result := transformBoardType(bt)
+
+	return &result, nil
+}
+
 // GetBoardTypes returns all board type entities from the respository.
 func (s *Storage) GetBoardTypes() (results []listing.BoardType, err error) {
 	boardTemplates, err := s.repo.GetBoardTemplates()
@@ -16,19 +32,39 @@ func (s *Storage) GetBoardTypes() (results []listing.BoardType, err error) {
 
 	results = make([]listing.BoardType, 0)
 
-	for _, bt := range *boardTemplates {
-		boardType := listing.BoardType{
-			ID:        bt.ID,
-			Name:      bt.Name,
-			IsDefault: bt.IsDefault,
-		}
-
-		results = append(results, boardType)
+	for i := range *boardTemplates {
+		results = append(results, transformBoardType(&(*boardTemplates)[i]))
 	}
 
 	return results, nil
 }
 
+func transformBoardType(bt *BoardTemplate) listing.BoardType {
+	columns := []listing.BoardColumn{}
+	for _, c := range bt.Columns {
+		columns = append(columns, listing.BoardColumn{
+			Name:          c.Name,
+			IssueStatuses: c.IssueStatuses,
+			Ordinal:       c.Ordinal,
+			MinWIP:        c.MinWIP,
+			MaxWIP:        c.MaxWIP,
+			WIPPolicy:     c.WIPPolicy,
+		})
+	}
+
+	return listing.BoardType{
+		ID:               bt.ID,
+		Name:             bt.Name,
+		IsDefault:        bt.IsDefault,
+		IsSprintable:     bt.IsSprintable,
+		IsBacklogVisible: bt.IsBacklogVisible,
+		IsBoardVisible:   bt.IsBoardVisible,
+		WorkflowID:       bt.WorkflowID,
+		Columns:          columns,
+		UnmappedStatuses: append([]string{}, bt.UnmappedStatuses...),
+	}
+}
+
 // GetCategories returns all category entities from the respository.
 func (s *Storage) GetCategories() (results []listing.Category, err error) {
 	categories, err := s.repo.GetCategories()
@@ -51,6 +87,54 @@ func (s *Storage) GetCategories() (results []listing.Category, err error) {
 	return results, nil
 }
 
+// GetCustomFields returns all custom field entities, or those available to a project and issue type, from the repository.
+func (s *Storage) GetCustomFields(projectID *string, issueType *string) (results []listing.CustomField, err error) {
+	var projectIDAsObjectID *primitive.ObjectID
+	if projectID != nil && len(*projectID) > 0 {
+		objectID, err := primitive.ObjectIDFromHex(*projectID)
+		if err != nil {
+			return results, err
+		}
+		projectIDAsObjectID = &objectID
+	}
+
+	var t string
+	if issueType != nil {
+		t = *issueType
+	}
+
+	customFields, err := s.repo.GetCustomFields(projectIDAsObjectID, t)
+	if err != nil {
+		return results, err
+	}
+
+	results = make([]listing.CustomField, 0)
+
+	for _, cf := range *customFields {
+		projectIDs := []string{}
+		for _, id := range cf.ProjectIDs {
+			projectIDs = append(projectIDs, id.Hex())
+		}
+
+		customField := listing.CustomField{
+			ID:          cf.ID.Hex(),
+			Name:        cf.Name,
+			Description: cf.Description,
+			Type:        cf.Type,
+			Options:     cf.Options,
+			IsRequired:  cf.IsRequired,
+			ProjectIDs:  projectIDs,
+			IssueTypes:  cf.IssueTypes,
+			CreatedAt:   cf.CreatedAt,
+			UpdatedAt:   cf.UpdatedAt,
+		}
+
+		results = append(results, customField)
+	}
+
+	return results, nil
+}
+
 // GetIssue returns an issue entity by id from the repository.
 func (s *Storage) GetIssue(id string) (result listing.Issue, err error) {
 	objectID, err := primitive.ObjectIDFromHex(id)
@@ -63,26 +147,32 @@ func (s *Storage) GetIssue(id string) (result listing.Issue, err error) {
 		return result, err
 	}
 
-	result = listing.Issue{
-		ID:          i.ID.Hex(),
-		ProjectID:   i.ProjectID.Hex(),
-		SprintID:    getHexFromObjectID(i.SprintID),
-		ProjectRef:  i.ProjectRef,
-		Type:        i.Type,
-		Summary:     i.Summary,
-		Description: i.Description,
-		Status:      i.Status,
-		Priority:    i.Priority,
-		Points:      i.Points,
-		Ordinal:     i.Ordinal,
-		CreatedAt:   i.CreatedAt,
-		UpdatedAt:   i.UpdatedAt,
-		ReporterID:  i.ReporterID.Hex(),
-		AssigneeID:  i.AssigneeID.Hex(),
-		Labels:      i.Labels,
+	result = transformIssue(i)
+
+	results := []listing.Issue{result}
+	if err = s.setIssueSLAs(results, []Issue{*i}); err != nil {
+		return result, err
 	}
 
-	return result, nil
+	return results[0], nil
+}
+
+// GetIssueByRef returns an issue entity by its project reference, or by a reference it had before it was moved to
+// another project, from the repository.
+func (s *Storage) GetIssueByRef(ref string) (result listing.Issue, err error) {
+	i, err := s.repo.GetIssueByRef(ref)
+	if err != nil {
+		return result, err
+	}
+
+	result = transformIssue(i)
+
+	results := []listing.Issue{result}
+	if err = s.setIssueSLAs(results, []Issue{*i}); err != nil {
+		return result, err
+	}
+
+	return results[0], nil
 }
 
 // GetIssueComments returns a paginated slice of issue comment entities from the repository.
@@ -152,8 +242,8 @@ func (s *Storage) GetIssueComments(issueID *string, p *listing.Pagination) (resu
 	return results, count, nil
 }
 
-// GetIssues returns a paginated slice of issue entities from the repository.
-func (s *Storage) GetIssues(p *listing.Pagination) (results []listing.Issue, count int64, err error) {
+// GetIssues returns a paginated, and optionally filtered and sorted, slice of issue entities from the repository.
+func (s *Storage) GetIssues(p *listing.Pagination, q *listing.IssueQuery) (results []listing.Issue, count int64, err error) {
 	var cursor primitive.ObjectID = primitive.ObjectID{}
 
 	limit := int64(p.PageSize)
@@ -165,7 +255,12 @@ func (s *Storage) GetIssues(p *listing.Pagination) (results []listing.Issue, cou
 		}
 	}
 
-	issues, count, err := s.repo.GetIssues(&cursor, limit)
+	filter, sort, err := s.getIssueQuery(q)
+	if err != nil {
+		return results, count, err
+	}
+
+	issues, count, err := s.repo.GetIssues(&cursor, limit, filter, sort)
 	if err != nil {
 		return results, count, nil
 	}
@@ -173,28 +268,15 @@ func (s *Storage) GetIssues(p *listing.Pagination) (results []listing.Issue, cou
 	results = make([]listing.Issue, 0)
 
 	for _, i := range *issues {
-		issue := listing.Issue{
-			ID:          i.ID.Hex(),
-			ProjectID:   getHexFromObjectID(i.ProjectID),
-			SprintID:    getHexFromObjectID(i.SprintID),
-			ProjectRef:  i.ProjectRef,
-			Type:        i.Type,
-			Summary:     i.Summary,
-			Description: i.Description,
-			Status:      i.Status,
-			Priority:    i.Priority,
-			Points:      i.Points,
-			Ordinal:     i.Ordinal,
-			CreatedAt:   i.CreatedAt,
-			UpdatedAt:   i.UpdatedAt,
-			ReporterID:  getHexFromObjectID(i.ReporterID),
-			AssigneeID:  getHexFromObjectID(i.AssigneeID),
-			Labels:      i.Labels,
-		}
+		issue := transformIssue(&i)
 
 		results = append(results, issue)
 	}
 
+	if err = s.setIssueSLAs(results, *issues); err != nil {
+		return results, count, err
+	}
+
 	if len(results) > 0 {
 		lastResult := results[len(results)-1]
 		p.Cursor = lastResult.ID
@@ -270,6 +352,8 @@ func (s *Storage) GetIssueTypes() (results []listing.IssueType, err error) {
 			ID:          it.ID,
 			Name:        it.Name,
 			Description: it.Description,
+			Icon:        it.Icon,
+			IsSubTask:   it.IsSubTask,
 			IsDefault:   it.IsDefault,
 		}
 
@@ -286,12 +370,18 @@ func (s *Storage) GetLabels(term *string) (results []listing.Label, err error) {
 		return results, err
 	}
 
+	counts, err := s.repo.GetLabelCounts()
+	if err != nil {
+		return results, err
+	}
+
 	results = make([]listing.Label, 0)
 
 	for _, l := range *labels {
 		label := listing.Label{
 			ID:    l.ID.Hex(),
 			Label: l.Label,
+			Count: counts[l.Label],
 		}
 
 		results = append(results, label)
@@ -325,6 +415,178 @@ func (s *Storage) GetPriorityTypes() (results []listing.PriorityType, err error)
 	return results, nil
 }
 
+// issueQueryFields maps the fields an issue query can filter and sort on to their storage names.
+var issueQueryFields = map[string]string{
+	"type":       "type",
+	"status":     "status",
+	"priority":   "priority",
+	"label":      "labels",
+	"component":  "components",
+	"fixVersion": "fixVersions",
+	"assigneeId": "assigneeId",
+	"epicId":     "epicId",
+	"reporterId": "reporterId",
+	"sprintId":   "sprintId",
+	"scheduleId": "scheduleId",
+	"points":     "points",
+	"dueDate":    "dueDate",
+	"ordinal":    "rank",
+	"rank":       "rank",
+	"projectRef": "projectRef",
+	"createdAt":  "createdAt",
+	"updatedAt":  "updatedAt",
+}
+
+// issueQueryArrayFields are the storage names of the issue fields that hold lists, which match repeated filters only
+// where they hold every value.
+var issueQueryArrayFields = map[string]bool{
+	"labels":      true,
+	"components":  true,
+	"fixVersions": true,
+}
+
+// getIssueQuery converts an issue query to a storage filter and sort. Custom fields are referenced as "cf.{id}". A
+// field filtered more than once matches any of its values, except for list fields, which must hold all of them.
+func (s *Storage) getIssueQuery(q *listing.IssueQuery) (filter bson.M, sort bson.D, err error) {
+	filter = bson.M{}
+
+	if q == nil {
+		return filter, sort, nil
+	}
+
+	keys := []string{}
+	values := make(map[string][]interface{})
+	add := func(key string, v interface{}) {
+		if _, ok := values[key]; !ok {
+			keys = append(keys, key)
+		}
+		values[key] = append(values[key], v)
+	}
+
+	for _, f := range q.Filters {
+		if strings.HasPrefix(f.Field, "cf.") {
+			id := strings.TrimPrefix(f.Field, "cf.")
+			v, err := s.getCustomFieldQueryValue(id, f.Value)
+			if err != nil {
+				return filter, sort, err
+			}
+			add("customFields."+id, v)
+			continue
+		}
+
+		key, ok := issueQueryFields[f.Field]
+		if !ok {
+			return filter, sort, fmt.Errorf("Cannot filter issues by %v", f.Field)
+		}
+
+		var v interface{} = f.Value
+		switch key {
+		case "assigneeId", "reporterId", "epicId", "sprintId", "scheduleId", "components", "fixVersions":
+			if v, err = primitive.ObjectIDFromHex(f.Value); err != nil {
+				return filter, sort, err
+			}
+		case "points":
+			points, err := strconv.ParseInt(f.Value, 10, 32)
+			if err != nil {
+				return filter, sort, err
+			}
+			v = int32(points)
+		case "dueDate":
+			day, err := time.Parse("2006-01-02", f.Value)
+			if err != nil {
+				return filter, sort, err
+			}
+			v = bson.M{"$gte": day, "$lt": day.AddDate(0, 0, 1)}
+		}
+		add(key, v)
+	}
+
+	for _, key := range keys {
+		vs := values[key]
+		if len(vs) == 1 {
+			filter[key] = vs[0]
+			continue
+		}
+
+		// Date ranges cannot be listed in an $in, so each is a separate condition
+		ranges := false
+		for _, v := range vs {
+			if _, ok := v.(bson.M); ok {
+				ranges = true
+			}
+		}
+
+		switch {
+		case ranges:
+			or := bson.A{}
+			for _, v := range vs {
+				or = append(or, bson.M{key: v})
+			}
+			and, _ := filter["$and"].(bson.A)
+			filter["$and"] = append(and, bson.M{"$or": or})
+		case issueQueryArrayFields[key]:
+			filter[key] = bson.M{"$all": vs}
+		default:
+			filter[key] = bson.M{"$in": vs}
+		}
+	}
+
+	if len(q.Sort) > 0 {
+		order := q.Order
+		if order == 0 {
+			order = 1
+		}
+
+		key, ok := issueQueryFields[q.Sort]
+		if strings.HasPrefix(q.Sort, "cf.") {
+			key, ok = "customFields."+strings.TrimPrefix(q.Sort, "cf."), true
+		}
+		if !ok {
+			return filter, sort, fmt.Errorf("Cannot sort issues by %v", q.Sort)
+		}
+
+		sort = bson.D{
+			primitive.E{Key: key, Value: order},
+			primitive.E{Key: "_id", Value: 1},
+		}
+	}
+
+	return filter, sort, nil
+}
+
+func transformIssue(i *Issue) listing.Issue {
+	return listing.Issue{
+		ID:                i.ID.Hex(),
+		ProjectID:         getHexFromObjectID(i.ProjectID),
+		SprintID:          getHexFromObjectID(i.SprintID),
+		ProjectRef:        i.ProjectRef,
+		Type:              i.Type,
+		Summary:           i.Summary,
+		Description:       i.Description,
+		Status:            i.Status,
+		Priority:          i.Priority,
+		Points:            i.Points,
+		OriginalEstimate:  i.OriginalEstimate,
+		RemainingEstimate: i.RemainingEstimate,
+		DueDate:           i.DueDate,
+		TimeSpent:         i.TimeSpent,
+		Rank:              i.Rank,
+		CreatedAt:         i.CreatedAt,
+		UpdatedAt:         i.UpdatedAt,
+		ReporterID:        getHexFromObjectID(i.ReporterID),
+		AssigneeID:        getHexFromObjectID(i.AssigneeID),
+		EpicID:            getHexFromObjectID(i.EpicID),
+		Labels:            i.Labels,
+		Components:        transformObjectIDs(i.Components),
+		FixVersions:       transformObjectIDs(i.FixVersions),
+		CustomFields:      transformCustomFieldValues(i.CustomFields),
+		ScheduleID:        getHexFromObjectID(i.ScheduleID),
+		ScheduledAt:       i.ScheduledAt,
+		PreviousRefs:      i.PreviousRefs,
+		ClonedFromID:      getHexFromObjectID(i.ClonedFromID),
+	}
+}
+
 func transformProject(p *Project, bs *[]Board) (project listing.Project) {
 	boards := []listing.Board{}
 	if len(*bs) > 0 {
@@ -344,6 +606,9 @@ func transformProject(p *Project, bs *[]Board) (project listing.Project) {
 		DefaultAssigneeID: getHexFromObjectID(p.DefaultAssigneeID),
 		DefaultBoardID:    getHexFromObjectID(p.DefaultBoardID),
 		Boards:            boards,
+		IssueTypes:        p.IssueTypes,
+		Components:        transformComponents(p.Components),
+		Versions:          transformVersions(p.Versions),
 		CreatedAt:         p.CreatedAt,
 		UpdatedAt:         p.UpdatedAt,
 	}
@@ -351,6 +616,63 @@ func transformProject(p *Project, bs *[]Board) (project listing.Project) {
 	return project
 }
 
+func transformComponents(cs []Component) []listing.Component {
+	components := []listing.Component{}
+
+	for _, c := range cs {
+		component := listing.Component{
+			ID:           c.ID.Hex(),
+			Name:         c.Name,
+			Description:  c.Description,
+			LeadID:       getHexFromObjectID(c.LeadID),
+			AssigneeType: c.AssigneeType,
+			CreatedAt:    c.CreatedAt,
+			UpdatedAt:    c.UpdatedAt,
+		}
+
+		components = append(components, component)
+	}
+
+	return components
+}
+
+func transformVersion(v *Version) listing.Version {
+	return listing.Version{
+		ID:          v.ID.Hex(),
+		Name:        v.Name,
+		Description: v.Description,
+		Status:      v.Status,
+		StartAt:     v.StartAt,
+		ReleaseAt:   v.ReleaseAt,
+		ReleasedAt:  v.ReleasedAt,
+		CreatedAt:   v.CreatedAt,
+		UpdatedAt:   v.UpdatedAt,
+	}
+}
+
+func transformVersions(vs []Version) []listing.Version {
+	versions := []listing.Version{}
+
+	for i := range vs {
+		versions = append(versions, transformVersion(&vs[i]))
+	}
+
+	return versions
+}
+
+func transformObjectIDs(ids []primitive.ObjectID) []string {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	results := []string{}
+	for _, id := range ids {
+		results = append(results, id.Hex())
+	}
+
+	return results
+}
+
 func transformBoard(b *Board) *listing.Board {
 	columns := []listing.BoardColumn{}
 
@@ -360,25 +682,45 @@ func transformBoard(b *Board) *listing.Board {
 				Name:          c.Name,
 				IssueStatuses: c.IssueStatuses,
 				Ordinal:       c.Ordinal,
+				MinWIP:        c.MinWIP,
+				MaxWIP:        c.MaxWIP,
+				WIPPolicy:     c.WIPPolicy,
 			}
 
 			columns = append(columns, column)
 		}
 	}
 
+	filters := []listing.BoardFilter{}
+	for _, f := range b.Filters {
+		filters = append(filters, listing.BoardFilter{Field: f.Field, Value: f.Value})
+	}
+
+	swimlaneQueries := []listing.SwimlaneQuery{}
+	for _, q := range b.SwimlaneQueries {
+		query := listing.SwimlaneQuery{Name: q.Name, Filters: []listing.BoardFilter{}}
+		for _, f := range q.Filters {
+			query.Filters = append(query.Filters, listing.BoardFilter{Field: f.Field, Value: f.Value})
+		}
+		swimlaneQueries = append(swimlaneQueries, query)
+	}
+
 	sprints := []listing.Sprint{}
 
 	if len(b.Sprints) > 0 {
 		for _, s := range b.Sprints {
 
 			sprint := listing.Sprint{
-				ID:        s.ID.Hex(),
-				Name:      s.Name,
-				Goal:      s.Goal,
-				Ordinal:   s.Ordinal,
-				CreatedAt: &s.CreatedAt,
-				UpdatedAt: &s.UpdatedAt,
-				CreatedBy: getHexFromObjectID(s.CreatedBy),
+				ID:          s.ID.Hex(),
+				Name:        s.Name,
+				Goal:        s.Goal,
+				Ordinal:     s.Ordinal,
+				State:       s.state(),
+				StartedAt:   s.StartedAt,
+				CompletedAt: s.CompletedAt,
+				CreatedAt:   &s.CreatedAt,
+				UpdatedAt:   &s.UpdatedAt,
+				CreatedBy:   getHexFromObjectID(s.CreatedBy),
 			}
 
 			if !s.StartAt.IsZero() {
@@ -399,7 +741,12 @@ func transformBoard(b *Board) *listing.Board {
 		Description:      b.Description,
 		IsBacklogVisible: b.IsBacklogVisible,
 		IsBoardVisible:   b.IsBoardVisible,
+		ParallelSprints:  b.ParallelSprints,
+		Filters:          filters,
 		Columns:          columns,
+		UnmappedStatuses: append([]string{}, b.UnmappedStatuses...),
+		SwimlaneType:     b.swimlaneType(),
+		SwimlaneQueries:  swimlaneQueries,
 		Sprints:          sprints,
 		CreatedAt:        &b.CreatedAt,
 		UpdatedAt:        &b.UpdatedAt,
@@ -487,8 +834,25 @@ func (s *Storage) GetProjectByID(id string) (project listing.Project, err error)
 	// return result, nil
 }
 
-// GetProjectIssues returns a paginated slice of project issue entities from the respository.
-func (s *Storage) GetProjectIssues(projectID *string, p *listing.Pagination) (results []listing.Issue, count int64, err error) {
+// GetProjectComponents returns the component entities of a project from the repository.
+func (s *Storage) GetProjectComponents(projectID *string) (results []listing.Component, err error) {
+	objectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return results, err
+	}
+
+	p, err := s.repo.GetProject(objectID)
+	if err != nil {
+		return results, err
+	}
+
+	results = transformComponents(p.Components)
+
+	return results, nil
+}
+
+// GetProjectIssues returns a paginated, and optionally filtered and sorted, slice of project issue entities from the respository.
+func (s *Storage) GetProjectIssues(projectID *string, p *listing.Pagination, q *listing.IssueQuery) (results []listing.Issue, count int64, err error) {
 	var projectIDAsObjectID primitive.ObjectID = primitive.ObjectID{}
 	var cursor primitive.ObjectID = primitive.ObjectID{}
 
@@ -508,7 +872,12 @@ func (s *Storage) GetProjectIssues(projectID *string, p *listing.Pagination) (re
 		}
 	}
 
-	issues, count, err := s.repo.GetProjectIssues(&projectIDAsObjectID, &cursor, limit)
+	filter, sort, err := s.getIssueQuery(q)
+	if err != nil {
+		return results, count, err
+	}
+
+	issues, count, err := s.repo.GetProjectIssues(&projectIDAsObjectID, &cursor, limit, filter, sort)
 	if err != nil {
 		return results, count, err
 	}
@@ -516,28 +885,15 @@ func (s *Storage) GetProjectIssues(projectID *string, p *listing.Pagination) (re
 	results = make([]listing.Issue, 0)
 
 	for _, i := range *issues {
-		issue := listing.Issue{
-			ID:          i.ID.Hex(),
-			ProjectID:   getHexFromObjectID(i.ProjectID),
-			SprintID:    getHexFromObjectID(i.SprintID),
-			ProjectRef:  i.ProjectRef,
-			Type:        i.Type,
-			Summary:     i.Summary,
-			Description: i.Description,
-			Status:      i.Status,
-			Priority:    i.Priority,
-			Points:      i.Points,
-			Ordinal:     i.Ordinal,
-			CreatedAt:   i.CreatedAt,
-			UpdatedAt:   i.UpdatedAt,
-			ReporterID:  getHexFromObjectID(i.ReporterID),
-			AssigneeID:  getHexFromObjectID(i.AssigneeID),
-			Labels:      i.Labels,
-		}
+		issue := transformIssue(&i)
 
 		results = append(results, issue)
 	}
 
+	if err = s.setIssueSLAs(results, *issues); err != nil {
+		return results, count, err
+	}
+
 	if len(results) > 0 {
 		lastResult := results[len(results)-1]
 		p.Cursor = lastResult.ID
@@ -546,6 +902,28 @@ func (s *Storage) GetProjectIssues(projectID *string, p *listing.Pagination) (re
 	return results, count, nil
 }
 
+// StreamIssues calls a function with each issue matching a query, of a project or of every project where the project
+// id is empty, as it is read from the database's "issues" collection, so that the issues are never all held in memory.
+func (s *Storage) StreamIssues(projectID *string, q *listing.IssueQuery, fn func(*listing.Issue) error) error {
+	filter, sort, err := s.getIssueQuery(q)
+	if err != nil {
+		return err
+	}
+
+	if len(*projectID) > 0 {
+		projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+		if err != nil {
+			return err
+		}
+		filter["projectId"] = projectIDAsObjectID
+	}
+
+	return s.repo.StreamIssues(filter, sort, func(i *Issue) error {
+		issue := transformIssue(i)
+		return fn(&issue)
+	})
+}
+
 // GetProjectBoard returns a project board entity by project and board ids from the repository.
 func (s *Storage) GetProjectBoard(projectID *string, boardID *string) (result *listing.Board, err error) {
 	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
@@ -574,9 +952,49 @@ func (s *Storage) GetProjectBoard(projectID *string, boardID *string) (result *l
 
 	result = transformBoard(b)
 
+	// Report the number of issues in each column against its WIP limits
+	activeSprints := getBoardActiveSprints(b)
+	for i := range b.Columns {
+		count, err := s.countBoardColumnIssues(projectIDAsObjectID, b, &b.Columns[i], activeSprints)
+		if err != nil {
+			return result, err
+		}
+		result.Columns[i].SetIssueCount(count)
+	}
+
 	return result, nil
 }
 
+// GetProjectBoards returns the board entities of a project from the repository.
+func (s *Storage) GetProjectBoards(projectID *string) (results []listing.Board, err error) {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return results, err
+	}
+
+	p, err := s.repo.GetProject(projectIDAsObjectID)
+	if err != nil {
+		return results, err
+	}
+
+	results = make([]listing.Board, 0)
+
+	if len(p.Boards) == 0 {
+		return results, nil
+	}
+
+	bs, err := s.repo.GetBoardsByIds(&p.Boards)
+	if err != nil {
+		return results, err
+	}
+
+	for i := range *bs {
+		results = append(results, *transformBoard(&(*bs)[i]))
+	}
+
+	return results, nil
+}
+
 // GetProjectBacklogIssues returns a paginated slice of project backlog issue entities from the respository.
 func (s *Storage) GetProjectBacklogIssues(projectID *string, p *listing.Pagination) (results []listing.Issue, count int64, err error) {
 	var projectIDAsObjectID primitive.ObjectID = primitive.ObjectID{}
@@ -605,28 +1023,17 @@ func (s *Storage) GetProjectBacklogIssues(projectID *string, p *listing.Paginati
 
 	results = make([]listing.Issue, 0)
 
-	for _, i := range *issues {
-		issue := listing.Issue{
-			ID:          i.ID.Hex(),
-			ProjectID:   getHexFromObjectID(i.ProjectID),
-			ProjectRef:  i.ProjectRef,
-			Type:        i.Type,
-			Summary:     i.Summary,
-			Description: i.Description,
-			Status:      i.Status,
-			Priority:    i.Priority,
-			Points:      i.Points,
-			Ordinal:     i.Ordinal,
-			CreatedAt:   i.CreatedAt,
-			UpdatedAt:   i.UpdatedAt,
-			ReporterID:  getHexFromObjectID(i.ReporterID),
-			AssigneeID:  getHexFromObjectID(i.AssigneeID),
-			Labels:      i.Labels,
-		}
+	for n, i := range *issues {
+		issue := transformIssue(&i)
+		issue.Ordinal = int32(n)
 
 		results = append(results, issue)
 	}
 
+	if err = s.setIssueSLAs(results, *issues); err != nil {
+		return results, count, err
+	}
+
 	if len(results) > 0 {
 		lastResult := results[len(results)-1]
 		p.Cursor = lastResult.ID
@@ -635,6 +1042,36 @@ func (s *Storage) GetProjectBacklogIssues(projectID *string, p *listing.Paginati
 	return results, count, nil
 }
 
+// GetProjectIssueTypes returns the issue type entities allowed by a project's issue type scheme from the repository.
+func (s *Storage) GetProjectIssueTypes(projectID *string) (results []listing.IssueType, err error) {
+	project, err := s.GetProjectByID(*projectID)
+	if err != nil {
+		return results, err
+	}
+
+	issueTypes, err := s.GetIssueTypes()
+	if err != nil {
+		return results, err
+	}
+
+	if len(project.IssueTypes) == 0 {
+		return issueTypes, nil
+	}
+
+	results = make([]listing.IssueType, 0)
+
+	for _, it := range issueTypes {
+		for _, id := range project.IssueTypes {
+			if it.ID == id {
+				results = append(results, it)
+				break
+			}
+		}
+	}
+
+	return results, nil
+}
+
 // GetProjects returns a paginated slice of project entities from the respository.
 func (s *Storage) GetProjects(p *listing.Pagination) (results []listing.Project, count int64, err error) {
 	var cursor primitive.ObjectID = primitive.ObjectID{}
@@ -713,29 +1150,17 @@ func (s *Storage) GetProjectSprintIssues(projectID *string, sprintID *string, p
 
 	results = make([]listing.Issue, 0)
 
-	for _, i := range *issues {
-		issue := listing.Issue{
-			ID:          i.ID.Hex(),
-			ProjectID:   getHexFromObjectID(i.ProjectID),
-			SprintID:    getHexFromObjectID(i.SprintID),
-			ProjectRef:  i.ProjectRef,
-			Type:        i.Type,
-			Summary:     i.Summary,
-			Description: i.Description,
-			Status:      i.Status,
-			Priority:    i.Priority,
-			Points:      i.Points,
-			Ordinal:     i.Ordinal,
-			CreatedAt:   i.CreatedAt,
-			UpdatedAt:   i.UpdatedAt,
-			ReporterID:  getHexFromObjectID(i.ReporterID),
-			AssigneeID:  getHexFromObjectID(i.AssigneeID),
-			Labels:      i.Labels,
-		}
+	for n, i := range *issues {
+		issue := transformIssue(&i)
+		issue.Ordinal = int32(n)
 
 		results = append(results, issue)
 	}
 
+	if err = s.setIssueSLAs(results, *issues); err != nil {
+		return results, count, err
+	}
+
 	if len(results) > 0 {
 		lastResult := results[len(results)-1]
 		p.Cursor = lastResult.ID
@@ -766,6 +1191,80 @@ func (s *Storage) GetProjectTypes(term *string) (results []listing.ProjectType,
 	return results, nil
 }
 
+// GetProjectVersionNotes returns the issues of a project version, grouped by issue type, from the repository.
+func (s *Storage) GetProjectVersionNotes(projectID *string, versionID *string) (result *listing.ReleaseNotes, err error) {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return result, err
+	}
+
+	versionIDAsObjectID, err := primitive.ObjectIDFromHex(*versionID)
+	if err != nil {
+		return result, err
+	}
+
+	p, err := s.repo.GetProject(projectIDAsObjectID)
+	if err != nil {
+		return result, err
+	}
+
+	v, err := getProjectVersion(p, versionIDAsObjectID)
+	if err != nil {
+		return result, err
+	}
+
+	query := bson.M{"fixVersions": versionIDAsObjectID}
+	sort := bson.D{primitive.E{Key: "projectRef", Value: 1}}
+
+	issues, _, err := s.repo.GetProjectIssues(&projectIDAsObjectID, nil, 0, query, sort)
+	if err != nil {
+		return result, err
+	}
+
+	issueTypes, err := s.repo.GetIssueTypes()
+	if err != nil {
+		return result, err
+	}
+
+	result = &listing.ReleaseNotes{
+		Version: transformVersion(v),
+		Groups:  []listing.ReleaseNotesGroup{},
+	}
+
+	for _, it := range *issueTypes {
+		group := listing.ReleaseNotesGroup{IssueType: it.ID, Name: it.Name, Issues: []listing.Issue{}}
+
+		for i := range *issues {
+			if (*issues)[i].Type == it.ID {
+				group.Issues = append(group.Issues, transformIssue(&(*issues)[i]))
+			}
+		}
+
+		if len(group.Issues) > 0 {
+			result.Groups = append(result.Groups, group)
+		}
+	}
+
+	return result, nil
+}
+
+// GetProjectVersions returns the version entities of a project from the repository.
+func (s *Storage) GetProjectVersions(projectID *string) (results []listing.Version, err error) {
+	objectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return results, err
+	}
+
+	p, err := s.repo.GetProject(objectID)
+	if err != nil {
+		return results, err
+	}
+
+	results = transformVersions(p.Versions)
+
+	return results, nil
+}
+
 // GetUsers returns all, or a filtered slice of user entities from the repository.
 func (s *Storage) GetUsers(term *string) (results []listing.User, err error) {
 	users, err := s.repo.GetUsers(term)
@@ -788,6 +1287,31 @@ func (s *Storage) GetUsers(term *string) (results []listing.User, err error) {
 	return results, nil
 }
 
+// GetUserNames returns the full names of users, keyed by user id, from the repository. Ids of users that do not exist
+// are left out.
+func (s *Storage) GetUserNames(userIDs []string) (map[string]string, error) {
+	ids := []primitive.ObjectID{}
+	for _, id := range userIDs {
+		idAsObjectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, idAsObjectID)
+	}
+
+	users, err := s.repo.GetUsersByID(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]string)
+	for _, u := range *users {
+		results[u.ID.Hex()] = strings.TrimSpace(u.Name.FirstName + " " + u.Name.LastName)
+	}
+
+	return results, nil
+}
+
 // GetWorkflows returns all workflow entities from the repository.
 func (s *Storage) GetWorkflows() (results []listing.Workflow, err error) {
 	workflows, err := s.repo.GetWorkflows()
@@ -810,11 +1334,34 @@ func (s *Storage) GetWorkflows() (results []listing.Workflow, err error) {
 			steps = append(steps, newStep)
 		}
 
+		transformRules := func(rs []TransitionRule) []listing.TransitionRule {
+			rules := []listing.TransitionRule{}
+			for _, r := range rs {
+				rules = append(rules, listing.TransitionRule{Type: r.Type, Params: r.Params})
+			}
+			return rules
+		}
+
+		transitions := []listing.WorkflowTransition{}
+
+		for _, t := range w.Transitions {
+			transition := listing.WorkflowTransition{
+				Name:          t.Name,
+				FromStatusIDs: t.FromStatusIDs,
+				ToStatusID:    t.ToStatusID,
+				Conditions:    transformRules(t.Conditions),
+				Validators:    transformRules(t.Validators),
+				PostFunctions: transformRules(t.PostFunctions),
+			}
+			transitions = append(transitions, transition)
+		}
+
 		workflow := listing.Workflow{
-			ID:       w.ID,
-			Name:     w.Name,
-			IsLocked: w.IsLocked,
-			Steps:    steps,
+			ID:          w.ID,
+			Name:        w.Name,
+			IsLocked:    w.IsLocked,
+			Steps:       steps,
+			Transitions: transitions,
 		}
 
 		results = append(results, workflow)
@@ -0,0 +1,198 @@
+package mongo
+
+import (
+	"fmt"
+
+	"github.com/njehyde/issue-tracker/libraries/slog"
+	"github.com/njehyde/issue-tracker/pkg/updating"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// issueMoveAttempts is the number of times a move is ranked again after a concurrent move changed its neighbours.
+const issueMoveAttempts = 3
+
+// MoveIssue places an issue before or after another issue of the backlog or a sprint, in the database's "issues"
+// collection. The rank is only written while the issue keeps the rank it was placed from, and the move is ranked
+// again where a concurrent move took the same rank or moved the issue it was placed against.
+func (s *Storage) MoveIssue(projectID *string, issueID *string, p *updating.IssuePosition, t *updating.TransitionIssue) error {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return err
+	}
+
+	issueIDAsObjectID, err := primitive.ObjectIDFromHex(*issueID)
+	if err != nil {
+		return err
+	}
+
+	l := issueList{projectID: projectIDAsObjectID}
+	if len(p.SprintID) > 0 {
+		if l.sprintID, err = s.getProjectSprintID(projectIDAsObjectID, p.SprintID); err != nil {
+			return err
+		}
+	}
+
+	issue, err := s.repo.GetIssue(issueIDAsObjectID)
+	if err != nil {
+		return err
+	}
+	if issue.ProjectID != projectIDAsObjectID {
+		return fmt.Errorf("Issue %v not found for project %v", *issueID, *projectID)
+	}
+
+	anchorID, after := p.BeforeID, false
+	if len(p.AfterID) > 0 {
+		anchorID, after = p.AfterID, true
+	}
+
+	setMap := bson.M{"sprintId": nil}
+	if !l.sprintID.IsZero() {
+		setMap["sprintId"] = l.sprintID
+	}
+	if t != nil {
+		transition, err := transitionUpdate(t)
+		if err != nil {
+			return err
+		}
+		for k, v := range transition {
+			setMap[k] = v
+		}
+	}
+
+	current := issue.Rank
+	var written bson.M
+
+	// Once a placement has been written the issue has moved, and its status may have changed, so from then on the
+	// move is kept where it was last placed rather than failed, and its changes are always recorded
+	for attempt := 1; attempt <= issueMoveAttempts; attempt++ {
+		rank, anchor, err := s.getIssueMoveRank(l, issueIDAsObjectID, anchorID, after)
+		if err != nil {
+			if written != nil {
+				break
+			}
+			return err
+		}
+
+		set := bson.M{"rank": rank}
+		for k, v := range setMap {
+			set[k] = v
+		}
+		update := bson.M{"$set": set}
+
+		ok, err := s.repo.UpdateIssueIfRank(issueIDAsObjectID, current, update)
+		if err != nil {
+			if written != nil {
+				break
+			}
+			return err
+		}
+		if !ok {
+			// The issue was moved since its rank was read, so this move is placed against its new rank
+			moved, err := s.repo.GetIssue(issueIDAsObjectID)
+			if err != nil {
+				if written != nil {
+					break
+				}
+				return err
+			}
+			current = moved.Rank
+			continue
+		}
+
+		current = rank
+		written = update
+
+		settled, err := s.checkIssueMove(l, issueIDAsObjectID, rank, anchor)
+		if err != nil || settled {
+			break
+		}
+	}
+
+	if written == nil {
+		return fmt.Errorf("Issue %v is being moved concurrently, please try again", issue.ProjectRef)
+	}
+
+	s.checkIssueRanks(l, current)
+
+	err = s.recordIssueChanges([]Issue{*issue}, map[primitive.ObjectID]interface{}{issueIDAsObjectID: written})
+	if err != nil {
+		slog.Errorf("Changes of moved issue %v could not be recorded: %v", issue.ProjectRef, err)
+	}
+
+	return nil
+}
+
+// getIssueMoveRank returns the rank key that places an issue next to an anchor issue of the backlog or a sprint, and
+// the anchor as it was read. Without an anchor the issue is placed at the bottom.
+func (s *Storage) getIssueMoveRank(l issueList, issueID primitive.ObjectID, anchorID string, after bool) (string, *Issue, error) {
+	others := l.filter()
+	others["_id"] = bson.M{"$ne": issueID}
+
+	if len(anchorID) == 0 {
+		last, err := s.repo.GetIssueListRank(l.projectID, others, -1)
+		if err != nil {
+			return "", nil, err
+		}
+
+		rank, err := rankBetween(last, "")
+		return rank, nil, err
+	}
+
+	anchorIDAsObjectID, err := primitive.ObjectIDFromHex(anchorID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	anchor, err := s.repo.GetIssue(anchorIDAsObjectID)
+	if err != nil {
+		return "", nil, err
+	}
+	if anchor.ProjectID != l.projectID || anchor.SprintID != l.sprintID {
+		return "", nil, fmt.Errorf("Issue %v is not in the same backlog or sprint", anchor.ProjectRef)
+	}
+
+	var prev, next string
+	if after {
+		others["rank"] = bson.M{"$gt": anchor.Rank}
+		prev = anchor.Rank
+		next, err = s.repo.GetIssueListRank(l.projectID, others, 1)
+	} else {
+		others["rank"] = bson.M{"$lt": anchor.Rank}
+		prev, err = s.repo.GetIssueListRank(l.projectID, others, -1)
+		next = anchor.Rank
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	rank, err := rankBetween(prev, next)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return rank, anchor, nil
+}
+
+// checkIssueMove returns whether a moved issue holds its rank alone, next to an anchor that has not moved since.
+func (s *Storage) checkIssueMove(l issueList, issueID primitive.ObjectID, rank string, anchor *Issue) (bool, error) {
+	query := l.filter()
+	query["_id"] = bson.M{"$ne": issueID}
+	query["rank"] = rank
+
+	count, err := s.repo.CountProjectIssues(&l.projectID, query)
+	if err != nil || count > 0 {
+		return false, err
+	}
+
+	if anchor == nil {
+		return true, nil
+	}
+
+	current, err := s.repo.GetIssue(anchor.ID)
+	if err != nil {
+		return false, err
+	}
+
+	return current.Rank == anchor.Rank && current.SprintID == anchor.SprintID, nil
+}
@@ -0,0 +1,198 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/njehyde/issue-tracker/libraries/slog"
+	"github.com/njehyde/issue-tracker/pkg/listing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Notification defines the storage form of a user notification entity.
+type Notification struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	UserID    primitive.ObjectID `bson:"userId"`
+	ProjectID primitive.ObjectID `bson:"projectId"`
+	IssueID   primitive.ObjectID `bson:"issueId"`
+	Type      string             `bson:"type"`
+	Message   string             `bson:"message"`
+	ReadAt    *time.Time         `bson:"readAt,omitempty"`
+	CreatedAt time.Time          `bson:"createdAt"`
+}
+
+// Notification types.
+const (
+	notificationSLABreached = "SLA_BREACHED"
+	notificationOverdue     = "ISSUE_OVERDUE"
+)
+
+// AddNotification ...
+func (r *Repository) AddNotification(n *Notification) error {
+	collection := r.db.Collection("notifications")
+
+	n.ID = primitive.NewObjectID()
+	n.CreatedAt = time.Now()
+
+	insertResult, err := collection.InsertOne(context.Background(), n)
+	if err != nil {
+		return err
+	}
+
+	slog.Infof("Added notification %v: %+v", n.ID.Hex(), insertResult)
+
+	return nil
+}
+
+// GetNotifications ...
+func (r *Repository) GetNotifications(userID *primitive.ObjectID, cursor *primitive.ObjectID, limit int64, unread bool) (*[]Notification, int64, error) {
+	var notifications []Notification
+	var count int64
+
+	collection := r.db.Collection("notifications")
+
+	filter := bson.M{"userId": userID}
+	if unread {
+		filter["readAt"] = bson.M{"$exists": false}
+	}
+
+	count, err := collection.CountDocuments(context.Background(), filter)
+	if err != nil {
+		return &notifications, count, err
+	}
+
+	// Newest first, so the cursor pages backwards through the ids
+	if cursor != nil && !cursor.IsZero() {
+		filter["_id"] = bson.M{"$lt": cursor}
+	}
+
+	findOptions := options.Find().SetLimit(limit).SetSort(
+		bson.D{
+			primitive.E{Key: "_id", Value: -1},
+		},
+	)
+
+	cur, err := collection.Find(context.Background(), filter, findOptions)
+	if err != nil {
+		return &notifications, count, err
+	}
+	defer cur.Close(context.Background())
+
+	for cur.Next(context.Background()) {
+		var n Notification
+
+		err = cur.Decode(&n)
+		if err != nil {
+			return &notifications, count, err
+		}
+
+		notifications = append(notifications, n)
+	}
+
+	return &notifications, count, nil
+}
+
+// UpdateNotification ...
+func (r *Repository) UpdateNotification(userID *primitive.ObjectID, notificationID *primitive.ObjectID, update primitive.M) error {
+	collection := r.db.Collection("notifications")
+
+	filter := bson.M{"_id": notificationID, "userId": userID}
+
+	updateResult, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return fmt.Errorf("Notification %v not found", notificationID.Hex())
+	}
+
+	slog.Infof("Updated notification %v: %+v", notificationID.Hex(), updateResult)
+
+	return nil
+}
+
+// GetNotifications returns a paginated slice of a user's notification entities, newest first, from the database's
+// "notifications" collection.
+func (s *Storage) GetNotifications(userID *string, unread bool, p *listing.Pagination) (results []listing.Notification, count int64, err error) {
+	var cursor primitive.ObjectID
+
+	if len(p.Cursor) > 0 {
+		cursor, err = primitive.ObjectIDFromHex(p.Cursor)
+		if err != nil {
+			return results, count, err
+		}
+	}
+
+	userIDAsObjectID, err := primitive.ObjectIDFromHex(*userID)
+	if err != nil {
+		return results, count, err
+	}
+
+	notifications, count, err := s.repo.GetNotifications(&userIDAsObjectID, &cursor, int64(p.PageSize), unread)
+	if err != nil {
+		return results, count, err
+	}
+
+	results = make([]listing.Notification, 0)
+
+	for _, n := range *notifications {
+		results = append(results, listing.Notification{
+			ID:        n.ID.Hex(),
+			ProjectID: getHexFromObjectID(n.ProjectID),
+			IssueID:   getHexFromObjectID(n.IssueID),
+			Type:      n.Type,
+			Message:   n.Message,
+			ReadAt:    n.ReadAt,
+			CreatedAt: n.CreatedAt,
+		})
+	}
+
+	if len(results) > 0 {
+		p.Cursor = results[len(results)-1].ID
+	}
+
+	return results, count, nil
+}
+
+// ReadNotification marks a user's notification entity as read in the database's "notifications" collection.
+func (s *Storage) ReadNotification(userID *string, notificationID *string) error {
+	userIDAsObjectID, err := primitive.ObjectIDFromHex(*userID)
+	if err != nil {
+		return err
+	}
+
+	notificationIDAsObjectID, err := primitive.ObjectIDFromHex(*notificationID)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{"$set": bson.M{"readAt": time.Now()}}
+
+	return s.repo.UpdateNotification(&userIDAsObjectID, &notificationIDAsObjectID, update)
+}
+
+// notifyIssueUsers adds a notification about an issue for its assignee, or for its project's lead when the issue is
+// unassigned, to the database's "notifications" collection.
+func (s *Storage) notifyIssueUsers(issue *Issue, project *Project, notificationType string, message string) error {
+	userID := issue.AssigneeID
+	if userID.IsZero() {
+		userID = project.LeadID
+	}
+	if userID.IsZero() {
+		return nil
+	}
+
+	n := Notification{
+		UserID:    userID,
+		ProjectID: issue.ProjectID,
+		IssueID:   issue.ID,
+		Type:      notificationType,
+		Message:   message,
+	}
+
+	return s.repo.AddNotification(&n)
+}
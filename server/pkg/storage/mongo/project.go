@@ -2,6 +2,7 @@ package mongo
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/njehyde/issue-tracker/libraries/slog"
@@ -21,6 +22,11 @@ type Project struct {
 	DefaultAssigneeID primitive.ObjectID   `bson:"defaultAssigneeId"`
 	DefaultBoardID    primitive.ObjectID   `bson:"defaultBoardId"`
 	Boards            []primitive.ObjectID `bson:"boards"`
+	IssueTypes        []string             `bson:"issueTypes"`
+	Components        []Component          `bson:"components"`
+	Versions          []Version            `bson:"versions"`
+	SLAPolicies       []SLAPolicy          `bson:"slaPolicies,omitempty"`
+	IssueTemplates    []IssueTemplate      `bson:"issueTemplates,omitempty"`
 	CreatedAt         time.Time            `bson:"createdAt"`
 	UpdatedAt         time.Time            `bson:"updatedAt"`
 }
@@ -150,6 +156,131 @@ func (r *Repository) UpdateProject(ID primitive.ObjectID, update primitive.M) er
 	return nil
 }
 
+// UpdateManyProjects ...
+func (r *Repository) UpdateManyProjects(filter primitive.M, update primitive.M) error {
+	collection := r.db.Collection("projects")
+
+	updateResult, err := collection.UpdateMany(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	slog.Infof("Updated %v projects: %+v", updateResult.ModifiedCount, updateResult)
+
+	return nil
+}
+
+// Component defines the storage form of a project component entity.
+type Component struct {
+	ID           primitive.ObjectID `bson:"_id"`
+	Name         string             `bson:"name"`
+	Description  string             `bson:"description"`
+	LeadID       primitive.ObjectID `bson:"leadId"`
+	AssigneeType string             `bson:"assigneeType"`
+	CreatedAt    time.Time          `bson:"createdAt"`
+	UpdatedAt    time.Time          `bson:"updatedAt"`
+}
+
+// Component assignee types.
+const (
+	componentAssigneeProjectDefault = "PROJECT_DEFAULT"
+	componentAssigneeComponentLead  = "COMPONENT_LEAD"
+	componentAssigneeUnassigned     = "UNASSIGNED"
+)
+
+// AddProjectComponent ...
+func (r *Repository) AddProjectComponent(projectID primitive.ObjectID, c *Component) error {
+	collection := r.db.Collection("projects")
+
+	now := time.Now()
+
+	c.ID = primitive.NewObjectID()
+	c.CreatedAt = now
+	c.UpdatedAt = now
+
+	filter := bson.M{"_id": projectID}
+
+	update := bson.M{
+		"$set": bson.M{
+			"updatedAt": now,
+		},
+		"$push": bson.M{
+			"components": c,
+		},
+	}
+
+	updateResult, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return fmt.Errorf("Project %v not found", projectID.Hex())
+	}
+
+	slog.Infof("Added component %v via update to project %v: %+v", c.ID.Hex(), projectID.Hex(), updateResult)
+
+	return nil
+}
+
+// DeleteProjectComponent ...
+func (r *Repository) DeleteProjectComponent(projectID primitive.ObjectID, componentID primitive.ObjectID) error {
+	collection := r.db.Collection("projects")
+
+	filter := bson.M{"_id": projectID, "components._id": componentID}
+
+	update := bson.M{
+		"$set": bson.M{
+			"updatedAt": time.Now(),
+		},
+		"$pull": bson.M{
+			"components": bson.M{
+				"_id": componentID,
+			},
+		},
+	}
+
+	updateResult, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return fmt.Errorf("Component %v not found for project %v", componentID.Hex(), projectID.Hex())
+	}
+
+	slog.Infof("Deleted component %v via update to project %v: %+v", componentID.Hex(), projectID.Hex(), updateResult)
+
+	return nil
+}
+
+// UpdateProjectComponent ...
+func (r *Repository) UpdateProjectComponent(projectID primitive.ObjectID, componentID primitive.ObjectID, set primitive.M) error {
+	collection := r.db.Collection("projects")
+
+	now := time.Now()
+
+	filter := bson.M{"_id": projectID, "components._id": componentID}
+
+	update := bson.M{"$set": bson.M{"updatedAt": now, "components.$.updatedAt": now}}
+	for k, v := range set {
+		update["$set"].(bson.M)["components.$."+k] = v
+	}
+
+	updateResult, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return fmt.Errorf("Component %v not found for project %v", componentID.Hex(), projectID.Hex())
+	}
+
+	slog.Infof("Updated component %v via update to project %v: %+v", componentID.Hex(), projectID.Hex(), updateResult)
+
+	return nil
+}
+
 // ProjectType defines the storage form of a project type entity.
 type ProjectType struct {
 	ID        string `bson:"_id"`
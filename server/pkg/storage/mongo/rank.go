@@ -0,0 +1,438 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/njehyde/issue-tracker/libraries/slog"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Issues are ordered within the backlog, or a sprint, by a rank key. Rank keys are strings of base 36 digits that
+// sort lexicographically and never end in a zero, so there is always room for a key between any two others, and
+// moving an issue only writes the issue itself.
+const rankDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// rankMaxLength is the length of rank key past which the issues of a backlog or sprint are rebalanced.
+const rankMaxLength = 12
+
+// rankBetween returns a rank key that sorts between two others. An empty prev is before every key, and an empty next
+// is after every key.
+func rankBetween(prev string, next string) (string, error) {
+	if len(next) > 0 && prev >= next {
+		return "", fmt.Errorf("Rank %v is not before rank %v", prev, next)
+	}
+	if strings.HasSuffix(prev, "0") || strings.HasSuffix(next, "0") {
+		return "", fmt.Errorf("Rank keys cannot end in a zero")
+	}
+
+	return rankMidpoint(prev, next), nil
+}
+
+func rankMidpoint(prev string, next string) string {
+	// Keep any prefix the keys share, missing digits of prev counting as zeros
+	if len(next) > 0 {
+		n := 0
+		for n < len(next) && rankDigit(prev, n) == strings.IndexByte(rankDigits, next[n]) {
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(prev) {
+				rest = prev[n:]
+			}
+			return next[:n] + rankMidpoint(rest, next[n:])
+		}
+	}
+
+	prevDigit := rankDigit(prev, 0)
+	nextDigit := len(rankDigits)
+	if len(next) > 0 {
+		nextDigit = strings.IndexByte(rankDigits, next[0])
+	}
+
+	if nextDigit-prevDigit > 1 {
+		return string(rankDigits[(prevDigit+nextDigit+1)/2])
+	}
+
+	// The first digits are adjacent, so a shorter next is the key itself, or the key extends prev
+	if len(next) > 1 {
+		return next[:1]
+	}
+
+	rest := ""
+	if len(prev) > 1 {
+		rest = prev[1:]
+	}
+	return string(rankDigits[prevDigit]) + rankMidpoint(rest, "")
+}
+
+func rankDigit(key string, i int) int {
+	if i >= len(key) {
+		return 0
+	}
+	return strings.IndexByte(rankDigits, key[i])
+}
+
+// rankKeys returns count rank keys, in order, spread between two others. Keys are placed by bisection, so they
+// stay short for the size of the list.
+func rankKeys(prev string, next string, count int) ([]string, error) {
+	if count <= 0 {
+		return []string{}, nil
+	}
+
+	mid, err := rankBetween(prev, next)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := rankKeys(prev, mid, count/2)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := rankKeys(mid, next, count-count/2-1)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(append(before, mid), after...), nil
+}
+
+// issueList identifies the issues ranked together: the backlog of a project where the sprint id is zero, or a sprint.
+type issueList struct {
+	projectID primitive.ObjectID
+	sprintID  primitive.ObjectID
+}
+
+func (l issueList) filter() bson.M {
+	if l.sprintID.IsZero() {
+		return bson.M{"sprintId": bson.M{"$eq": nil}}
+	}
+	return bson.M{"sprintId": l.sprintID}
+}
+
+// GetIssueListRank ...
+func (r *Repository) GetIssueListRank(projectID primitive.ObjectID, filter bson.M, order int32) (string, error) {
+	var i Issue
+
+	collection := r.db.Collection("issues")
+
+	query := bson.M{"projectId": projectID}
+	for k, v := range filter {
+		query[k] = v
+	}
+
+	findOptions := options.FindOne().SetSort(
+		bson.D{
+			primitive.E{Key: "rank", Value: order},
+			primitive.E{Key: "_id", Value: order},
+		},
+	)
+
+	err := collection.FindOne(context.Background(), query, findOptions).Decode(&i)
+	if err == mongo.ErrNoDocuments {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return i.Rank, nil
+}
+
+// getIssueListLastRanks returns count rank keys for issues added to the bottom of the backlog, or a sprint.
+func (s *Storage) getIssueListLastRanks(l issueList, count int) ([]string, error) {
+	if s.isIssueListRebalancing(l) {
+		return nil, errIssueListRebalancing
+	}
+
+	last, err := s.repo.GetIssueListRank(l.projectID, l.filter(), -1)
+	if err != nil {
+		return nil, err
+	}
+
+	ranks, err := rankKeys(last, "", count)
+	if err != nil {
+		return nil, err
+	}
+
+	s.checkIssueRanks(l, ranks...)
+
+	return ranks, nil
+}
+
+// getIssueListFirstRank returns the rank key for an issue added to the top of the backlog, or a sprint.
+func (s *Storage) getIssueListFirstRank(l issueList) (string, error) {
+	if s.isIssueListRebalancing(l) {
+		return "", errIssueListRebalancing
+	}
+
+	first, err := s.repo.GetIssueListRank(l.projectID, l.filter(), 1)
+	if err != nil {
+		return "", err
+	}
+
+	rank, err := rankBetween("", first)
+	if err != nil {
+		return "", err
+	}
+
+	s.checkIssueRanks(l, rank)
+
+	return rank, nil
+}
+
+// getIssueListPositionRank returns the rank key that places an issue at an ordinal position among the other issues of
+// the backlog, or a sprint. Positions past the end place the issue last, and an issue already at the position keeps
+// its rank key.
+func (s *Storage) getIssueListPositionRank(l issueList, issueID primitive.ObjectID, ordinal int32) (string, error) {
+	if s.isIssueListRebalancing(l) {
+		return "", errIssueListRebalancing
+	}
+
+	siblings, _, err := s.repo.GetProjectIssues(&l.projectID, nil, 0, l.filter(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	others := []Issue{}
+	current := -1
+	for n, i := range *siblings {
+		if i.ID != issueID {
+			others = append(others, i)
+		} else {
+			current = n
+		}
+	}
+
+	position := int(ordinal)
+	if position < 0 {
+		position = 0
+	}
+	if position > len(others) {
+		position = len(others)
+	}
+	if position == current {
+		return (*siblings)[current].Rank, nil
+	}
+
+	var prev, next string
+	if position > 0 {
+		prev = others[position-1].Rank
+	}
+	if position < len(others) {
+		next = others[position].Rank
+	}
+
+	// Neighbours sharing a rank key leave no room between them until the list is rebalanced
+	if len(next) > 0 && prev >= next {
+		err = s.rebalanceIssueList(l, bson.D{
+			primitive.E{Key: "rank", Value: 1},
+			primitive.E{Key: "_id", Value: 1},
+		})
+		if err != nil {
+			return "", err
+		}
+		return s.getIssueListPositionRank(l, issueID, ordinal)
+	}
+
+	rank, err := rankBetween(prev, next)
+	if err != nil {
+		return "", err
+	}
+
+	s.checkIssueRanks(l, rank)
+
+	return rank, nil
+}
+
+// errIssueListChanged is returned by a rebalance when issues of the backlog or sprint were moved while it ran.
+var errIssueListChanged = errors.New("Issues were moved while their ranks were rebalanced, please try again")
+
+// errIssueListRebalancing is returned for rank keys of a backlog or sprint that is being rebalanced, as they would be
+// placed among rank keys that are about to be replaced.
+var errIssueListRebalancing = errors.New("Issue ranks are being rebalanced, please try again")
+
+// startIssueListRebalance marks a backlog, or sprint, as being rebalanced. It returns false where it already is, as a
+// list only has one rebalance at a time.
+func (s *Storage) startIssueListRebalance(l issueList) bool {
+	s.rebalancingLock.Lock()
+	defer s.rebalancingLock.Unlock()
+
+	if s.rebalancing[l] {
+		return false
+	}
+	s.rebalancing[l] = true
+
+	return true
+}
+
+func (s *Storage) finishIssueListRebalance(l issueList) {
+	s.rebalancingLock.Lock()
+	defer s.rebalancingLock.Unlock()
+
+	delete(s.rebalancing, l)
+}
+
+func (s *Storage) isIssueListRebalancing(l issueList) bool {
+	s.rebalancingLock.Lock()
+	defer s.rebalancingLock.Unlock()
+
+	return s.rebalancing[l]
+}
+
+// checkIssueRanks queues the backlog, or sprint, for rebalancing where any of its new rank keys has grown too long.
+func (s *Storage) checkIssueRanks(l issueList, ranks ...string) {
+	for _, rank := range ranks {
+		if len(rank) > rankMaxLength {
+			s.queueIssueListRebalance(l)
+			return
+		}
+	}
+}
+
+func (s *Storage) queueIssueListRebalance(l issueList) {
+	select {
+	case s.rebalance <- l:
+	default:
+		slog.Infof("Rank rebalancing queue is full, skipping %v", l.projectID.Hex())
+	}
+}
+
+// RebalanceIssueRanks respaces the rank keys of each backlog or sprint queued for rebalancing, keeping the order of
+// its issues. It runs for the life of the process.
+func (s *Storage) RebalanceIssueRanks() {
+	for l := range s.rebalance {
+		err := s.rebalanceIssueList(l, bson.D{
+			primitive.E{Key: "rank", Value: 1},
+			primitive.E{Key: "_id", Value: 1},
+		})
+		if err == errIssueListChanged {
+			s.queueIssueListRebalance(l)
+			continue
+		}
+		if err == errIssueListRebalancing {
+			slog.Infof("Issue ranks for project %v are already being rebalanced", l.projectID.Hex())
+			continue
+		}
+		if err != nil {
+			slog.Errorf("Failed to rebalance issue ranks: %v", err.Error())
+		}
+	}
+}
+
+// rebalanceIssueList replaces the rank keys of a backlog, or sprint, with evenly spaced ones. The new keys are written
+// to each issue's "nextRank" alongside the rank key it was read with, and only replace the rank keys once every issue
+// has one, so that the list is never ordered by a mix of old and new keys.
+func (s *Storage) rebalanceIssueList(l issueList, sort bson.D) error {
+	if !s.startIssueListRebalance(l) {
+		return errIssueListRebalancing
+	}
+	defer s.finishIssueListRebalance(l)
+
+	issues, _, err := s.repo.GetProjectIssues(&l.projectID, nil, 0, l.filter(), sort)
+	if err != nil {
+		return err
+	}
+
+	ranks, err := rankKeys("", "", len(*issues))
+	if err != nil {
+		return err
+	}
+
+	// Issues without a rank key, which sort first, follow those with one, as the issues an interrupted migration
+	// already ranked were ahead of those it had not reached
+	sorted := make([]Issue, 0, len(*issues))
+	for _, ranked := range []bool{true, false} {
+		for _, issue := range *issues {
+			if (len(issue.Rank) > 0) == ranked {
+				sorted = append(sorted, issue)
+			}
+		}
+	}
+	issues = &sorted
+
+	slog.Infof("Rebalancing %v issue ranks for project %v", len(*issues), l.projectID.Hex())
+
+	// Each new rank key is only written while the issue keeps the rank it was read with
+	changed := false
+	read := make(map[primitive.ObjectID]string)
+	for i, issue := range *issues {
+		ok, err := s.repo.UpdateIssueIfRank(issue.ID, issue.Rank, bson.M{"$set": bson.M{"nextRank": ranks[i]}})
+		if err != nil {
+			return err
+		}
+		if !ok {
+			changed = true
+		}
+		read[issue.ID] = issue.Rank
+	}
+
+	// Issues moved within, into or out of the list while the new keys were written were ranked against the old ones
+	if !changed {
+		current, _, err := s.repo.GetProjectIssues(&l.projectID, nil, 0, l.filter(), nil)
+		if err != nil {
+			return err
+		}
+
+		changed = len(*current) != len(read)
+		for _, issue := range *current {
+			if rank, ok := read[issue.ID]; !ok || rank != issue.Rank {
+				changed = true
+			}
+		}
+	}
+
+	staged := l.filter()
+	staged["nextRank"] = bson.M{"$exists": true}
+
+	if changed {
+		if err = s.repo.UpdateManyIssues(staged, bson.M{"$unset": bson.M{"nextRank": ""}}); err != nil {
+			return err
+		}
+		return errIssueListChanged
+	}
+
+	return s.repo.UpdateManyIssuesWithPipeline(staged, mongo.Pipeline{
+		bson.D{primitive.E{Key: "$set", Value: bson.M{"rank": "$nextRank"}}},
+		bson.D{primitive.E{Key: "$unset", Value: bson.A{"nextRank", "ordinal"}}},
+	})
+}
+
+// MigrateIssueRanks gives rank keys to issues saved with only an ordinal position, keeping the order of each backlog
+// and sprint. It is safe to run on every start.
+func (s *Storage) MigrateIssueRanks() error {
+	issues, _, err := s.repo.GetIssues(nil, 0, bson.M{"rank": bson.M{"$exists": false}}, nil)
+	if err != nil {
+		return err
+	}
+
+	lists := make(map[issueList]bool)
+	for _, i := range *issues {
+		lists[issueList{i.ProjectID, i.SprintID}] = true
+	}
+
+	// Issues an interrupted run already migrated have a rank and no ordinal, and keep their place ahead of the rest
+	for l := range lists {
+		err = s.rebalanceIssueList(l, bson.D{
+			primitive.E{Key: "rank", Value: 1},
+			primitive.E{Key: "ordinal", Value: 1},
+			primitive.E{Key: "_id", Value: 1},
+		})
+		if err == errIssueListChanged {
+			s.queueIssueListRebalance(l)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
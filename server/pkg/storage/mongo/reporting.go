@@ -0,0 +1,445 @@
+package mongo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/njehyde/issue-tracker/pkg/reporting"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetIssueHistory returns the history of an issue from the database's "issues" and "issue_changes" collections.
+func (s *Storage) GetIssueHistory(issueID *string) (*reporting.IssueHistory, error) {
+	issueIDAsObjectID, err := primitive.ObjectIDFromHex(*issueID)
+	if err != nil {
+		return nil, err
+	}
+
+	issue, err := s.repo.GetIssue(issueIDAsObjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	histories, err := s.getIssueHistories([]Issue{*issue})
+	if err != nil {
+		return nil, err
+	}
+
+	return &histories[0], nil
+}
+
+// GetIssueStatusCategories returns the category of every issue status in the database's "issue_statuses" collection,
+// keyed by issue status id.
+func (s *Storage) GetIssueStatusCategories() (map[string]string, error) {
+	term := ""
+	issueStatuses, err := s.repo.GetIssueStatuses(&term, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]string)
+	for _, is := range issueStatuses {
+		results[is.ID] = is.CategoryID
+	}
+
+	return results, nil
+}
+
+// GetProjectBoardColumnHistory returns a project's board, and the changes made to its columns, from the database's
+// "boards" and "board_column_changes" collections.
+func (s *Storage) GetProjectBoardColumnHistory(projectID *string, boardID *string) (*reporting.Board, error) {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	boardIDAsObjectID, err := primitive.ObjectIDFromHex(*boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	board, err := s.getProjectBoard(projectIDAsObjectID, boardIDAsObjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := s.repo.GetBoardColumnChanges(boardIDAsObjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := reporting.Board{
+		ID:            board.ID.Hex(),
+		Name:          board.Name,
+		Columns:       transformReportingBoardColumns(board.Columns),
+		ColumnChanges: []reporting.BoardColumnChange{},
+	}
+
+	for _, c := range *changes {
+		result.ColumnChanges = append(result.ColumnChanges, reporting.BoardColumnChange{
+			From:      transformReportingBoardColumns(c.From),
+			CreatedAt: c.CreatedAt,
+		})
+	}
+
+	if board.Sprints != nil {
+		result.Sprints = []reporting.Sprint{}
+		for i := range board.Sprints {
+			result.Sprints = append(result.Sprints, transformReportingSprint(board, &board.Sprints[i]))
+		}
+	}
+
+	return &result, nil
+}
+
+// GetProjectBoardIssueHistories returns the history of every issue matching the filters of a project's board created
+// before a point in time, from the database's "boards", "issues" and "issue_changes" collections.
+func (s *Storage) GetProjectBoardIssueHistories(projectID *string, boardID *string, until time.Time) ([]reporting.IssueHistory, error) {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	boardIDAsObjectID, err := primitive.ObjectIDFromHex(*boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	board, err := s.getProjectBoard(projectIDAsObjectID, boardIDAsObjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := s.getBoardIssueQuery(board)
+	if err != nil {
+		return nil, err
+	}
+
+	// The board's own filters may be on the creation date too
+	query = bson.M{"$and": []bson.M{query, {"createdAt": bson.M{"$lte": until}}}}
+
+	issues, _, err := s.repo.GetProjectIssues(&projectIDAsObjectID, nil, 0, query, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getIssueHistories(*issues)
+}
+
+// GetProjectBoardSprints returns the sprint child entities of a project's board from the database's "boards"
+// collection.
+func (s *Storage) GetProjectBoardSprints(projectID *string, boardID *string) ([]reporting.Sprint, error) {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	boardIDAsObjectID, err := primitive.ObjectIDFromHex(*boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	board, err := s.getProjectBoard(projectIDAsObjectID, boardIDAsObjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []reporting.Sprint{}
+	for i := range board.Sprints {
+		results = append(results, transformReportingSprint(board, &board.Sprints[i]))
+	}
+
+	return results, nil
+}
+
+// GetProjectEpicIssueTimes returns the estimates and time spent of an epic, and of each issue grouped under it, from the
+// database's "issues" collection.
+func (s *Storage) GetProjectEpicIssueTimes(projectID *string, epicID *string) ([]reporting.IssueTime, error) {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	epicIDAsObjectID, err := s.getProjectEpicID(projectIDAsObjectID, *epicID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := bson.M{"$or": []bson.M{{"_id": epicIDAsObjectID}, {"epicId": epicIDAsObjectID}}}
+
+	return s.getIssueTimes(projectIDAsObjectID, query)
+}
+
+// GetProjectIssueHistories returns the history of every issue of a project created before a point in time, from the
+// database's "issues" and "issue_changes" collections.
+func (s *Storage) GetProjectIssueHistories(projectID *string, until time.Time) ([]reporting.IssueHistory, error) {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := bson.M{"createdAt": bson.M{"$lte": until}}
+
+	issues, _, err := s.repo.GetProjectIssues(&projectIDAsObjectID, nil, 0, query, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getIssueHistories(*issues)
+}
+
+// GetProjectSprint returns a sprint child entity of one of a project's boards from the database's "boards" collection.
+func (s *Storage) GetProjectSprint(projectID *string, sprintID *string) (*reporting.Sprint, error) {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	sprintIDAsObjectID, err := primitive.ObjectIDFromHex(*sprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := s.repo.GetProject(projectIDAsObjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, boardID := range project.Boards {
+		board, err := s.repo.GetBoard(&boardID)
+		if err != nil {
+			return nil, err
+		}
+
+		sprint, err := getBoardSprint(board, sprintIDAsObjectID)
+		if err != nil {
+			continue
+		}
+
+		result := transformReportingSprint(board, sprint)
+		return &result, nil
+	}
+
+	return nil, fmt.Errorf("Sprint %v not found for project %v", *sprintID, *projectID)
+}
+
+// GetProjectSprintIssueHistories returns the history of every issue that is, or has been, in a sprint, from the
+// database's "issues" and "issue_changes" collections.
+func (s *Storage) GetProjectSprintIssueHistories(projectID *string, sprintID *string) ([]reporting.IssueHistory, error) {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	sprintIDAsObjectID, err := primitive.ObjectIDFromHex(*sprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	sprintChanges, err := s.repo.GetIssueChanges(bson.M{
+		"projectId": projectIDAsObjectID,
+		"field":     "sprintId",
+		"$or": []bson.M{
+			{"from": sprintIDAsObjectID},
+			{"to": sprintIDAsObjectID},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	issueIDs := []primitive.ObjectID{}
+	for _, c := range *sprintChanges {
+		issueIDs = append(issueIDs, c.IssueID)
+	}
+
+	query := bson.M{"$or": []bson.M{
+		{"sprintId": sprintIDAsObjectID},
+		{"_id": bson.M{"$in": issueIDs}},
+	}}
+
+	issues, _, err := s.repo.GetProjectIssues(&projectIDAsObjectID, nil, 0, query, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getIssueHistories(*issues)
+}
+
+// GetProjectSprintIssueTimes returns the estimates and time spent of each issue in a sprint from the database's
+// "issues" collection.
+func (s *Storage) GetProjectSprintIssueTimes(projectID *string, sprintID *string) ([]reporting.IssueTime, error) {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	sprintIDAsObjectID, err := primitive.ObjectIDFromHex(*sprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getIssueTimes(projectIDAsObjectID, bson.M{"sprintId": sprintIDAsObjectID})
+}
+
+// GetUserWorklogs returns the work a user logged between two points in time, with the issues it was logged against,
+// from the database's "issue_worklogs" and "issues" collections.
+func (s *Storage) GetUserWorklogs(userID *string, from time.Time, to time.Time) ([]reporting.Worklog, error) {
+	userIDAsObjectID, err := primitive.ObjectIDFromHex(*userID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"userId":    userIDAsObjectID,
+		"startedAt": bson.M{"$gte": from, "$lt": to},
+	}
+
+	worklogs, err := s.repo.GetWorklogs(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	issueIDs := []primitive.ObjectID{}
+	seen := make(map[primitive.ObjectID]bool)
+	for _, w := range *worklogs {
+		if !seen[w.IssueID] {
+			seen[w.IssueID] = true
+			issueIDs = append(issueIDs, w.IssueID)
+		}
+	}
+
+	issuesMap := make(map[primitive.ObjectID]Issue)
+	if len(issueIDs) > 0 {
+		issues, err := s.repo.GetIssuesByIds(&issueIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, i := range *issues {
+			issuesMap[i.ID] = i
+		}
+	}
+
+	results := []reporting.Worklog{}
+	for _, w := range *worklogs {
+		i := issuesMap[w.IssueID]
+		results = append(results, reporting.Worklog{
+			IssueID:    w.IssueID.Hex(),
+			ProjectID:  w.ProjectID.Hex(),
+			ProjectRef: i.ProjectRef,
+			Summary:    i.Summary,
+			StartedAt:  w.StartedAt,
+			Duration:   w.Duration,
+		})
+	}
+
+	return results, nil
+}
+
+// getIssueTimes returns the estimates and time spent of the issues of a project that match a query.
+func (s *Storage) getIssueTimes(projectID primitive.ObjectID, query bson.M) ([]reporting.IssueTime, error) {
+	issues, _, err := s.repo.GetProjectIssues(&projectID, nil, 0, query, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []reporting.IssueTime{}
+	for _, i := range *issues {
+		results = append(results, reporting.IssueTime{
+			ID:                i.ID.Hex(),
+			OriginalEstimate:  i.OriginalEstimate,
+			RemainingEstimate: i.RemainingEstimate,
+			TimeSpent:         i.TimeSpent,
+		})
+	}
+
+	return results, nil
+}
+
+// getIssueHistories loads the changes of a set of issues and returns them in their reporting form.
+func (s *Storage) getIssueHistories(issues []Issue) ([]reporting.IssueHistory, error) {
+	results := []reporting.IssueHistory{}
+	if len(issues) == 0 {
+		return results, nil
+	}
+
+	issueIDs := []primitive.ObjectID{}
+	for _, i := range issues {
+		issueIDs = append(issueIDs, i.ID)
+	}
+
+	changes, err := s.repo.GetIssueChanges(bson.M{"issueId": bson.M{"$in": issueIDs}})
+	if err != nil {
+		return nil, err
+	}
+
+	changesByIssue := make(map[primitive.ObjectID][]reporting.IssueChange)
+	for _, c := range *changes {
+		changesByIssue[c.IssueID] = append(changesByIssue[c.IssueID], reporting.IssueChange{
+			Field:     c.Field,
+			From:      transformIssueChangeValue(c.From),
+			To:        transformIssueChangeValue(c.To),
+			CreatedAt: c.CreatedAt,
+		})
+	}
+
+	for _, i := range issues {
+		results = append(results, reporting.IssueHistory{
+			ID:         i.ID.Hex(),
+			ProjectRef: i.ProjectRef,
+			Type:       i.Type,
+			AssigneeID: getHexFromObjectID(i.AssigneeID),
+			Labels:     i.Labels,
+			CreatedAt:  i.CreatedAt,
+			State: reporting.IssueState{
+				Status:            i.Status,
+				SprintID:          getHexFromObjectID(i.SprintID),
+				Points:            int64(i.Points),
+				OriginalEstimate:  i.OriginalEstimate,
+				RemainingEstimate: i.RemainingEstimate,
+			},
+			Changes: changesByIssue[i.ID],
+		})
+	}
+
+	return results, nil
+}
+
+// transformIssueChangeValue converts a recorded issue change value to its reporting form.
+func transformIssueChangeValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case primitive.ObjectID:
+		return value.Hex()
+	case int32:
+		return int64(value)
+	}
+	return v
+}
+
+// transformReportingSprint converts a sprint of a board to its reporting form.
+func transformReportingSprint(b *Board, s *Sprint) reporting.Sprint {
+	return reporting.Sprint{
+		ID:          s.ID.Hex(),
+		BoardID:     b.ID.Hex(),
+		Name:        s.Name,
+		State:       s.state(),
+		StartAt:     s.StartAt,
+		EndAt:       s.EndAt,
+		StartedAt:   s.StartedAt,
+		CompletedAt: s.CompletedAt,
+	}
+}
+
+// transformReportingBoardColumns converts the columns of a board to their reporting form.
+func transformReportingBoardColumns(columns []BoardColumn) []reporting.BoardColumn {
+	results := []reporting.BoardColumn{}
+	for _, c := range columns {
+		results = append(results, reporting.BoardColumn{
+			Name:          c.Name,
+			IssueStatuses: c.IssueStatuses,
+			Ordinal:       c.Ordinal,
+		})
+	}
+	return results
+}
@@ -0,0 +1,491 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/njehyde/issue-tracker/libraries/slog"
+	"github.com/njehyde/issue-tracker/pkg/adding"
+	"github.com/njehyde/issue-tracker/pkg/listing"
+	"github.com/njehyde/issue-tracker/pkg/updating"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// IssueSchedule defines the storage form of a project issue schedule entity. Schedules without a next run are done, or
+// disabled.
+type IssueSchedule struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	ProjectID primitive.ObjectID `bson:"projectId"`
+	Name      string             `bson:"name"`
+	Rule      string             `bson:"rule"`
+	Timezone  string             `bson:"timezone"`
+	StartAt   time.Time          `bson:"startAt"`
+	CatchUp   string             `bson:"catchUp"`
+	IsEnabled bool               `bson:"isEnabled"`
+	Issue     ScheduledIssue     `bson:"issue"`
+	NextRunAt *time.Time         `bson:"nextRunAt,omitempty"`
+	LastRunAt *time.Time         `bson:"lastRunAt,omitempty"`
+	LastError string             `bson:"lastError,omitempty"`
+	CreatedBy primitive.ObjectID `bson:"createdBy"`
+	CreatedAt time.Time          `bson:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt"`
+}
+
+// ScheduledIssue defines the storage form of the issue template of an issue schedule.
+type ScheduledIssue struct {
+	Type             string                 `bson:"type"`
+	Summary          string                 `bson:"summary"`
+	Description      string                 `bson:"description,omitempty"`
+	Status           string                 `bson:"status"`
+	Priority         string                 `bson:"priority"`
+	Points           int32                  `bson:"points,omitempty"`
+	OriginalEstimate int64                  `bson:"originalEstimate,omitempty"`
+	DueIn            int64                  `bson:"dueIn,omitempty"`
+	AssigneeID       primitive.ObjectID     `bson:"assigneeId,omitempty"`
+	EpicID           primitive.ObjectID     `bson:"epicId,omitempty"`
+	Labels           []string               `bson:"labels,omitempty"`
+	Components       []primitive.ObjectID   `bson:"components,omitempty"`
+	CustomFields     map[string]interface{} `bson:"customFields,omitempty"`
+}
+
+// AddIssueSchedule ...
+func (r *Repository) AddIssueSchedule(is *IssueSchedule) error {
+	collection := r.db.Collection("issue_schedules")
+
+	now := time.Now()
+
+	is.ID = primitive.NewObjectID()
+	is.CreatedAt = now
+	is.UpdatedAt = now
+
+	insertResult, err := collection.InsertOne(context.Background(), is)
+	if err != nil {
+		return err
+	}
+
+	slog.Infof("Added issue schedule %v: %+v", is.ID.Hex(), insertResult)
+
+	return nil
+}
+
+// DeleteIssueSchedule ...
+func (r *Repository) DeleteIssueSchedule(projectID *primitive.ObjectID, scheduleID *primitive.ObjectID) error {
+	collection := r.db.Collection("issue_schedules")
+
+	filter := bson.M{"_id": scheduleID, "projectId": projectID}
+
+	deleteResult, err := collection.DeleteOne(context.Background(), filter)
+	if err != nil {
+		return err
+	}
+
+	if deleteResult.DeletedCount == 0 {
+		return fmt.Errorf("Issue schedule %v not found for project %v", scheduleID.Hex(), projectID.Hex())
+	}
+
+	slog.Infof("Deleted issue schedule %v of project %v: %+v", scheduleID.Hex(), projectID.Hex(), deleteResult)
+
+	return nil
+}
+
+// GetIssueSchedule ...
+func (r *Repository) GetIssueSchedule(scheduleID *primitive.ObjectID) (*IssueSchedule, error) {
+	var is IssueSchedule
+
+	collection := r.db.Collection("issue_schedules")
+
+	err := collection.FindOne(context.Background(), bson.M{"_id": scheduleID}).Decode(&is)
+	if err == mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("Issue schedule %v not found", scheduleID.Hex())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &is, nil
+}
+
+// GetIssueSchedules ...
+func (r *Repository) GetIssueSchedules(filter bson.M) (*[]IssueSchedule, error) {
+	var schedules []IssueSchedule
+
+	collection := r.db.Collection("issue_schedules")
+
+	findOptions := options.Find().SetSort(
+		bson.D{
+			primitive.E{Key: "_id", Value: 1},
+		},
+	)
+
+	cur, err := collection.Find(context.Background(), filter, findOptions)
+	if err != nil {
+		return &schedules, err
+	}
+	defer cur.Close(context.Background())
+
+	for cur.Next(context.Background()) {
+		var is IssueSchedule
+
+		err = cur.Decode(&is)
+		if err != nil {
+			return &schedules, err
+		}
+
+		schedules = append(schedules, is)
+	}
+
+	return &schedules, nil
+}
+
+// UpdateIssueSchedule ...
+func (r *Repository) UpdateIssueSchedule(projectID *primitive.ObjectID, scheduleID *primitive.ObjectID, update primitive.M) error {
+	collection := r.db.Collection("issue_schedules")
+
+	filter := bson.M{"_id": scheduleID, "projectId": projectID}
+
+	updateResult, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return fmt.Errorf("Issue schedule %v not found for project %v", scheduleID.Hex(), projectID.Hex())
+	}
+
+	slog.Infof("Updated issue schedule %v for project %v: %+v", scheduleID.Hex(), projectID.Hex(), updateResult)
+
+	return nil
+}
+
+// AddIssueSchedule adds a project issue schedule entity to the database's "issue_schedules" collection.
+func (s *Storage) AddIssueSchedule(projectID *string, userID *string, is *adding.IssueSchedule, nextRunAt *time.Time) error {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return err
+	}
+
+	userIDAsObjectID, err := primitive.ObjectIDFromHex(*userID)
+	if err != nil {
+		return err
+	}
+
+	scheduledIssue, err := s.getScheduledIssue(projectIDAsObjectID, &is.Issue)
+	if err != nil {
+		return err
+	}
+
+	startAt := time.Now()
+	if is.StartAt != nil {
+		startAt = *is.StartAt
+	}
+
+	newSchedule := IssueSchedule{
+		ProjectID: projectIDAsObjectID,
+		Name:      is.Name,
+		Rule:      is.Rule,
+		Timezone:  is.Timezone,
+		StartAt:   startAt,
+		CatchUp:   is.CatchUp,
+		IsEnabled: true,
+		Issue:     *scheduledIssue,
+		NextRunAt: nextRunAt,
+		CreatedBy: userIDAsObjectID,
+	}
+
+	return s.repo.AddIssueSchedule(&newSchedule)
+}
+
+// AddScheduledIssue adds the issue of an issue schedule run to the database's "issues" collection, as reported by the
+// user who created the schedule. Runs that already created an issue are skipped, so that a run is never repeated.
+func (s *Storage) AddScheduledIssue(scheduleID *string, runAt time.Time) (bool, error) {
+	scheduleIDAsObjectID, err := primitive.ObjectIDFromHex(*scheduleID)
+	if err != nil {
+		return false, err
+	}
+
+	is, err := s.repo.GetIssueSchedule(&scheduleIDAsObjectID)
+	if err != nil {
+		return false, err
+	}
+
+	query := bson.M{"scheduleId": is.ID, "scheduledAt": runAt}
+	count, err := s.repo.CountProjectIssues(&is.ProjectID, query)
+	if err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return false, nil
+	}
+
+	var labels []adding.Label
+	for _, l := range is.Issue.Labels {
+		labels = append(labels, adding.Label{IsNew: false, Value: l})
+	}
+
+	newIssue := adding.Issue{
+		ProjectID:        is.ProjectID.Hex(),
+		Type:             is.Issue.Type,
+		Summary:          is.Issue.Summary,
+		Description:      is.Issue.Description,
+		Status:           is.Issue.Status,
+		Priority:         is.Issue.Priority,
+		Points:           is.Issue.Points,
+		OriginalEstimate: is.Issue.OriginalEstimate,
+		ReporterID:       is.CreatedBy.Hex(),
+		AssigneeID:       getHexFromObjectID(is.Issue.AssigneeID),
+		EpicID:           getHexFromObjectID(is.Issue.EpicID),
+		Labels:           labels,
+		Components:       transformObjectIDs(is.Issue.Components),
+		CustomFields:     getCustomFieldFormValues(is.Issue.CustomFields),
+	}
+
+	if is.Issue.DueIn > 0 {
+		dueDate := runAt.Add(time.Duration(is.Issue.DueIn) * time.Second)
+		newIssue.DueDate = &dueDate
+	}
+
+	err = s.addIssue(&newIssue, func(i *Issue) {
+		i.ScheduleID = is.ID
+		i.ScheduledAt = &runAt
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// DeleteIssueSchedule deletes a project issue schedule entity from the database's "issue_schedules" collection. The
+// issues it created are left as they are.
+func (s *Storage) DeleteIssueSchedule(projectID *string, scheduleID *string) error {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return err
+	}
+
+	scheduleIDAsObjectID, err := primitive.ObjectIDFromHex(*scheduleID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.DeleteIssueSchedule(&projectIDAsObjectID, &scheduleIDAsObjectID)
+}
+
+// GetDueIssueSchedules returns the enabled issue schedule entities with a next run at or before a time from the
+// database's "issue_schedules" collection.
+func (s *Storage) GetDueIssueSchedules(now time.Time) (results []adding.DueIssueSchedule, err error) {
+	filter := bson.M{"isEnabled": true, "nextRunAt": bson.M{"$lte": now}}
+
+	schedules, err := s.repo.GetIssueSchedules(filter)
+	if err != nil {
+		return results, err
+	}
+
+	for _, is := range *schedules {
+		results = append(results, adding.DueIssueSchedule{
+			ID:        is.ID.Hex(),
+			ProjectID: is.ProjectID.Hex(),
+			UserID:    is.CreatedBy.Hex(),
+			Rule:      is.Rule,
+			Timezone:  is.Timezone,
+			StartAt:   is.StartAt,
+			CatchUp:   is.CatchUp,
+			NextRunAt: *is.NextRunAt,
+		})
+	}
+
+	return results, nil
+}
+
+// GetProjectIssueSchedules returns the issue schedule entities of a project from the database's "issue_schedules"
+// collection.
+func (s *Storage) GetProjectIssueSchedules(projectID *string) (results []listing.IssueSchedule, err error) {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return results, err
+	}
+
+	schedules, err := s.repo.GetIssueSchedules(bson.M{"projectId": projectIDAsObjectID})
+	if err != nil {
+		return results, err
+	}
+
+	for _, is := range *schedules {
+		results = append(results, listing.IssueSchedule{
+			ID:        is.ID.Hex(),
+			ProjectID: is.ProjectID.Hex(),
+			Name:      is.Name,
+			Rule:      is.Rule,
+			Timezone:  is.Timezone,
+			StartAt:   is.StartAt,
+			CatchUp:   is.CatchUp,
+			IsEnabled: is.IsEnabled,
+			Issue: listing.ScheduledIssue{
+				Type:             is.Issue.Type,
+				Summary:          is.Issue.Summary,
+				Description:      is.Issue.Description,
+				Status:           is.Issue.Status,
+				Priority:         is.Issue.Priority,
+				Points:           is.Issue.Points,
+				OriginalEstimate: is.Issue.OriginalEstimate,
+				DueIn:            is.Issue.DueIn,
+				AssigneeID:       getHexFromObjectID(is.Issue.AssigneeID),
+				EpicID:           getHexFromObjectID(is.Issue.EpicID),
+				Labels:           is.Issue.Labels,
+				Components:       transformObjectIDs(is.Issue.Components),
+				CustomFields:     transformCustomFieldValues(is.Issue.CustomFields),
+			},
+			NextRunAt: is.NextRunAt,
+			LastRunAt: is.LastRunAt,
+			LastError: is.LastError,
+			CreatedBy: is.CreatedBy.Hex(),
+			CreatedAt: is.CreatedAt,
+			UpdatedAt: is.UpdatedAt,
+		})
+	}
+
+	return results, nil
+}
+
+// UpdateIssueSchedule updates a project issue schedule entity in the database's "issue_schedules" collection.
+func (s *Storage) UpdateIssueSchedule(projectID *string, scheduleID *string, is *updating.IssueSchedule, nextRunAt *time.Time) error {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return err
+	}
+
+	scheduleIDAsObjectID, err := primitive.ObjectIDFromHex(*scheduleID)
+	if err != nil {
+		return err
+	}
+
+	si := adding.ScheduledIssue(is.Issue)
+	scheduledIssue, err := s.getScheduledIssue(projectIDAsObjectID, &si)
+	if err != nil {
+		return err
+	}
+
+	set := bson.M{
+		"name":      is.Name,
+		"rule":      is.Rule,
+		"timezone":  is.Timezone,
+		"startAt":   is.StartAt,
+		"catchUp":   is.CatchUp,
+		"isEnabled": is.IsEnabled,
+		"issue":     scheduledIssue,
+		"updatedAt": time.Now(),
+	}
+
+	update := bson.M{"$set": set}
+	if nextRunAt != nil {
+		set["nextRunAt"] = nextRunAt
+	} else {
+		update["$unset"] = bson.M{"nextRunAt": ""}
+	}
+
+	return s.repo.UpdateIssueSchedule(&projectIDAsObjectID, &scheduleIDAsObjectID, update)
+}
+
+// UpdateIssueScheduleRun updates when an issue schedule entity last ran, when it next runs, and the error of its last
+// run, in the database's "issue_schedules" collection.
+func (s *Storage) UpdateIssueScheduleRun(scheduleID *string, lastRunAt time.Time, nextRunAt *time.Time, lastError string) error {
+	scheduleIDAsObjectID, err := primitive.ObjectIDFromHex(*scheduleID)
+	if err != nil {
+		return err
+	}
+
+	is, err := s.repo.GetIssueSchedule(&scheduleIDAsObjectID)
+	if err != nil {
+		return err
+	}
+
+	set := bson.M{"lastRunAt": lastRunAt}
+	unset := bson.M{}
+
+	if nextRunAt != nil {
+		set["nextRunAt"] = nextRunAt
+	} else {
+		unset["nextRunAt"] = ""
+	}
+
+	if len(lastError) > 0 {
+		set["lastError"] = lastError
+	} else {
+		unset["lastError"] = ""
+	}
+
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	return s.repo.UpdateIssueSchedule(&is.ProjectID, &scheduleIDAsObjectID, update)
+}
+
+// getScheduledIssue checks the issue template of an issue schedule against its project, as an issue would be checked
+// when it is added, and returns its storage form.
+func (s *Storage) getScheduledIssue(projectID primitive.ObjectID, si *adding.ScheduledIssue) (*ScheduledIssue, error) {
+	p, err := s.repo.GetProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.checkIssueType(si.Type, p.IssueTypes); err != nil {
+		return nil, err
+	}
+
+	customFields, err := s.getIssueCustomFieldValues(projectID, si.Type, si.CustomFields)
+	if err != nil {
+		return nil, err
+	}
+
+	components, err := getProjectComponentIDs(p, si.Components)
+	if err != nil {
+		return nil, err
+	}
+
+	result := ScheduledIssue{
+		Type:             si.Type,
+		Summary:          si.Summary,
+		Description:      si.Description,
+		Status:           si.Status,
+		Priority:         si.Priority,
+		Points:           si.Points,
+		OriginalEstimate: si.OriginalEstimate,
+		DueIn:            si.DueIn,
+		Labels:           si.Labels,
+		Components:       components,
+	}
+
+	if len(customFields) > 0 {
+		result.CustomFields = customFields
+	}
+
+	if len(si.EpicID) > 0 {
+		if si.Type == issueTypeEpic {
+			return nil, fmt.Errorf("Epics cannot belong to an epic")
+		}
+		if result.EpicID, err = s.getProjectEpicID(projectID, si.EpicID); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(si.AssigneeID) > 0 {
+		if result.AssigneeID, err = primitive.ObjectIDFromHex(si.AssigneeID); err != nil {
+			return nil, err
+		}
+		if _, err = s.repo.GetUserByID(&result.AssigneeID); err != nil {
+			return nil, fmt.Errorf("User %v not found", si.AssigneeID)
+		}
+	}
+
+	if err = s.addMissingLabels(si.Labels); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,660 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/njehyde/issue-tracker/libraries/slog"
+	"github.com/njehyde/issue-tracker/pkg/adding"
+	"github.com/njehyde/issue-tracker/pkg/listing"
+	"github.com/njehyde/issue-tracker/pkg/updating"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SLAPolicy defines the storage form of a project SLA policy entity. A policy's clock starts when an issue first enters
+// a status of one of its start categories, is paused while the issue is in a status of one of its pause categories,
+// and stops for good when the issue enters a status of one of its stop categories.
+type SLAPolicy struct {
+	ID              primitive.ObjectID `bson:"_id"`
+	Name            string             `bson:"name"`
+	StartCategories []string           `bson:"startCategories"`
+	PauseCategories []string           `bson:"pauseCategories"`
+	StopCategories  []string           `bson:"stopCategories"`
+	Targets         []SLATarget        `bson:"targets"`
+	CreatedAt       time.Time          `bson:"createdAt"`
+	UpdatedAt       time.Time          `bson:"updatedAt"`
+}
+
+// SLATarget defines the storage form of the time an SLA policy allows issues of a priority, in seconds.
+type SLATarget struct {
+	Priority string `bson:"priority"`
+	Duration int64  `bson:"duration"`
+}
+
+// target returns the time the policy allows an issue of a priority, and whether the policy applies to it at all.
+func (p *SLAPolicy) target(priority string) (time.Duration, bool) {
+	for _, t := range p.Targets {
+		if t.Priority == priority {
+			return time.Duration(t.Duration) * time.Second, true
+		}
+	}
+
+	return 0, false
+}
+
+// SLA clock states.
+const (
+	slaNotStarted = "NOT_STARTED"
+	slaRunning    = "RUNNING"
+	slaPaused     = "PAUSED"
+	slaStopped    = "STOPPED"
+)
+
+// slaBreachLookback is how long after an issue is last updated that a finished issue is still checked for breaches,
+// so that a clock which ran out just before it stopped is still caught.
+const slaBreachLookback = 24 * time.Hour
+
+// slaClock is the state of an SLA policy's clock for an issue.
+type slaClock struct {
+	policy   *SLAPolicy
+	state    string
+	target   time.Duration
+	elapsed  time.Duration
+	breachAt *time.Time
+}
+
+// breached returns whether the clock has used up its target.
+func (c *slaClock) breached() bool {
+	return c.state != slaNotStarted && c.elapsed >= c.target
+}
+
+// statusPeriod is a status an issue entered, and when it entered it.
+type statusPeriod struct {
+	status string
+	from   time.Time
+}
+
+// AddProjectSLAPolicy ...
+func (r *Repository) AddProjectSLAPolicy(projectID primitive.ObjectID, p *SLAPolicy) error {
+	collection := r.db.Collection("projects")
+
+	now := time.Now()
+
+	p.ID = primitive.NewObjectID()
+	p.CreatedAt = now
+	p.UpdatedAt = now
+
+	filter := bson.M{"_id": projectID}
+
+	update := bson.M{
+		"$set": bson.M{
+			"updatedAt": now,
+		},
+		"$push": bson.M{
+			"slaPolicies": p,
+		},
+	}
+
+	updateResult, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return fmt.Errorf("Project %v not found", projectID.Hex())
+	}
+
+	slog.Infof("Added SLA policy %v via update to project %v: %+v", p.ID.Hex(), projectID.Hex(), updateResult)
+
+	return nil
+}
+
+// DeleteProjectSLAPolicy ...
+func (r *Repository) DeleteProjectSLAPolicy(projectID primitive.ObjectID, policyID primitive.ObjectID) error {
+	collection := r.db.Collection("projects")
+
+	filter := bson.M{"_id": projectID, "slaPolicies._id": policyID}
+
+	update := bson.M{
+		"$set": bson.M{
+			"updatedAt": time.Now(),
+		},
+		"$pull": bson.M{
+			"slaPolicies": bson.M{
+				"_id": policyID,
+			},
+		},
+	}
+
+	updateResult, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return fmt.Errorf("SLA policy %v not found for project %v", policyID.Hex(), projectID.Hex())
+	}
+
+	slog.Infof("Deleted SLA policy %v via update to project %v: %+v", policyID.Hex(), projectID.Hex(), updateResult)
+
+	return nil
+}
+
+// GetSLAProjects ...
+func (r *Repository) GetSLAProjects() (*[]Project, error) {
+	var projects []Project
+
+	collection := r.db.Collection("projects")
+
+	filter := bson.M{"slaPolicies.0": bson.M{"$exists": true}}
+
+	cur, err := collection.Find(context.Background(), filter, options.Find())
+	if err != nil {
+		return &projects, err
+	}
+	defer cur.Close(context.Background())
+
+	for cur.Next(context.Background()) {
+		var p Project
+
+		err = cur.Decode(&p)
+		if err != nil {
+			return &projects, err
+		}
+
+		projects = append(projects, p)
+	}
+
+	return &projects, nil
+}
+
+// UpdateProjectSLAPolicy ...
+func (r *Repository) UpdateProjectSLAPolicy(projectID primitive.ObjectID, policyID primitive.ObjectID, set primitive.M) error {
+	collection := r.db.Collection("projects")
+
+	now := time.Now()
+
+	filter := bson.M{"_id": projectID, "slaPolicies._id": policyID}
+
+	update := bson.M{"$set": bson.M{"updatedAt": now, "slaPolicies.$.updatedAt": now}}
+	for k, v := range set {
+		update["$set"].(bson.M)["slaPolicies.$."+k] = v
+	}
+
+	updateResult, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return fmt.Errorf("SLA policy %v not found for project %v", policyID.Hex(), projectID.Hex())
+	}
+
+	slog.Infof("Updated SLA policy %v via update to project %v: %+v", policyID.Hex(), projectID.Hex(), updateResult)
+
+	return nil
+}
+
+// AddProjectSLAPolicy adds an SLA policy child entity to a project in the database's "projects" collection.
+func (s *Storage) AddProjectSLAPolicy(projectID *string, p *adding.SLAPolicy) error {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return err
+	}
+
+	targets := []SLATarget{}
+	priorities := []string{}
+	for _, t := range p.Targets {
+		targets = append(targets, SLATarget{Priority: t.Priority, Duration: t.Duration})
+		priorities = append(priorities, t.Priority)
+	}
+
+	categories := append(append(append([]string{}, p.StartCategories...), p.PauseCategories...), p.StopCategories...)
+	err = s.checkSLAPolicy(categories, priorities)
+	if err != nil {
+		return err
+	}
+
+	policy := SLAPolicy{
+		Name:            p.Name,
+		StartCategories: p.StartCategories,
+		PauseCategories: p.PauseCategories,
+		StopCategories:  p.StopCategories,
+		Targets:         targets,
+	}
+
+	return s.repo.AddProjectSLAPolicy(projectIDAsObjectID, &policy)
+}
+
+// DeleteProjectSLAPolicy deletes an SLA policy child entity of a project in the database's "projects" collection, and
+// forgets the breaches recorded against it.
+func (s *Storage) DeleteProjectSLAPolicy(projectID *string, policyID *string) error {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return err
+	}
+
+	policyIDAsObjectID, err := primitive.ObjectIDFromHex(*policyID)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.DeleteProjectSLAPolicy(projectIDAsObjectID, policyIDAsObjectID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.UpdateManyIssues(
+		bson.M{"projectId": projectIDAsObjectID, "slaBreaches": policyIDAsObjectID},
+		bson.M{"$pull": bson.M{"slaBreaches": policyIDAsObjectID}},
+	)
+}
+
+// GetProjectSLAPolicies returns the SLA policy entities of a project from the repository.
+func (s *Storage) GetProjectSLAPolicies(projectID *string) (results []listing.SLAPolicy, err error) {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return results, err
+	}
+
+	p, err := s.repo.GetProject(projectIDAsObjectID)
+	if err != nil {
+		return results, err
+	}
+
+	results = []listing.SLAPolicy{}
+	for _, sp := range p.SLAPolicies {
+		targets := []listing.SLATarget{}
+		for _, t := range sp.Targets {
+			targets = append(targets, listing.SLATarget{Priority: t.Priority, Duration: t.Duration})
+		}
+		results = append(results, listing.SLAPolicy{
+			ID:              sp.ID.Hex(),
+			Name:            sp.Name,
+			StartCategories: sp.StartCategories,
+			PauseCategories: sp.PauseCategories,
+			StopCategories:  sp.StopCategories,
+			Targets:         targets,
+			CreatedAt:       sp.CreatedAt,
+			UpdatedAt:       sp.UpdatedAt,
+		})
+	}
+
+	return results, nil
+}
+
+// UpdateProjectSLAPolicy updates an SLA policy child entity of a project in the database's "projects" collection.
+func (s *Storage) UpdateProjectSLAPolicy(projectID *string, policyID *string, p *updating.SLAPolicy) error {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return err
+	}
+
+	policyIDAsObjectID, err := primitive.ObjectIDFromHex(*policyID)
+	if err != nil {
+		return err
+	}
+
+	targets := []SLATarget{}
+	priorities := []string{}
+	for _, t := range p.Targets {
+		targets = append(targets, SLATarget{Priority: t.Priority, Duration: t.Duration})
+		priorities = append(priorities, t.Priority)
+	}
+
+	categories := append(append(append([]string{}, p.StartCategories...), p.PauseCategories...), p.StopCategories...)
+	err = s.checkSLAPolicy(categories, priorities)
+	if err != nil {
+		return err
+	}
+
+	set := bson.M{
+		"name":            p.Name,
+		"startCategories": p.StartCategories,
+		"pauseCategories": p.PauseCategories,
+		"stopCategories":  p.StopCategories,
+		"targets":         targets,
+	}
+
+	return s.repo.UpdateProjectSLAPolicy(projectIDAsObjectID, policyIDAsObjectID, set)
+}
+
+// AddIssueBreach records that an issue has breached an SLA policy, or passed its due date, in the database's "issues"
+// collection, so that it is only reported once, and notifies the issue's assignee.
+func (s *Storage) AddIssueBreach(b *updating.IssueBreach) error {
+	issueIDAsObjectID, err := primitive.ObjectIDFromHex(b.IssueID)
+	if err != nil {
+		return err
+	}
+
+	issue, err := s.repo.GetIssue(issueIDAsObjectID)
+	if err != nil {
+		return err
+	}
+
+	project, err := s.repo.GetProject(issue.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	var update bson.M
+	var notificationType, message string
+
+	if len(b.PolicyID) == 0 {
+		update = bson.M{"$set": bson.M{"isOverdue": true}}
+		notificationType = notificationOverdue
+		message = fmt.Sprintf("%v passed its due date", issue.ProjectRef)
+	} else {
+		policyIDAsObjectID, err := primitive.ObjectIDFromHex(b.PolicyID)
+		if err != nil {
+			return err
+		}
+		update = bson.M{"$addToSet": bson.M{"slaBreaches": policyIDAsObjectID}}
+		notificationType = notificationSLABreached
+		message = fmt.Sprintf("%v breached the SLA %v", issue.ProjectRef, b.Name)
+	}
+
+	err = s.repo.UpdateIssue(issue.ID, update)
+	if err != nil {
+		return err
+	}
+
+	return s.notifyIssueUsers(issue, project, notificationType, message)
+}
+
+// GetIssueBreaches returns the issues that have breached an SLA policy, or passed their due date, and have not been
+// reported yet.
+func (s *Storage) GetIssueBreaches(now time.Time) (results []updating.IssueBreach, err error) {
+	done, err := s.getDoneStatuses()
+	if err != nil {
+		return results, err
+	}
+
+	doneStatuses := []string{}
+	for status := range done {
+		doneStatuses = append(doneStatuses, status)
+	}
+
+	projects, err := s.repo.GetSLAProjects()
+	if err != nil {
+		return results, err
+	}
+
+	for _, p := range *projects {
+		filter := bson.M{
+			"projectId": p.ID,
+			"$or": []bson.M{
+				{"status": bson.M{"$nin": doneStatuses}},
+				{"updatedAt": bson.M{"$gte": now.Add(-slaBreachLookback)}},
+			},
+		}
+
+		issues, _, err := s.repo.GetIssues(nil, 0, filter, nil)
+		if err != nil {
+			return results, err
+		}
+
+		clocks, err := s.getIssueSLAClocks(*issues, now)
+		if err != nil {
+			return results, err
+		}
+
+		for _, i := range *issues {
+			for _, c := range clocks[i.ID] {
+				if !c.breached() || containsObjectID(i.SLABreaches, c.policy.ID) {
+					continue
+				}
+				results = append(results, updating.IssueBreach{
+					ProjectID:  i.ProjectID.Hex(),
+					IssueID:    i.ID.Hex(),
+					PolicyID:   c.policy.ID.Hex(),
+					Name:       c.policy.Name,
+					BreachedAt: *c.breachAt,
+				})
+			}
+		}
+	}
+
+	filter := bson.M{
+		"dueDate":   bson.M{"$lte": now},
+		"isOverdue": bson.M{"$ne": true},
+		"status":    bson.M{"$nin": doneStatuses},
+	}
+
+	overdue, _, err := s.repo.GetIssues(nil, 0, filter, nil)
+	if err != nil {
+		return results, err
+	}
+
+	for _, i := range *overdue {
+		results = append(results, updating.IssueBreach{
+			ProjectID:  i.ProjectID.Hex(),
+			IssueID:    i.ID.Hex(),
+			Name:       "Due date",
+			BreachedAt: *i.DueDate,
+		})
+	}
+
+	return results, nil
+}
+
+// checkSLAPolicy returns an error where an SLA policy references a category or priority type that does not exist.
+func (s *Storage) checkSLAPolicy(categories []string, priorities []string) error {
+	cs, err := s.repo.GetCategories()
+	if err != nil {
+		return err
+	}
+
+	knownCategories := make(map[string]bool)
+	for _, c := range *cs {
+		knownCategories[c.ID] = true
+	}
+	for _, c := range categories {
+		if !knownCategories[c] {
+			return fmt.Errorf("Category %v not found", c)
+		}
+	}
+
+	pts, err := s.repo.GetPriorityTypes(1)
+	if err != nil {
+		return err
+	}
+
+	knownPriorities := make(map[string]bool)
+	for _, pt := range pts {
+		knownPriorities[pt.ID] = true
+	}
+	for _, p := range priorities {
+		if !knownPriorities[p] {
+			return fmt.Errorf("Priority type %v not found", p)
+		}
+	}
+
+	return nil
+}
+
+// getIssueSLAClocks returns the clocks of the SLA policies that apply to each issue, keyed by issue id.
+func (s *Storage) getIssueSLAClocks(issues []Issue, now time.Time) (map[primitive.ObjectID][]slaClock, error) {
+	results := make(map[primitive.ObjectID][]slaClock)
+
+	projects := make(map[primitive.ObjectID]*Project)
+	ids := []primitive.ObjectID{}
+	for _, i := range issues {
+		if _, ok := projects[i.ProjectID]; !ok {
+			p, err := s.repo.GetProject(i.ProjectID)
+			if err != nil {
+				return results, err
+			}
+			projects[i.ProjectID] = p
+		}
+		if len(projects[i.ProjectID].SLAPolicies) > 0 {
+			ids = append(ids, i.ID)
+		}
+	}
+
+	if len(ids) == 0 {
+		return results, nil
+	}
+
+	categories, err := s.GetIssueStatusCategories()
+	if err != nil {
+		return results, err
+	}
+
+	periods, err := s.getIssueStatusPeriods(issues, ids)
+	if err != nil {
+		return results, err
+	}
+
+	for _, i := range issues {
+		project := projects[i.ProjectID]
+		for n := range project.SLAPolicies {
+			policy := &project.SLAPolicies[n]
+			target, ok := policy.target(i.Priority)
+			if !ok {
+				continue
+			}
+			results[i.ID] = append(results[i.ID], getSLAClock(policy, target, periods[i.ID], categories, now))
+		}
+	}
+
+	return results, nil
+}
+
+// getIssueStatusPeriods returns the statuses each issue has been in, in order, from its recorded status changes.
+func (s *Storage) getIssueStatusPeriods(issues []Issue, ids []primitive.ObjectID) (map[primitive.ObjectID][]statusPeriod, error) {
+	results := make(map[primitive.ObjectID][]statusPeriod)
+
+	changes, err := s.repo.GetIssueChanges(bson.M{"issueId": bson.M{"$in": ids}, "field": "status"})
+	if err != nil {
+		return results, err
+	}
+
+	issueChanges := make(map[primitive.ObjectID][]IssueChange)
+	for _, c := range *changes {
+		issueChanges[c.IssueID] = append(issueChanges[c.IssueID], c)
+	}
+
+	for _, i := range issues {
+		cs := issueChanges[i.ID]
+
+		// Issues start in the status their first change moved them from
+		status := i.Status
+		if len(cs) > 0 {
+			status, _ = cs[0].From.(string)
+		}
+
+		results[i.ID] = []statusPeriod{{status: status, from: i.CreatedAt}}
+		for _, c := range cs {
+			to, _ := c.To.(string)
+			results[i.ID] = append(results[i.ID], statusPeriod{status: to, from: c.CreatedAt})
+		}
+	}
+
+	return results, nil
+}
+
+// setIssueSLAs sets the SLA clocks of listed issues, which are in the same order as the issues they were transformed
+// from.
+func (s *Storage) setIssueSLAs(results []listing.Issue, issues []Issue) error {
+	now := time.Now()
+
+	clocks, err := s.getIssueSLAClocks(issues, now)
+	if err != nil {
+		return err
+	}
+
+	for n, i := range issues {
+		for _, c := range clocks[i.ID] {
+			results[n].SLAs = append(results[n].SLAs, transformIssueSLA(&c))
+		}
+	}
+
+	return nil
+}
+
+// getSLAClock replays the statuses an issue has been in against an SLA policy, to find how much of the policy's target
+// the issue has used.
+func getSLAClock(p *SLAPolicy, target time.Duration, periods []statusPeriod, categories map[string]string, now time.Time) slaClock {
+	clock := slaClock{policy: p, state: slaNotStarted, target: target}
+
+	for n, sp := range periods {
+		end := now
+		if n+1 < len(periods) {
+			end = periods[n+1].from
+		}
+
+		category := categories[sp.status]
+		if clock.state == slaNotStarted && !containsString(p.StartCategories, category) {
+			continue
+		}
+		if containsString(p.StopCategories, category) {
+			clock.state = slaStopped
+			break
+		}
+		if containsString(p.PauseCategories, category) {
+			clock.state = slaPaused
+			continue
+		}
+
+		clock.state = slaRunning
+		clock.elapsed += end.Sub(sp.from)
+		if clock.breachAt == nil && clock.elapsed >= target {
+			breachAt := end.Add(target - clock.elapsed)
+			clock.breachAt = &breachAt
+		}
+	}
+
+	// A running clock breaches once the rest of its target has passed
+	if clock.state == slaRunning && clock.breachAt == nil {
+		breachAt := now.Add(target - clock.elapsed)
+		clock.breachAt = &breachAt
+	}
+
+	return clock
+}
+
+func transformIssueSLA(c *slaClock) listing.IssueSLA {
+	return listing.IssueSLA{
+		PolicyID:  c.policy.ID.Hex(),
+		Name:      c.policy.Name,
+		State:     c.state,
+		Target:    int64(c.target / time.Second),
+		Elapsed:   int64(c.elapsed / time.Second),
+		Remaining: int64((c.target - c.elapsed) / time.Second),
+		Breached:  c.breached(),
+		BreachAt:  c.breachAt,
+	}
+}
+
+// sameDueDate returns whether two optional due dates are the same.
+func sameDueDate(a *time.Time, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Equal(*b)
+}
+
+func containsObjectID(ids []primitive.ObjectID, id primitive.ObjectID) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsString(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+
+	return false
+}
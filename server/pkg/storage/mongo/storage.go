@@ -2,10 +2,14 @@ package mongo
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/njehyde/issue-tracker/libraries/slog"
+	"github.com/njehyde/issue-tracker/pkg/listing"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -19,9 +23,13 @@ type Repository struct {
 
 // Storage stores beer data in JSON files
 type Storage struct {
-	client *mongo.Client
-	db     *mongo.Database
-	repo   *Repository
+	client    *mongo.Client
+	db        *mongo.Database
+	repo      *Repository
+	rebalance chan issueList
+
+	rebalancing     map[issueList]bool
+	rebalancingLock sync.Mutex
 }
 
 // NewStorage returns a new mongodb storage
@@ -75,6 +83,9 @@ func NewStorage() (*Storage, error) {
 	repo.db = client.Database("issue-tracker")
 	s.repo = repo
 
+	s.rebalance = make(chan issueList, 64)
+	s.rebalancing = make(map[issueList]bool)
+
 	slog.Infof("Connected to MongoDB!")
 
 	return s, nil
@@ -90,6 +101,288 @@ func getHexFromObjectID(ID primitive.ObjectID) string {
 	return id
 }
 
+// getBoardWorkflow returns the workflow a board was created with, falling back to the workflow of its board
+// template for boards that pre-date the board's workflow reference.
+func (s *Storage) getBoardWorkflow(b *Board) (*Workflow, error) {
+	workflowID := b.WorkflowID
+
+	if workflowID == 0 {
+		bt, err := s.repo.GetBoardTemplate(&b.Type)
+		if err != nil {
+			return nil, err
+		}
+		workflowID = bt.WorkflowID
+	}
+
+	return s.repo.GetWorkflow(&workflowID)
+}
+
+// getProjectWorkflow returns the workflow of a project's default board.
+func (s *Storage) getProjectWorkflow(projectID primitive.ObjectID) (*Workflow, error) {
+	p, err := s.repo.GetProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := s.repo.GetBoard(&p.DefaultBoardID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getBoardWorkflow(b)
+}
+
+// checkIssueType returns an error where an issue type does not exist, or is not allowed by a project's issue
+// type scheme. An empty scheme allows every issue type.
+func (s *Storage) checkIssueType(issueType string, scheme []string) error {
+	if _, err := s.repo.GetIssueType(issueType); err != nil {
+		return fmt.Errorf("Issue type %v not found", issueType)
+	}
+
+	if len(scheme) == 0 {
+		return nil
+	}
+
+	for _, id := range scheme {
+		if id == issueType {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Issue type %v is not allowed by the project's issue type scheme", issueType)
+}
+
+// issueTypeEpic is the issue type of the issues that other issues can be grouped under.
+const issueTypeEpic = "EPIC"
+
+// getProjectEpicID returns the id of an epic of a project, or an error where the issue is not an epic of the project.
+func (s *Storage) getProjectEpicID(projectID primitive.ObjectID, epicID string) (primitive.ObjectID, error) {
+	epicIDAsObjectID, err := primitive.ObjectIDFromHex(epicID)
+	if err != nil {
+		return epicIDAsObjectID, err
+	}
+
+	epic, err := s.repo.GetIssue(epicIDAsObjectID)
+	if err != nil || epic.ProjectID != projectID {
+		return epicIDAsObjectID, fmt.Errorf("Epic %v not found for project %v", epicID, projectID.Hex())
+	}
+	if epic.Type != issueTypeEpic {
+		return epicIDAsObjectID, fmt.Errorf("Issue %v is not an epic", epic.ProjectRef)
+	}
+
+	return epicIDAsObjectID, nil
+}
+
+// addBoardTemplate adds a board template, making it the only default board template where it is the default.
+func (s *Storage) addBoardTemplate(bt *BoardTemplate) error {
+	err := s.repo.AddBoardTemplate(bt)
+	if err != nil {
+		return err
+	}
+
+	if bt.IsDefault {
+		return s.clearDefaultBoardTemplate(bt.ID)
+	}
+
+	return nil
+}
+
+// clearDefaultBoardTemplate unsets the default flag of every board template other than one.
+func (s *Storage) clearDefaultBoardTemplate(exceptID string) error {
+	filter := bson.M{"_id": bson.M{"$ne": exceptID}, "isDefault": true}
+	update := bson.M{"$set": bson.M{"isDefault": false}}
+
+	return s.repo.UpdateBoardTemplates(filter, update)
+}
+
+// getProjectBoard returns a board of a project, or an error where the board does not belong to the project.
+func (s *Storage) getProjectBoard(projectID primitive.ObjectID, boardID primitive.ObjectID) (*Board, error) {
+	p, err := s.repo.GetProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, b := range p.Boards {
+		if b == boardID {
+			return s.repo.GetBoard(&boardID)
+		}
+	}
+
+	return nil, fmt.Errorf("Board %v not found for project %v", boardID.Hex(), projectID.Hex())
+}
+
+// getBoardIssueQuery converts the issue filters of a board to a storage filter. Boards without filters match every
+// issue.
+func (s *Storage) getBoardIssueQuery(b *Board) (bson.M, error) {
+	q := listing.IssueQuery{}
+	for _, f := range b.Filters {
+		q.Filters = append(q.Filters, listing.IssueFilter{Field: f.Field, Value: f.Value})
+	}
+
+	filter, _, err := s.getIssueQuery(&q)
+	if err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+// checkBoardStatuses checks that every status of a workflow is in exactly one board column, or in the unmapped
+// statuses, and that no other statuses are used.
+func (s *Storage) checkBoardStatuses(workflowID int32, columns []BoardColumn, unmapped []string) error {
+	w, err := s.repo.GetWorkflow(&workflowID)
+	if err != nil {
+		return fmt.Errorf("Workflow %v not found", workflowID)
+	}
+
+	mapped := make(map[string]int)
+	for _, step := range w.Steps {
+		for _, status := range step.StatusIds {
+			mapped[status] = 0
+		}
+	}
+
+	use := func(status string) error {
+		count, ok := mapped[status]
+		if !ok {
+			return fmt.Errorf("Issue status %v is not in workflow %v", status, w.Name)
+		}
+		if count > 0 {
+			return fmt.Errorf("Issue status %v is mapped more than once", status)
+		}
+		mapped[status]++
+		return nil
+	}
+
+	for _, c := range columns {
+		for _, status := range c.IssueStatuses {
+			if err := use(status); err != nil {
+				return err
+			}
+		}
+	}
+	for _, status := range unmapped {
+		if err := use(status); err != nil {
+			return err
+		}
+	}
+
+	for status, count := range mapped {
+		if count == 0 {
+			return fmt.Errorf("Issue status %v must be in a column or unmapped", status)
+		}
+	}
+
+	return nil
+}
+
+// getBoardSprint returns a sprint of a board, or an error where the sprint does not belong to the board.
+func getBoardSprint(b *Board, sprintID primitive.ObjectID) (*Sprint, error) {
+	for i := range b.Sprints {
+		if b.Sprints[i].ID == sprintID {
+			return &b.Sprints[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("Sprint %v not found for board %v", sprintID.Hex(), b.ID.Hex())
+}
+
+// getProjectSprintID returns the id of a sprint of one of a project's boards, or an error where no board of the project
+// has the sprint.
+func (s *Storage) getProjectSprintID(projectID primitive.ObjectID, sprintID string) (primitive.ObjectID, error) {
+	sprintIDAsObjectID, err := primitive.ObjectIDFromHex(sprintID)
+	if err != nil {
+		return sprintIDAsObjectID, err
+	}
+
+	p, err := s.repo.GetProject(projectID)
+	if err != nil {
+		return sprintIDAsObjectID, err
+	}
+
+	boards, err := s.repo.GetBoardsByIds(&p.Boards)
+	if err != nil {
+		return sprintIDAsObjectID, err
+	}
+
+	for i := range *boards {
+		if _, err := getBoardSprint(&(*boards)[i], sprintIDAsObjectID); err == nil {
+			return sprintIDAsObjectID, nil
+		}
+	}
+
+	return sprintIDAsObjectID, fmt.Errorf("Sprint %v not found for project %v", sprintID, projectID.Hex())
+}
+
+// getDoneStatuses returns the set of issue statuses in the "DONE" category.
+func (s *Storage) getDoneStatuses() (map[string]bool, error) {
+	term := ""
+	issueStatuses, err := s.repo.GetIssueStatuses(&term, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	done := make(map[string]bool)
+	for _, is := range issueStatuses {
+		if is.CategoryID == "DONE" {
+			done[is.ID] = true
+		}
+	}
+
+	return done, nil
+}
+
+// getProjectComponentIDs returns a set of component ids as ObjectIDs, or an error where a component does not belong
+// to the project.
+func getProjectComponentIDs(p *Project, ids []string) ([]primitive.ObjectID, error) {
+	results := []primitive.ObjectID{}
+
+	for _, id := range ids {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return results, err
+		}
+
+		found := false
+		for _, c := range p.Components {
+			if c.ID == objectID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return results, fmt.Errorf("Component %v not found for project %v", id, p.Key)
+		}
+
+		results = append(results, objectID)
+	}
+
+	return results, nil
+}
+
+// getComponentAssignee returns the assignee for a new issue from the assignee policy of its first component,
+// falling back to the project's default assignee.
+func getComponentAssignee(p *Project, components []primitive.ObjectID) primitive.ObjectID {
+	for _, id := range components {
+		for _, c := range p.Components {
+			if c.ID != id {
+				continue
+			}
+			switch c.AssigneeType {
+			case componentAssigneeComponentLead:
+				if !c.LeadID.IsZero() {
+					return c.LeadID
+				}
+			case componentAssigneeUnassigned:
+				return primitive.NilObjectID
+			}
+			return p.DefaultAssigneeID
+		}
+	}
+
+	return p.DefaultAssigneeID
+}
+
 // // Query ...
 // type Query struct {
 // 	CollectionName *string
@@ -0,0 +1,273 @@
+package mongo
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/njehyde/issue-tracker/pkg/listing"
+	"github.com/njehyde/issue-tracker/pkg/updating"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// swimlaneType returns the swimlane type of a board. Boards created before swimlanes have none.
+func (b *Board) swimlaneType() string {
+	if len(b.SwimlaneType) == 0 {
+		return updating.SwimlaneTypeNone
+	}
+	return b.SwimlaneType
+}
+
+// UpdateProjectBoardSwimlanes updates the swimlane configuration of a board in the database's "boards" collection.
+func (s *Storage) UpdateProjectBoardSwimlanes(projectID *string, boardID *string, sl *updating.Swimlanes) error {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return err
+	}
+
+	boardIDAsObjectID, err := primitive.ObjectIDFromHex(*boardID)
+	if err != nil {
+		return err
+	}
+
+	if _, err = s.getProjectBoard(projectIDAsObjectID, boardIDAsObjectID); err != nil {
+		return err
+	}
+
+	queries := []SwimlaneQuery{}
+	for _, q := range sl.Queries {
+		query := SwimlaneQuery{Name: q.Name, Filters: []BoardFilter{}}
+		for _, f := range q.Filters {
+			query.Filters = append(query.Filters, BoardFilter{Field: f.Field, Value: f.Value})
+		}
+		if _, err = s.getBoardIssueQuery(&Board{Filters: query.Filters}); err != nil {
+			return err
+		}
+		queries = append(queries, query)
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"swimlaneType":    sl.Type,
+			"swimlaneQueries": queries,
+			"updatedAt":       time.Now(),
+		},
+	}
+
+	return s.repo.UpdateBoard(boardIDAsObjectID, update)
+}
+
+// swimlane defines the key and name of a lane, before the issues are placed in it.
+type swimlane struct {
+	key  string
+	name string
+}
+
+// GetProjectBoardIssues returns the issues on a board, in the database's "issues" collection, grouped into a cell
+// for each swimlane and column. Lanes without issues are left out, apart from the lanes of swimlane queries.
+func (s *Storage) GetProjectBoardIssues(projectID *string, boardID *string) (result *listing.BoardIssues, err error) {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return result, err
+	}
+
+	boardIDAsObjectID, err := primitive.ObjectIDFromHex(*boardID)
+	if err != nil {
+		return result, err
+	}
+
+	b, err := s.getProjectBoard(projectIDAsObjectID, boardIDAsObjectID)
+	if err != nil {
+		return result, err
+	}
+
+	columns := append([]BoardColumn{}, b.Columns...)
+	sort.Slice(columns, func(i, j int) bool { return columns[i].Ordinal < columns[j].Ordinal })
+
+	columnOrdinals := make(map[string]int32)
+	statuses := []string{}
+	for i, c := range columns {
+		for _, status := range c.IssueStatuses {
+			columnOrdinals[status] = int32(i)
+			statuses = append(statuses, status)
+		}
+	}
+
+	activeSprints := getBoardActiveSprints(b)
+
+	query, err := s.getBoardStatusesQuery(b, statuses, activeSprints)
+	if err != nil {
+		return result, err
+	}
+
+	issues, _, err := s.repo.GetProjectIssues(&projectIDAsObjectID, nil, 0, query, nil)
+	if err != nil {
+		return result, err
+	}
+
+	lanes, laneKeys, err := s.getBoardSwimlanes(projectIDAsObjectID, b, query, issues)
+	if err != nil {
+		return result, err
+	}
+
+	result = &listing.BoardIssues{
+		SwimlaneType: b.swimlaneType(),
+		Columns:      []string{},
+		Swimlanes:    []listing.Swimlane{},
+	}
+	for _, c := range columns {
+		result.Columns = append(result.Columns, c.Name)
+	}
+
+	laneIndexes := make(map[string]int)
+	for _, l := range lanes {
+		lane := listing.Swimlane{Key: l.key, Name: l.name, Cells: []listing.SwimlaneCell{}}
+		for i, c := range columns {
+			lane.Cells = append(lane.Cells, listing.SwimlaneCell{Column: c.Name, Ordinal: int32(i), Issues: []listing.Issue{}})
+		}
+		laneIndexes[l.key] = len(result.Swimlanes)
+		result.Swimlanes = append(result.Swimlanes, lane)
+	}
+
+	for i := range *issues {
+		issue := &(*issues)[i]
+		listingIssue := transformIssue(issue)
+		result.Swimlanes[laneIndexes[laneKeys[issue.ID]]].AddIssue(columnOrdinals[issue.Status], &listingIssue)
+	}
+
+	if b.swimlaneType() != updating.SwimlaneTypeQuery {
+		nonEmpty := []listing.Swimlane{}
+		for _, lane := range result.Swimlanes {
+			if lane.IssueCount > 0 || len(result.Swimlanes) == 1 {
+				nonEmpty = append(nonEmpty, lane)
+			}
+		}
+		result.Swimlanes = nonEmpty
+	}
+
+	return result, nil
+}
+
+// getBoardSwimlanes returns the lanes of a board in display order, and the key of the lane that each of its issues
+// belongs to. Issues without a value for the swimlane field belong to the lane with an empty key, which is last.
+func (s *Storage) getBoardSwimlanes(projectID primitive.ObjectID, b *Board, query bson.M, issues *[]Issue) ([]swimlane, map[primitive.ObjectID]string, error) {
+	lanes := []swimlane{}
+	laneKeys := make(map[primitive.ObjectID]string)
+
+	switch b.swimlaneType() {
+	case updating.SwimlaneTypeAssignee:
+		names := make(map[string]string)
+		for _, i := range *issues {
+			if i.AssigneeID.IsZero() {
+				continue
+			}
+			laneKeys[i.ID] = i.AssigneeID.Hex()
+			if _, ok := names[i.AssigneeID.Hex()]; ok {
+				continue
+			}
+
+			assigneeID := i.AssigneeID
+			u, err := s.repo.GetUserByID(&assigneeID)
+			if err != nil {
+				return nil, nil, err
+			}
+			names[assigneeID.Hex()] = strings.TrimSpace(u.Name.FirstName + " " + u.Name.LastName)
+		}
+		for key, name := range names {
+			lanes = append(lanes, swimlane{key, name})
+		}
+		sort.Slice(lanes, func(i, j int) bool { return lanes[i].name < lanes[j].name })
+		lanes = append(lanes, swimlane{"", "Unassigned"})
+
+	case updating.SwimlaneTypeEpic:
+		epicIDs := []primitive.ObjectID{}
+		seen := make(map[primitive.ObjectID]bool)
+		for _, i := range *issues {
+			if i.EpicID.IsZero() {
+				continue
+			}
+			laneKeys[i.ID] = i.EpicID.Hex()
+			if !seen[i.EpicID] {
+				seen[i.EpicID] = true
+				epicIDs = append(epicIDs, i.EpicID)
+			}
+		}
+		if len(epicIDs) > 0 {
+			epics, err := s.repo.GetIssuesByIds(&epicIDs)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, e := range *epics {
+				lanes = append(lanes, swimlane{e.ID.Hex(), e.Summary})
+			}
+		}
+		sort.Slice(lanes, func(i, j int) bool { return lanes[i].name < lanes[j].name })
+		lanes = append(lanes, swimlane{"", "No epic"})
+
+	case updating.SwimlaneTypePriority:
+		priorityTypes, err := s.repo.GetPriorityTypes(1)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, pt := range priorityTypes {
+			lanes = append(lanes, swimlane{pt.ID, pt.Name})
+		}
+		for _, i := range *issues {
+			laneKeys[i.ID] = i.Priority
+		}
+		lanes = append(lanes, swimlane{"", "No priority"})
+
+	case updating.SwimlaneTypeIssueType:
+		issueTypes, err := s.repo.GetIssueTypes()
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, it := range *issueTypes {
+			lanes = append(lanes, swimlane{it.ID, it.Name})
+		}
+		sort.Slice(lanes, func(i, j int) bool { return lanes[i].name < lanes[j].name })
+		for _, i := range *issues {
+			laneKeys[i.ID] = i.Type
+		}
+		lanes = append(lanes, swimlane{"", "No issue type"})
+
+	case updating.SwimlaneTypeQuery:
+		for _, q := range b.SwimlaneQueries {
+			laneQuery, err := s.getBoardIssueQuery(&Board{Filters: q.Filters})
+			if err != nil {
+				return nil, nil, err
+			}
+
+			matches, _, err := s.repo.GetProjectIssues(&projectID, nil, 0, bson.M{"$and": []bson.M{query, laneQuery}}, nil)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			// Issues belong to the first query that they match
+			for _, i := range *matches {
+				if _, ok := laneKeys[i.ID]; !ok {
+					laneKeys[i.ID] = q.Name
+				}
+			}
+			lanes = append(lanes, swimlane{q.Name, q.Name})
+		}
+		lanes = append(lanes, swimlane{"", "Everything else"})
+
+	default:
+		lanes = append(lanes, swimlane{"", "All issues"})
+	}
+
+	// Issues whose value has no lane, such as an unknown priority, are placed in the lane with an empty key
+	known := make(map[string]bool)
+	for _, l := range lanes {
+		known[l.key] = true
+	}
+	for id, key := range laneKeys {
+		if !known[key] {
+			laneKeys[id] = ""
+		}
+	}
+
+	return lanes, laneKeys, nil
+}
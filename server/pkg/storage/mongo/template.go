@@ -0,0 +1,340 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/njehyde/issue-tracker/libraries/slog"
+	"github.com/njehyde/issue-tracker/pkg/adding"
+	"github.com/njehyde/issue-tracker/pkg/listing"
+	"github.com/njehyde/issue-tracker/pkg/updating"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IssueTemplate defines the storage form of a project issue template entity. Issues of its issue type that are added
+// from it have the summary prefix, and take its description, priority, labels and custom field values where they have
+// none of their own.
+type IssueTemplate struct {
+	ID            primitive.ObjectID     `bson:"_id"`
+	Name          string                 `bson:"name"`
+	IssueType     string                 `bson:"issueType"`
+	SummaryPrefix string                 `bson:"summaryPrefix,omitempty"`
+	Description   string                 `bson:"description,omitempty"`
+	Priority      string                 `bson:"priority,omitempty"`
+	Labels        []string               `bson:"labels,omitempty"`
+	CustomFields  map[string]interface{} `bson:"customFields,omitempty"`
+	CreatedAt     time.Time              `bson:"createdAt"`
+	UpdatedAt     time.Time              `bson:"updatedAt"`
+}
+
+// AddProjectIssueTemplate ...
+func (r *Repository) AddProjectIssueTemplate(projectID primitive.ObjectID, t *IssueTemplate) error {
+	collection := r.db.Collection("projects")
+
+	now := time.Now()
+
+	t.ID = primitive.NewObjectID()
+	t.CreatedAt = now
+	t.UpdatedAt = now
+
+	filter := bson.M{"_id": projectID}
+
+	update := bson.M{
+		"$set": bson.M{
+			"updatedAt": now,
+		},
+		"$push": bson.M{
+			"issueTemplates": t,
+		},
+	}
+
+	updateResult, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return fmt.Errorf("Project %v not found", projectID.Hex())
+	}
+
+	slog.Infof("Added issue template %v via update to project %v: %+v", t.ID.Hex(), projectID.Hex(), updateResult)
+
+	return nil
+}
+
+// DeleteProjectIssueTemplate ...
+func (r *Repository) DeleteProjectIssueTemplate(projectID primitive.ObjectID, templateID primitive.ObjectID) error {
+	collection := r.db.Collection("projects")
+
+	filter := bson.M{"_id": projectID, "issueTemplates._id": templateID}
+
+	update := bson.M{
+		"$set": bson.M{
+			"updatedAt": time.Now(),
+		},
+		"$pull": bson.M{
+			"issueTemplates": bson.M{
+				"_id": templateID,
+			},
+		},
+	}
+
+	updateResult, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return fmt.Errorf("Issue template %v not found for project %v", templateID.Hex(), projectID.Hex())
+	}
+
+	slog.Infof("Deleted issue template %v via update to project %v: %+v", templateID.Hex(), projectID.Hex(), updateResult)
+
+	return nil
+}
+
+// UpdateProjectIssueTemplate ...
+func (r *Repository) UpdateProjectIssueTemplate(projectID primitive.ObjectID, templateID primitive.ObjectID, set primitive.M) error {
+	collection := r.db.Collection("projects")
+
+	now := time.Now()
+
+	filter := bson.M{"_id": projectID, "issueTemplates._id": templateID}
+
+	update := bson.M{"$set": bson.M{"updatedAt": now, "issueTemplates.$.updatedAt": now}}
+	for k, v := range set {
+		update["$set"].(bson.M)["issueTemplates.$."+k] = v
+	}
+
+	updateResult, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return fmt.Errorf("Issue template %v not found for project %v", templateID.Hex(), projectID.Hex())
+	}
+
+	slog.Infof("Updated issue template %v via update to project %v: %+v", templateID.Hex(), projectID.Hex(), updateResult)
+
+	return nil
+}
+
+// AddProjectIssueTemplate adds an issue template child entity to a project in the database's "projects" collection.
+func (s *Storage) AddProjectIssueTemplate(projectID *string, t *adding.IssueTemplate) error {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return err
+	}
+
+	template := IssueTemplate{
+		Name:          t.Name,
+		IssueType:     t.IssueType,
+		SummaryPrefix: t.SummaryPrefix,
+		Description:   t.Description,
+		Priority:      t.Priority,
+		Labels:        t.Labels,
+	}
+
+	template.CustomFields, err = s.checkIssueTemplate(projectIDAsObjectID, t.IssueType, t.Priority, t.Labels, t.CustomFields)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.AddProjectIssueTemplate(projectIDAsObjectID, &template)
+}
+
+// DeleteProjectIssueTemplate deletes an issue template child entity of a project in the database's "projects"
+// collection. Issues added from the template are left as they are.
+func (s *Storage) DeleteProjectIssueTemplate(projectID *string, templateID *string) error {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return err
+	}
+
+	templateIDAsObjectID, err := primitive.ObjectIDFromHex(*templateID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.DeleteProjectIssueTemplate(projectIDAsObjectID, templateIDAsObjectID)
+}
+
+// GetProjectIssueTemplates returns the issue template entities of a project, or only those of an issue type, from the
+// repository.
+func (s *Storage) GetProjectIssueTemplates(projectID *string, issueType string) (results []listing.IssueTemplate, err error) {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return results, err
+	}
+
+	p, err := s.repo.GetProject(projectIDAsObjectID)
+	if err != nil {
+		return results, err
+	}
+
+	results = []listing.IssueTemplate{}
+	for _, t := range p.IssueTemplates {
+		if len(issueType) > 0 && t.IssueType != issueType {
+			continue
+		}
+		results = append(results, listing.IssueTemplate{
+			ID:            t.ID.Hex(),
+			Name:          t.Name,
+			IssueType:     t.IssueType,
+			SummaryPrefix: t.SummaryPrefix,
+			Description:   t.Description,
+			Priority:      t.Priority,
+			Labels:        t.Labels,
+			CustomFields:  transformCustomFieldValues(t.CustomFields),
+			CreatedAt:     t.CreatedAt,
+			UpdatedAt:     t.UpdatedAt,
+		})
+	}
+
+	return results, nil
+}
+
+// UpdateProjectIssueTemplate updates an issue template child entity of a project in the database's "projects"
+// collection.
+func (s *Storage) UpdateProjectIssueTemplate(projectID *string, templateID *string, t *updating.IssueTemplate) error {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return err
+	}
+
+	templateIDAsObjectID, err := primitive.ObjectIDFromHex(*templateID)
+	if err != nil {
+		return err
+	}
+
+	customFields, err := s.checkIssueTemplate(projectIDAsObjectID, t.IssueType, t.Priority, t.Labels, t.CustomFields)
+	if err != nil {
+		return err
+	}
+
+	set := bson.M{
+		"name":          t.Name,
+		"issueType":     t.IssueType,
+		"summaryPrefix": t.SummaryPrefix,
+		"description":   t.Description,
+		"priority":      t.Priority,
+		"labels":        t.Labels,
+		"customFields":  customFields,
+	}
+
+	return s.repo.UpdateProjectIssueTemplate(projectIDAsObjectID, templateIDAsObjectID, set)
+}
+
+// checkIssueTemplate checks the issue type, priority and custom field values of an issue template against its project,
+// adds any of its labels not yet in the database, and returns its custom field values in their storage form. Templates
+// do not need values for required custom fields, as the issues added from them can still give them.
+func (s *Storage) checkIssueTemplate(projectID primitive.ObjectID, issueType string, priority string, labels []string, values map[string]interface{}) (map[string]interface{}, error) {
+	p, err := s.repo.GetProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.checkIssueType(issueType, p.IssueTypes); err != nil {
+		return nil, err
+	}
+
+	if len(priority) > 0 {
+		priorityTypes, err := s.repo.GetPriorityTypes(1)
+		if err != nil {
+			return nil, err
+		}
+
+		found := false
+		for _, pt := range priorityTypes {
+			if pt.ID == priority {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("Priority %v not found", priority)
+		}
+	}
+
+	customFields, err := s.getCustomFieldValues(projectID, issueType, values, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.addMissingLabels(labels); err != nil {
+		return nil, err
+	}
+
+	if len(customFields) == 0 {
+		return nil, nil
+	}
+
+	return customFields, nil
+}
+
+// applyIssueTemplate merges a project issue template into an issue that is being added from it. The template's issue
+// type is used where the issue has none, and the summary prefix is added unless the summary already starts with it.
+// The description, priority and custom field values of the issue win over those of the template, and the template's
+// labels are added to the issue's labels.
+func applyIssueTemplate(p *Project, i *adding.Issue) error {
+	templateIDAsObjectID, err := primitive.ObjectIDFromHex(i.TemplateID)
+	if err != nil {
+		return err
+	}
+
+	var t *IssueTemplate
+	for n := range p.IssueTemplates {
+		if p.IssueTemplates[n].ID == templateIDAsObjectID {
+			t = &p.IssueTemplates[n]
+			break
+		}
+	}
+	if t == nil {
+		return fmt.Errorf("Issue template %v not found for project %v", i.TemplateID, p.Key)
+	}
+
+	if len(i.Type) == 0 {
+		i.Type = t.IssueType
+	}
+	if i.Type != t.IssueType {
+		return fmt.Errorf("Issue template %v is for issue type %v, not %v", t.Name, t.IssueType, i.Type)
+	}
+
+	if !strings.HasPrefix(i.Summary, t.SummaryPrefix) {
+		i.Summary = t.SummaryPrefix + i.Summary
+	}
+	if len(i.Description) == 0 {
+		i.Description = t.Description
+	}
+	if len(i.Priority) == 0 {
+		i.Priority = t.Priority
+	}
+
+	for _, l := range t.Labels {
+		found := false
+		for _, label := range i.Labels {
+			if label.Value == l {
+				found = true
+				break
+			}
+		}
+		if !found {
+			i.Labels = append(i.Labels, adding.Label{IsNew: false, Value: l})
+		}
+	}
+
+	if len(t.CustomFields) > 0 && i.CustomFields == nil {
+		i.CustomFields = make(map[string]interface{})
+	}
+	for id, v := range getCustomFieldFormValues(t.CustomFields) {
+		if current, ok := i.CustomFields[id]; !ok || current == nil || current == "" {
+			i.CustomFields[id] = v
+		}
+	}
+
+	return nil
+}
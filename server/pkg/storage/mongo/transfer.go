@@ -0,0 +1,291 @@
+package mongo
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/njehyde/issue-tracker/pkg/adding"
+	"github.com/njehyde/issue-tracker/pkg/updating"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// issueTransfer is an issue made ready to be moved, or copied, to a project: its type, status and custom field values
+// are checked against the project, and it has a new project reference and a rank at the bottom of the project's
+// backlog.
+type issueTransfer struct {
+	projectRef   string
+	issueType    string
+	status       string
+	rank         string
+	customFields map[string]interface{}
+}
+
+// CloneIssue adds a copy of an issue to a project in the database's "issues" collection, along with copies of its
+// comments and history. The copy starts in the project's backlog, without the sprint, epic, components and versions
+// of the project it was copied from, and without the work logged against the issue.
+func (s *Storage) CloneIssue(issueID *string, c *adding.IssueClone) (string, error) {
+	issueIDAsObjectID, err := primitive.ObjectIDFromHex(*issueID)
+	if err != nil {
+		return "", err
+	}
+
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(c.ProjectID)
+	if err != nil {
+		return "", err
+	}
+
+	issue, err := s.repo.GetIssue(issueIDAsObjectID)
+	if err != nil {
+		return "", err
+	}
+
+	t, err := s.getIssueTransfer(issue, projectIDAsObjectID, c.Type, c.Status)
+	if err != nil {
+		return "", err
+	}
+
+	clone := Issue{
+		ProjectID:         projectIDAsObjectID,
+		ProjectRef:        t.projectRef,
+		Type:              t.issueType,
+		Summary:           issue.Summary,
+		Description:       issue.Description,
+		Status:            t.status,
+		Priority:          issue.Priority,
+		Points:            issue.Points,
+		OriginalEstimate:  issue.OriginalEstimate,
+		RemainingEstimate: issue.OriginalEstimate,
+		DueDate:           issue.DueDate,
+		ReporterID:        issue.ReporterID,
+		AssigneeID:        issue.AssigneeID,
+		Labels:            issue.Labels,
+		Rank:              t.rank,
+		CustomFields:      t.customFields,
+		ClonedFromID:      issue.ID,
+	}
+
+	err = s.repo.AddIssue(&clone)
+	if err != nil {
+		return "", err
+	}
+
+	comments, _, err := s.repo.GetIssueComments(&issue.ID, nil, 0)
+	if err != nil {
+		return "", err
+	}
+	for i := range *comments {
+		(*comments)[i].IssueID = clone.ID
+	}
+	if err = s.repo.AddIssueComments(*comments); err != nil {
+		return "", err
+	}
+
+	// The copied history is replayed at the moment the clone was created, in its original order, and followed by the
+	// changes to the clone's own status, sprint and estimate, so that reports never see the clone before it existed
+	changes, err := s.repo.GetIssueChanges(bson.M{"issueId": issue.ID})
+	if err != nil {
+		return "", err
+	}
+
+	created := bson.M{
+		"$set": bson.M{
+			"status":            clone.Status,
+			"points":            clone.Points,
+			"originalEstimate":  clone.OriginalEstimate,
+			"remainingEstimate": clone.RemainingEstimate,
+		},
+		"$unset": bson.M{"sprintId": ""},
+	}
+	*changes = append(*changes, getIssueChanges(issue, created, clone.CreatedAt)...)
+
+	for i := range *changes {
+		(*changes)[i].IssueID = clone.ID
+		(*changes)[i].ProjectID = projectIDAsObjectID
+		(*changes)[i].CreatedAt = clone.CreatedAt
+	}
+	if err = s.repo.AddIssueChanges(*changes); err != nil {
+		return "", err
+	}
+
+	return clone.ID.Hex(), nil
+}
+
+// TransferIssue moves an issue to another project in the database's "issues" collection, under a new project
+// reference. The issue keeps its id, so its comments, work logs and history stay with it, and it keeps answering to
+// its old reference. It moves to the project's backlog, without the sprint, epic, components, versions and SLA
+// breaches of the project it was moved from.
+func (s *Storage) TransferIssue(issueID *string, t *updating.IssueTransfer) (string, error) {
+	issueIDAsObjectID, err := primitive.ObjectIDFromHex(*issueID)
+	if err != nil {
+		return "", err
+	}
+
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(t.ProjectID)
+	if err != nil {
+		return "", err
+	}
+
+	issue, err := s.repo.GetIssue(issueIDAsObjectID)
+	if err != nil {
+		return "", err
+	}
+
+	if issue.ProjectID == projectIDAsObjectID {
+		return "", fmt.Errorf("Issue %v is already in project %v", issue.ProjectRef, t.ProjectID)
+	}
+
+	// Epics would leave their issues behind in the project they were moved from
+	if issue.Type == issueTypeEpic {
+		count, err := s.repo.CountProjectIssues(&issue.ProjectID, bson.M{"epicId": issue.ID})
+		if err != nil {
+			return "", err
+		}
+		if count > 0 {
+			return "", fmt.Errorf("Epic %v cannot be moved while %v issues belong to it", issue.ProjectRef, count)
+		}
+	}
+
+	transfer, err := s.getIssueTransfer(issue, projectIDAsObjectID, t.Type, t.Status)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+
+	set := bson.M{
+		"projectId":  projectIDAsObjectID,
+		"projectRef": transfer.projectRef,
+		"type":       transfer.issueType,
+		"status":     transfer.status,
+		"rank":       transfer.rank,
+		"updatedAt":  now,
+	}
+	unset := bson.M{
+		"sprintId":    "",
+		"epicId":      "",
+		"components":  "",
+		"fixVersions": "",
+		"slaBreaches": "",
+	}
+
+	if len(transfer.customFields) > 0 {
+		set["customFields"] = transfer.customFields
+	} else {
+		unset["customFields"] = ""
+	}
+
+	update := bson.M{
+		"$set":   set,
+		"$unset": unset,
+		"$push":  bson.M{"previousRefs": issue.ProjectRef},
+	}
+
+	err = s.repo.UpdateIssue(issue.ID, update)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.repo.AddIssueChanges(getIssueChanges(issue, update, now))
+	if err != nil {
+		return "", err
+	}
+
+	// The issue's history and work logs are reported on by the project it is now in
+	moved := bson.M{"$set": bson.M{"projectId": projectIDAsObjectID}}
+
+	if err = s.repo.UpdateManyIssueChanges(bson.M{"issueId": issue.ID}, moved); err != nil {
+		return "", err
+	}
+	if err = s.repo.UpdateManyIssueWorklogs(bson.M{"issueId": issue.ID}, moved); err != nil {
+		return "", err
+	}
+
+	return issue.ProjectID.Hex(), nil
+}
+
+// getIssueTransfer checks that an issue can be moved, or copied, to a project with the given issue type and status,
+// which default to the issue's own. The issue type must be allowed by the project's issue type scheme, the status must
+// be in the workflow of the project's default board, and custom fields the project does not have are dropped. The
+// project counter is only incremented once the checks have passed.
+func (s *Storage) getIssueTransfer(i *Issue, projectID primitive.ObjectID, issueType string, status string) (*issueTransfer, error) {
+	p, err := s.repo.GetProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(issueType) == 0 {
+		issueType = i.Type
+	}
+	if len(status) == 0 {
+		status = i.Status
+	}
+
+	if err = s.checkIssueType(issueType, p.IssueTypes); err != nil {
+		return nil, err
+	}
+
+	if (i.Type == issueTypeEpic) != (issueType == issueTypeEpic) {
+		return nil, fmt.Errorf("Issues cannot be changed to or from epics when they are moved")
+	}
+
+	w, err := s.getProjectWorkflow(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	found := false
+	for _, step := range w.Steps {
+		for _, id := range step.StatusIds {
+			if id == status {
+				found = true
+				break
+			}
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("Status %v is not in the workflow of project %v", status, p.Key)
+	}
+
+	customFields, err := s.repo.GetCustomFields(&projectID, issueType)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]interface{})
+	current := getCustomFieldFormValues(i.CustomFields)
+	for _, cf := range *customFields {
+		if v, ok := current[cf.ID.Hex()]; ok {
+			values[cf.ID.Hex()] = v
+		}
+	}
+
+	values, err = s.getIssueCustomFieldValues(projectID, issueType, values)
+	if err != nil {
+		return nil, err
+	}
+
+	ranks, err := s.getIssueListLastRanks(issueList{projectID: projectID}, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.UpdateProjectCounter(p.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	t := issueTransfer{
+		projectRef: p.Key + "-" + strconv.FormatInt(count, 10),
+		issueType:  issueType,
+		status:     status,
+		rank:       ranks[0],
+	}
+
+	if len(values) > 0 {
+		t.customFields = values
+	}
+
+	return &t, nil
+}
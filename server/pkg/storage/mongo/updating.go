@@ -3,6 +3,7 @@ package mongo
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/njehyde/issue-tracker/libraries/slog"
@@ -11,6 +12,99 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// CompleteProjectBoardSprint closes an active sprint child entity of a board in the database's "boards" collection.
+// Issues of the sprint whose status is not in the "DONE" category are sent to the bottom of the backlog, or of the
+// sprint given by the completion request.
+func (s *Storage) CompleteProjectBoardSprint(projectID *string, boardID *string, sprintID *string, sc *updating.SprintCompletion) error {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return err
+	}
+
+	boardIDAsObjectID, err := primitive.ObjectIDFromHex(*boardID)
+	if err != nil {
+		return err
+	}
+
+	sprintIDAsObjectID, err := primitive.ObjectIDFromHex(*sprintID)
+	if err != nil {
+		return err
+	}
+
+	board, err := s.getProjectBoard(projectIDAsObjectID, boardIDAsObjectID)
+	if err != nil {
+		return err
+	}
+
+	sprint, err := getBoardSprint(board, sprintIDAsObjectID)
+	if err != nil {
+		return err
+	}
+	if sprint.state() != sprintActive {
+		return fmt.Errorf("Sprint %v is not active", sprint.Name)
+	}
+
+	var moveTo *Sprint
+	switch sc.MoveUnfinishedTo {
+	case "":
+	case "NEXT":
+		for i := range board.Sprints {
+			next := &board.Sprints[i]
+			if next.state() != sprintFuture || next.Ordinal <= sprint.Ordinal {
+				continue
+			}
+			if moveTo == nil || next.Ordinal < moveTo.Ordinal {
+				moveTo = next
+			}
+		}
+		if moveTo == nil {
+			return fmt.Errorf("Board %v has no sprint after %v", board.Name, sprint.Name)
+		}
+	default:
+		moveToIDAsObjectID, err := primitive.ObjectIDFromHex(sc.MoveUnfinishedTo)
+		if err != nil {
+			return err
+		}
+
+		moveTo, err = getBoardSprint(board, moveToIDAsObjectID)
+		if err != nil {
+			return err
+		}
+		if moveTo.ID == sprint.ID || moveTo.state() == sprintClosed {
+			return fmt.Errorf("Unfinished issues can only be moved to another open sprint")
+		}
+	}
+
+	done, err := s.getDoneStatuses()
+	if err != nil {
+		return err
+	}
+
+	if moveTo == nil {
+		err = s.SendSprintIssuesToBacklog(&projectIDAsObjectID, &sprintIDAsObjectID, done)
+		if err != nil {
+			return err
+		}
+	} else {
+		err = s.SendSprintIssuesToSprint(&projectIDAsObjectID, &sprintIDAsObjectID, &moveTo.ID, done)
+		if err != nil {
+			return err
+		}
+	}
+
+	completedSprint := bson.D{
+		{Key: "sprints.$.state", Value: sprintClosed},
+		{Key: "sprints.$.completedAt", Value: time.Now()},
+	}
+
+	err = s.repo.UpdateBoardSprint(&boardIDAsObjectID, &sprintIDAsObjectID, &completedSprint)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // DecreaseIssueStatus updates the ordinal position of an issue status entity, as well as one or more of its siblings.
 func (s *Storage) DecreaseIssueStatus(id string) error {
 	var term string
@@ -173,76 +267,245 @@ func (s *Storage) IncreasePriorityType(id string) error {
 	return nil
 }
 
-// SendIssueToSprint sends an issue to the bottom of the backlog, and reassigns backlog issue ordinal positions.
-func (s *Storage) SendIssueToSprint(projectID *string, sprintID *string, issueID *string, d *updating.SendIssueToSprintMetadata) error {
+// GetProjectWorkflowTransitions returns the transitions of the workflow used by a project from the repository.
+func (s *Storage) GetProjectWorkflowTransitions(projectID *string) (results []updating.WorkflowTransition, err error) {
 	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
 	if err != nil {
-		return err
+		return results, err
 	}
 
-	sprintIDAsObjectID, err := primitive.ObjectIDFromHex(*sprintID)
+	w, err := s.getProjectWorkflow(projectIDAsObjectID)
+	if err != nil {
+		return results, err
+	}
+
+	transformRules := func(rs []TransitionRule) []updating.TransitionRule {
+		rules := []updating.TransitionRule{}
+		for _, r := range rs {
+			rules = append(rules, updating.TransitionRule{Type: r.Type, Params: r.Params})
+		}
+		return rules
+	}
+
+	results = make([]updating.WorkflowTransition, 0)
+
+	for _, t := range w.Transitions {
+		transition := updating.WorkflowTransition{
+			Name:          t.Name,
+			FromStatusIDs: t.FromStatusIDs,
+			ToStatusID:    t.ToStatusID,
+			Conditions:    transformRules(t.Conditions),
+			Validators:    transformRules(t.Validators),
+			PostFunctions: transformRules(t.PostFunctions),
+		}
+
+		results = append(results, transition)
+	}
+
+	return results, nil
+}
+
+// GetTransitionIssues returns the transition state of a set of issue entities, keyed by id, from the repository.
+func (s *Storage) GetTransitionIssues(ids []string) (results map[string]updating.TransitionIssue, err error) {
+	var objectIDs []primitive.ObjectID
+	for _, id := range ids {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return results, err
+		}
+		objectIDs = append(objectIDs, objectID)
+	}
+
+	issues, err := s.repo.GetIssuesByIds(&objectIDs)
+	if err != nil {
+		return results, err
+	}
+
+	results = make(map[string]updating.TransitionIssue)
+
+	for _, i := range *issues {
+		results[i.ID.Hex()] = updating.TransitionIssue{
+			ID:         i.ID.Hex(),
+			ProjectID:  getHexFromObjectID(i.ProjectID),
+			Type:       i.Type,
+			Status:     i.Status,
+			Priority:   i.Priority,
+			Points:     i.Points,
+			ReporterID: getHexFromObjectID(i.ReporterID),
+			AssigneeID: getHexFromObjectID(i.AssigneeID),
+			Labels:     i.Labels,
+		}
+	}
+
+	return results, nil
+}
+
+// transitionUpdate returns the fields changed by a workflow transition's post functions, so that they are
+// written by the same update as the status.
+func transitionUpdate(t *updating.TransitionIssue) (bson.M, error) {
+	assigneeIDAsObjectID := primitive.NilObjectID
+	if len(t.AssigneeID) > 0 {
+		var err error
+		if assigneeIDAsObjectID, err = primitive.ObjectIDFromHex(t.AssigneeID); err != nil {
+			return nil, err
+		}
+	}
+
+	return bson.M{
+		"type":       t.Type,
+		"status":     t.Status,
+		"priority":   t.Priority,
+		"points":     t.Points,
+		"assigneeId": assigneeIDAsObjectID,
+	}, nil
+}
+
+// MergeLabels replaces a label entity with a target label on every issue in the database's "issues" collection,
+// then deletes it from the "labels" collection.
+func (s *Storage) MergeLabels(id string, targetID string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return err
 	}
 
-	issueIDAsObjectID, err := primitive.ObjectIDFromHex(*issueID)
+	targetIDAsObjectID, err := primitive.ObjectIDFromHex(targetID)
 	if err != nil {
 		return err
 	}
 
-	issue, err := s.repo.GetIssue(issueIDAsObjectID)
+	label, err := s.repo.GetLabel(objectID)
+	if err != nil {
+		return fmt.Errorf("Label %v not found", id)
+	}
+
+	target, err := s.repo.GetLabel(targetIDAsObjectID)
+	if err != nil {
+		return fmt.Errorf("Label %v not found", targetID)
+	}
+
+	filter := bson.M{"labels": label.Label}
+
+	err = s.repo.UpdateManyIssues(filter, bson.M{"$addToSet": bson.M{"labels": target.Label}})
 	if err != nil {
 		return err
 	}
 
-	shouldCleanBacklogOrdinals := issue.SprintID.IsZero()
+	err = s.repo.UpdateManyIssues(filter, bson.M{
+		"$pull": bson.M{"labels": label.Label},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	})
+	if err != nil {
+		return err
+	}
 
-	count, err := s.repo.CountProjectSprintIssues(&projectIDAsObjectID, &sprintIDAsObjectID)
+	err = s.repo.DeleteLabel(objectID)
 	if err != nil {
 		return err
 	}
 
-	updateSetMap := bson.M{
-		"ordinal":  int32(count),
-		"sprintId": sprintIDAsObjectID,
+	return nil
+}
+
+// ReleaseProjectVersion releases a version child entity of a project in the database's "projects" collection. Where
+// a version to move unfinished issues to is given, issues of the released version whose status is not in the "DONE"
+// category are moved to it.
+func (s *Storage) ReleaseProjectVersion(projectID *string, versionID *string, vr *updating.VersionRelease) error {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return err
 	}
 
-	if d != nil && len(d.Status) > 0 {
-		slog.Infof("Setting status to %v", d.Status)
-		updateSetMap["status"] = d.Status
+	versionIDAsObjectID, err := primitive.ObjectIDFromHex(*versionID)
+	if err != nil {
+		return err
 	}
 
-	update := bson.M{
-		"$set": updateSetMap,
+	project, err := s.repo.GetProject(projectIDAsObjectID)
+	if err != nil {
+		return err
 	}
 
-	err = s.repo.UpdateIssue(issueIDAsObjectID, update)
+	version, err := getProjectVersion(project, versionIDAsObjectID)
 	if err != nil {
 		return err
 	}
+	if version.Status != versionUnreleased {
+		return fmt.Errorf("Version %v is not unreleased", version.Name)
+	}
 
-	if shouldCleanBacklogOrdinals {
-		err = s.CleanBacklogIssueOrdinals(&projectIDAsObjectID)
+	if len(vr.MoveUnfinishedTo) > 0 {
+		moveToIDAsObjectID, err := primitive.ObjectIDFromHex(vr.MoveUnfinishedTo)
 		if err != nil {
 			return err
 		}
-	} else {
-		err = s.CleanSprintIssueOrdinals(&projectIDAsObjectID, &issue.SprintID)
+
+		moveTo, err := getProjectVersion(project, moveToIDAsObjectID)
+		if err != nil {
+			return err
+		}
+		if moveTo.Status != versionUnreleased {
+			return fmt.Errorf("Unfinished issues can only be moved to an unreleased version")
+		}
+
+		done, err := s.getDoneStatuses()
+		if err != nil {
+			return err
+		}
+		doneStatuses := []string{}
+		for status := range done {
+			doneStatuses = append(doneStatuses, status)
+		}
+
+		filter := bson.M{
+			"projectId":   projectIDAsObjectID,
+			"fixVersions": versionIDAsObjectID,
+			"status":      bson.M{"$nin": doneStatuses},
+		}
+
+		err = s.repo.UpdateManyIssues(filter, bson.M{"$addToSet": bson.M{"fixVersions": moveToIDAsObjectID}})
 		if err != nil {
 			return err
 		}
+
+		err = s.repo.UpdateManyIssues(filter, bson.M{
+			"$pull": bson.M{"fixVersions": versionIDAsObjectID},
+			"$set":  bson.M{"updatedAt": time.Now()},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	releasedAt := time.Now()
+	if vr.ReleasedAt != nil {
+		releasedAt = *vr.ReleasedAt
+	}
+
+	set := bson.M{
+		"status":     versionReleased,
+		"releasedAt": releasedAt,
+	}
+
+	err = s.repo.UpdateProjectVersion(projectIDAsObjectID, versionIDAsObjectID, set)
+	if err != nil {
+		return err
 	}
 
 	return nil
 }
 
-// SendIssueToBottomOfBacklog sends an issue to the bottom of the backlog, and reassigns backlog issue ordinal positions.
-func (s *Storage) SendIssueToBottomOfBacklog(projectID *string, issueID *string) error {
+// SendIssueToSprint sends an issue to the bottom of a sprint, ranking it after the sprint's other issues.
+func (s *Storage) SendIssueToSprint(projectID *string, sprintID *string, issueID *string, d *updating.SendIssueToSprintMetadata, t *updating.TransitionIssue) error {
 	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
 	if err != nil {
 		return err
 	}
 
+	sprintIDAsObjectID, err := primitive.ObjectIDFromHex(*sprintID)
+	if err != nil {
+		return err
+	}
+
 	issueIDAsObjectID, err := primitive.ObjectIDFromHex(*issueID)
 	if err != nil {
 		return err
@@ -253,53 +516,49 @@ func (s *Storage) SendIssueToBottomOfBacklog(projectID *string, issueID *string)
 		return err
 	}
 
-	shouldCleanSprintOrdinals := !issue.SprintID.IsZero()
-
-	// Get all backlog issues (sorted by ordinal, ascending)
-	issues, _, err := s.repo.GetProjectBacklogIssues(&projectIDAsObjectID, nil, nil)
+	ranks, err := s.getIssueListLastRanks(issueList{projectIDAsObjectID, sprintIDAsObjectID}, 1)
 	if err != nil {
 		return err
 	}
 
-	updatesMap := make(map[primitive.ObjectID]interface{})
+	updateSetMap := bson.M{
+		"rank":     ranks[0],
+		"sprintId": sprintIDAsObjectID,
+	}
 
-	prevOrdinal := 0
-	for _, issue := range *issues {
-		if issue.ID != issueIDAsObjectID {
-			updatesMap[issue.ID] = bson.M{
-				"$set": bson.M{
-					"ordinal":  int32(prevOrdinal),
-					"sprintId": nil,
-				},
-			}
-			prevOrdinal++
+	if t != nil {
+		slog.Infof("Setting status to %v", t.Status)
+		transition, err := transitionUpdate(t)
+		if err != nil {
+			return err
 		}
+		for k, v := range transition {
+			updateSetMap[k] = v
+		}
+	} else if d != nil && len(d.Status) > 0 {
+		slog.Infof("Setting status to %v", d.Status)
+		updateSetMap["status"] = d.Status
 	}
 
-	updatesMap[issueIDAsObjectID] = bson.M{
-		"$set": bson.M{
-			"ordinal":  int32(len(updatesMap)),
-			"sprintId": nil,
-		},
+	update := bson.M{
+		"$set": updateSetMap,
 	}
 
-	err = s.repo.UpdateIssues(updatesMap)
+	err = s.repo.UpdateIssue(issueIDAsObjectID, update)
 	if err != nil {
 		return err
 	}
 
-	if shouldCleanSprintOrdinals {
-		err = s.CleanSprintIssueOrdinals(&projectIDAsObjectID, &issue.SprintID)
-		if err != nil {
-			return err
-		}
+	err = s.recordIssueChanges([]Issue{*issue}, map[primitive.ObjectID]interface{}{issueIDAsObjectID: update})
+	if err != nil {
+		return err
 	}
 
 	return nil
 }
 
-// SendIssueToTopOfBacklog sends an issue to the top of the backlog, and reassigns backlog issue ordinal positions.
-func (s *Storage) SendIssueToTopOfBacklog(projectID *string, issueID *string) error {
+// SendIssueToBottomOfBacklog sends an issue to the bottom of the backlog, ranking it after the other backlog issues.
+func (s *Storage) SendIssueToBottomOfBacklog(projectID *string, issueID *string) error {
 	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
 	if err != nil {
 		return err
@@ -315,73 +574,88 @@ func (s *Storage) SendIssueToTopOfBacklog(projectID *string, issueID *string) er
 		return err
 	}
 
-	shouldCleanSprintOrdinals := !issue.SprintID.IsZero()
+	ranks, err := s.getIssueListLastRanks(issueList{projectID: projectIDAsObjectID}, 1)
+	if err != nil {
+		return err
+	}
+
+	return s.sendIssueToBacklog(issue, ranks[0])
+}
 
-	// Get all backlog issues (sorted by ordinal, ascending)
-	issues, _, err := s.repo.GetProjectBacklogIssues(&projectIDAsObjectID, nil, nil)
+// SendIssueToTopOfBacklog sends an issue to the top of the backlog, ranking it before the other backlog issues.
+func (s *Storage) SendIssueToTopOfBacklog(projectID *string, issueID *string) error {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
 	if err != nil {
 		return err
 	}
 
-	updatesMap := make(map[primitive.ObjectID]interface{})
-	updatesMap[issueIDAsObjectID] = bson.M{
+	issueIDAsObjectID, err := primitive.ObjectIDFromHex(*issueID)
+	if err != nil {
+		return err
+	}
+
+	issue, err := s.repo.GetIssue(issueIDAsObjectID)
+	if err != nil {
+		return err
+	}
+
+	rank, err := s.getIssueListFirstRank(issueList{projectID: projectIDAsObjectID})
+	if err != nil {
+		return err
+	}
+
+	return s.sendIssueToBacklog(issue, rank)
+}
+
+func (s *Storage) sendIssueToBacklog(issue *Issue, rank string) error {
+	update := bson.M{
 		"$set": bson.M{
-			"ordinal":  int32(0),
+			"rank":     rank,
 			"sprintId": nil,
 		},
 	}
 
-	prevOrdinal := 1
-	for _, issue := range *issues {
-		if issue.ID != issueIDAsObjectID {
-			updatesMap[issue.ID] = bson.M{
-				"$set": bson.M{
-					"ordinal":  int32(prevOrdinal),
-					"sprintId": nil,
-				},
-			}
-			prevOrdinal++
-		}
-	}
-
-	err = s.repo.UpdateIssues(updatesMap)
+	err := s.repo.UpdateIssue(issue.ID, update)
 	if err != nil {
 		return err
 	}
 
-	if shouldCleanSprintOrdinals {
-		err = s.CleanSprintIssueOrdinals(&projectIDAsObjectID, &issue.SprintID)
-		if err != nil {
-			return err
-		}
+	err = s.recordIssueChanges([]Issue{*issue}, map[primitive.ObjectID]interface{}{issue.ID: update})
+	if err != nil {
+		return err
 	}
 
 	return nil
 }
 
-// SendSprintIssuesToBacklog ...
-func (s *Storage) SendSprintIssuesToBacklog(projectID *primitive.ObjectID, sprintID *primitive.ObjectID) error {
+// SendSprintIssuesToBacklog sends the issues of a sprint to the bottom of the backlog. Issues with a status in the
+// skip set, such as the done issues of a completed sprint, are left in the sprint.
+func (s *Storage) SendSprintIssuesToBacklog(projectID *primitive.ObjectID, sprintID *primitive.ObjectID, skip map[string]bool) error {
 	issues, _, err := s.repo.GetProjectSprintIssues(projectID, sprintID, nil, nil)
 	if err != nil {
 		return err
 	}
 
-	count, err := s.repo.CountProjectBacklogIssues(projectID)
+	moving := []Issue{}
+	for _, issue := range *issues {
+		if !skip[issue.Status] {
+			moving = append(moving, issue)
+		}
+	}
+
+	ranks, err := s.getIssueListLastRanks(issueList{projectID: *projectID}, len(moving))
 	if err != nil {
 		return err
 	}
 
 	updatesMap := make(map[primitive.ObjectID]interface{})
-	prevOrdinal := int32(count)
-
-	for _, issue := range *issues {
+	for n, issue := range moving {
 		updatesMap[issue.ID] = bson.M{
 			"$set": bson.M{
-				"ordinal":  int32(prevOrdinal),
+				"rank":     ranks[n],
 				"sprintId": nil,
 			},
 		}
-		prevOrdinal++
 	}
 
 	// Send all sprint issues to the bottom of the backlog
@@ -390,86 +664,304 @@ func (s *Storage) SendSprintIssuesToBacklog(projectID *primitive.ObjectID, sprin
 		return err
 	}
 
+	err = s.recordIssueChanges(*issues, updatesMap)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
-// CleanBacklogIssueOrdinals ...
-func (s *Storage) CleanBacklogIssueOrdinals(projectID *primitive.ObjectID) error {
-	slog.Infof("Cleaning backlog issue ordinals")
-	issues, _, err := s.repo.GetProjectBacklogIssues(projectID, nil, nil)
+// SendSprintIssuesToSprint sends the issues of a sprint to the bottom of another sprint. Issues with a status in the
+// skip set are left in the sprint.
+func (s *Storage) SendSprintIssuesToSprint(projectID *primitive.ObjectID, sprintID *primitive.ObjectID, targetSprintID *primitive.ObjectID, skip map[string]bool) error {
+	issues, _, err := s.repo.GetProjectSprintIssues(projectID, sprintID, nil, nil)
 	if err != nil {
 		return err
 	}
 
-	updatesMap := make(map[primitive.ObjectID]interface{})
-	prevOrdinal := 0
+	moving := []Issue{}
 	for _, issue := range *issues {
-		updatesMap[issue.ID] = bson.M{
-			"$set": bson.M{
-				"ordinal": int32(prevOrdinal),
-			},
+		if !skip[issue.Status] {
+			moving = append(moving, issue)
 		}
-		prevOrdinal++
-	}
-
-	// Send all sprint issues to the bottom of the backlog
-	err = s.repo.UpdateIssues(updatesMap)
-	if err != nil {
-		return err
 	}
 
-	return nil
-}
-
-// CleanSprintIssueOrdinals ...
-func (s *Storage) CleanSprintIssueOrdinals(projectID *primitive.ObjectID, sprintID *primitive.ObjectID) error {
-	issues, _, err := s.repo.GetProjectSprintIssues(projectID, sprintID, nil, nil)
+	ranks, err := s.getIssueListLastRanks(issueList{*projectID, *targetSprintID}, len(moving))
 	if err != nil {
 		return err
 	}
 
 	updatesMap := make(map[primitive.ObjectID]interface{})
-	for i, issue := range *issues {
+	for n, issue := range moving {
 		updatesMap[issue.ID] = bson.M{
 			"$set": bson.M{
-				"ordinal": int32(i),
+				"rank":     ranks[n],
+				"sprintId": targetSprintID,
 			},
 		}
 	}
 
-	// Send all sprint issues to the bottom of the backlog
 	err = s.repo.UpdateIssues(updatesMap)
 	if err != nil {
 		return err
 	}
 
+	err = s.recordIssueChanges(*issues, updatesMap)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
-// UpdateIssue updates an issue entity in the database's "issues" collection.
-func (s *Storage) UpdateIssue(projectID *string, issueID *string, i *updating.Issue) error {
-
+// StartProjectBoardSprint starts a future sprint child entity of a board in the database's "boards" collection. Unless
+// the board allows parallel sprints, no other sprint of the board can be active.
+func (s *Storage) StartProjectBoardSprint(projectID *string, boardID *string, sprintID *string, ss *updating.SprintStart) error {
 	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
 	if err != nil {
 		return err
 	}
 
-	issueIDAsObjectID, err := primitive.ObjectIDFromHex(*issueID)
+	boardIDAsObjectID, err := primitive.ObjectIDFromHex(*boardID)
 	if err != nil {
 		return err
 	}
 
-	// Load original issue
-	originalIssue, err := s.repo.GetIssue(issueIDAsObjectID)
+	sprintIDAsObjectID, err := primitive.ObjectIDFromHex(*sprintID)
 	if err != nil {
 		return err
 	}
 
-	assigneeIDAsObjectID, err := primitive.ObjectIDFromHex(i.AssigneeID)
+	board, err := s.getProjectBoard(projectIDAsObjectID, boardIDAsObjectID)
 	if err != nil {
 		return err
 	}
 
+	sprint, err := getBoardSprint(board, sprintIDAsObjectID)
+	if err != nil {
+		return err
+	}
+	if sprint.state() != sprintFuture {
+		return fmt.Errorf("Sprint %v has already been started", sprint.Name)
+	}
+
+	if !board.ParallelSprints {
+		for _, other := range board.Sprints {
+			if other.state() == sprintActive {
+				return fmt.Errorf("Sprint %v is already active on board %v", other.Name, board.Name)
+			}
+		}
+	}
+
+	startedSprint := bson.D{
+		{Key: "sprints.$.state", Value: sprintActive},
+		{Key: "sprints.$.startAt", Value: *ss.StartAt},
+		{Key: "sprints.$.endAt", Value: *ss.EndAt},
+		{Key: "sprints.$.startedAt", Value: time.Now()},
+	}
+
+	err = s.repo.UpdateBoardSprint(&boardIDAsObjectID, &sprintIDAsObjectID, &startedSprint)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UnreleaseProjectVersion returns a released version child entity of a project to unreleased in the database's
+// "projects" collection.
+func (s *Storage) UnreleaseProjectVersion(projectID *string, versionID *string) error {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return err
+	}
+
+	versionIDAsObjectID, err := primitive.ObjectIDFromHex(*versionID)
+	if err != nil {
+		return err
+	}
+
+	project, err := s.repo.GetProject(projectIDAsObjectID)
+	if err != nil {
+		return err
+	}
+
+	version, err := getProjectVersion(project, versionIDAsObjectID)
+	if err != nil {
+		return err
+	}
+	if version.Status != versionReleased {
+		return fmt.Errorf("Version %v is not released", version.Name)
+	}
+
+	set := bson.M{
+		"status":     versionUnreleased,
+		"releasedAt": nil,
+	}
+
+	err = s.repo.UpdateProjectVersion(projectIDAsObjectID, versionIDAsObjectID, set)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateBoardType updates a board type entity in the database's "board_templates" collection. Boards that pre-date
+// their own workflow reference use their board type's workflow, so it cannot be changed while any of them remain.
+func (s *Storage) UpdateBoardType(ID string, bt *updating.BoardType) error {
+	boardTemplate, err := s.repo.GetBoardTemplate(&ID)
+	if err != nil {
+		return fmt.Errorf("Board type %v not found", ID)
+	}
+
+	if _, err = s.repo.GetWorkflow(&bt.WorkflowID); err != nil {
+		return fmt.Errorf("Workflow %v not found", bt.WorkflowID)
+	}
+
+	if bt.WorkflowID != boardTemplate.WorkflowID {
+		filter := bson.M{
+			"type": ID,
+			"$or": []bson.M{
+				{"workflowId": bson.M{"$exists": false}},
+				{"workflowId": 0},
+			},
+		}
+		count, err := s.repo.CountBoards(filter)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("Cannot change the workflow of board type %v while %v boards depend on it", boardTemplate.Name, count)
+		}
+	}
+
+	set := bson.M{
+		"name":             bt.Name,
+		"isSprintable":     bt.IsSprintable,
+		"isBacklogVisible": bt.IsBacklogVisible,
+		"isBoardVisible":   bt.IsBoardVisible,
+		"workflowId":       bt.WorkflowID,
+		"isDefault":        bt.IsDefault,
+	}
+	update := bson.M{"$set": set}
+
+	if len(bt.Columns) > 0 {
+		// WIP limits copied from a board are kept for the columns of the same name
+		limits := make(map[string]BoardColumn)
+		for _, c := range boardTemplate.Columns {
+			limits[c.Name] = c
+		}
+
+		columns := []BoardColumn{}
+		for i, c := range bt.Columns {
+			column := BoardColumn{Name: c.Name, Ordinal: int32(i), IssueStatuses: c.IssueStatuses}
+			if l, ok := limits[c.Name]; ok {
+				column.MinWIP = l.MinWIP
+				column.MaxWIP = l.MaxWIP
+				column.WIPPolicy = l.WIPPolicy
+			}
+			columns = append(columns, column)
+		}
+		if err = s.checkBoardStatuses(bt.WorkflowID, columns, bt.UnmappedStatuses); err != nil {
+			return err
+		}
+		set["columns"] = columns
+		set["unmappedStatuses"] = bt.UnmappedStatuses
+	} else {
+		update["$unset"] = bson.M{"columns": "", "unmappedStatuses": ""}
+	}
+
+	err = s.repo.UpdateBoardTemplate(ID, update)
+	if err != nil {
+		return err
+	}
+
+	if bt.IsDefault {
+		return s.clearDefaultBoardTemplate(ID)
+	}
+
+	return nil
+}
+
+// UpdateCustomField updates a custom field entity in the database's "custom_fields" collection.
+func (s *Storage) UpdateCustomField(id string, cf *updating.CustomField) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	// The type of a custom field cannot be changed, so the stored type decides whether options are needed
+	current, err := s.repo.GetCustomField(objectID)
+	if err != nil {
+		return fmt.Errorf("Custom field %v not found", id)
+	}
+	if (current.Type == customFieldSelect || current.Type == customFieldMultiSelect) && len(cf.Options) == 0 {
+		return fmt.Errorf("'options' is empty")
+	}
+
+	projectIDs := []primitive.ObjectID{}
+	for _, id := range cf.ProjectIDs {
+		projectIDAsObjectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return err
+		}
+		projectIDs = append(projectIDs, projectIDAsObjectID)
+	}
+
+	issueTypes := cf.IssueTypes
+	if issueTypes == nil {
+		issueTypes = []string{}
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"name":        cf.Name,
+			"description": cf.Description,
+			"options":     cf.Options,
+			"isRequired":  cf.IsRequired,
+			"projectIds":  projectIDs,
+			"issueTypes":  issueTypes,
+			"updatedAt":   time.Now(),
+		},
+	}
+
+	err = s.repo.UpdateCustomField(objectID, update)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateIssue updates an issue entity in the database's "issues" collection.
+func (s *Storage) UpdateIssue(projectID *string, issueID *string, i *updating.Issue) error {
+
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return err
+	}
+
+	issueIDAsObjectID, err := primitive.ObjectIDFromHex(*issueID)
+	if err != nil {
+		return err
+	}
+
+	// Load original issue
+	originalIssue, err := s.repo.GetIssue(issueIDAsObjectID)
+	if err != nil {
+		return err
+	}
+
+	var assigneeIDAsObjectID = primitive.NilObjectID
+	if len(i.AssigneeID) > 0 {
+		assigneeIDAsObjectID, err = primitive.ObjectIDFromHex(i.AssigneeID)
+		if err != nil {
+			return err
+		}
+	}
+
 	var sprintIDAsObjectID = *&primitive.NilObjectID
 	if len(i.SprintID) > 0 {
 		sprintIDAsObjectID, err = primitive.ObjectIDFromHex(i.SprintID)
@@ -479,23 +971,107 @@ func (s *Storage) UpdateIssue(projectID *string, issueID *string, i *updating.Is
 	}
 
 	setMap := bson.M{
-		"type":        i.Type,
-		"summary":     i.Summary,
-		"description": i.Description,
-		"status":      i.Status,
-		"priority":    i.Priority,
-		"points":      i.Points,
-		"assigneeId":  assigneeIDAsObjectID,
-		"updatedAt":   time.Now(),
+		"type":             i.Type,
+		"summary":          i.Summary,
+		"description":      i.Description,
+		"status":           i.Status,
+		"priority":         i.Priority,
+		"points":           i.Points,
+		"originalEstimate": i.OriginalEstimate,
+		"assigneeId":       assigneeIDAsObjectID,
+		"updatedAt":        time.Now(),
 	}
 	unsetMap := bson.M{}
 
+	// Until work is logged, the remaining estimate follows the original estimate unless it is given
+	if i.RemainingEstimate != nil {
+		setMap["remainingEstimate"] = *i.RemainingEstimate
+	} else if originalIssue.TimeSpent == 0 && i.OriginalEstimate != originalIssue.OriginalEstimate {
+		setMap["remainingEstimate"] = i.OriginalEstimate
+	}
+
+	// A new due date clears the overdue flag, so that the issue is checked against it again
+	if i.DueDate != nil {
+		setMap["dueDate"] = *i.DueDate
+	} else {
+		unsetMap["dueDate"] = ""
+	}
+	if !sameDueDate(i.DueDate, originalIssue.DueDate) {
+		unsetMap["isOverdue"] = ""
+	}
+
+	if i.Type != originalIssue.Type {
+		project, err := s.repo.GetProject(originalIssue.ProjectID)
+		if err != nil {
+			return err
+		}
+		if err = s.checkIssueType(i.Type, project.IssueTypes); err != nil {
+			return err
+		}
+	}
+
+	if i.Components != nil || i.FixVersions != nil {
+		project, err := s.repo.GetProject(originalIssue.ProjectID)
+		if err != nil {
+			return err
+		}
+		if i.Components != nil {
+			components, err := getProjectComponentIDs(project, i.Components)
+			if err != nil {
+				return err
+			}
+			setMap["components"] = components
+		}
+		if i.FixVersions != nil {
+			fixVersions, err := getProjectVersionIDs(project, i.FixVersions, originalIssue.FixVersions)
+			if err != nil {
+				return err
+			}
+			setMap["fixVersions"] = fixVersions
+		}
+	}
+
+	if len(i.AddLabels) > 0 || len(i.RemoveLabels) > 0 {
+		if err = s.addMissingLabels(i.AddLabels); err != nil {
+			return err
+		}
+		setMap["labels"] = i.ApplyLabels(originalIssue.Labels)
+	}
+
+	if i.CustomFields != nil {
+		customFields, err := s.getIssueCustomFieldValues(originalIssue.ProjectID, i.Type, i.CustomFields)
+		if err != nil {
+			return err
+		}
+		setMap["customFields"] = customFields
+	}
+
 	if !sprintIDAsObjectID.IsZero() {
 		setMap["sprintId"] = sprintIDAsObjectID
 	} else {
 		unsetMap["sprintId"] = sprintIDAsObjectID
 	}
 
+	if len(i.EpicID) > 0 {
+		if i.Type == issueTypeEpic || i.EpicID == *issueID {
+			return fmt.Errorf("Epics cannot belong to an epic")
+		}
+		epicIDAsObjectID, err := s.getProjectEpicID(originalIssue.ProjectID, i.EpicID)
+		if err != nil {
+			return err
+		}
+		setMap["epicId"] = epicIDAsObjectID
+	} else {
+		unsetMap["epicId"] = ""
+	}
+
+	// Rank the issue at its ordinal position in its backlog or sprint
+	rank, err := s.getIssueListPositionRank(issueList{projectIDAsObjectID, sprintIDAsObjectID}, issueIDAsObjectID, i.Ordinal)
+	if err != nil {
+		return err
+	}
+	setMap["rank"] = rank
+
 	update := bson.M{
 		"$set": setMap,
 	}
@@ -508,93 +1084,162 @@ func (s *Storage) UpdateIssue(projectID *string, issueID *string, i *updating.Is
 		return err
 	}
 
-	slog.Infof("original ordinal: %v, new ordinal: %v", originalIssue.Ordinal, i.Ordinal)
+	err = s.recordIssueChanges([]Issue{*originalIssue}, map[primitive.ObjectID]interface{}{issueIDAsObjectID: update})
+	if err != nil {
+		return err
+	}
 
-	// Do the original and updating ordinals differ
-	// if originalIssue.Ordinal != i.Ordinal {
-	var siblingIssues *[]Issue
+	return nil
+}
 
-	if &i.SprintID != nil {
-		siblingIssues, _, err = s.repo.GetProjectSprintIssues(&projectIDAsObjectID, &sprintIDAsObjectID, nil, nil)
-	} else {
-		siblingIssues, _, err = s.repo.GetProjectBacklogIssues(&projectIDAsObjectID, nil, nil)
-	}
+// UpdateIssueOrdinals orders issues by their ordinal positions, in the database's "issues" collection. Issues already
+// in rank order keep their rank keys, so only the issues that moved are written.
+func (s *Storage) UpdateIssueOrdinals(projectID *string, issueOrdinals *[]updating.IssueOrdinal, transitions map[string]updating.TransitionIssue) error {
+	if len(*issueOrdinals) > 0 {
+		ordered := make([]updating.IssueOrdinal, len(*issueOrdinals))
+		copy(ordered, *issueOrdinals)
+		sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Ordinal < ordered[j].Ordinal })
 
-	updatesMap := make(map[primitive.ObjectID]interface{})
-	updatesMap[issueIDAsObjectID] = bson.M{
-		"$set": bson.M{
-			"ordinal": i.Ordinal,
-		},
-	}
-	slog.Infof("Adding %v with ordinal %v to updates map", issueIDAsObjectID.Hex(), i.Ordinal)
+		issueIDs := []primitive.ObjectID{}
+		for _, issueOrdinal := range ordered {
+			issueIDAsObjectID, err := primitive.ObjectIDFromHex(issueOrdinal.ID)
+			if err != nil {
+				return err
+			}
+			issueIDs = append(issueIDs, issueIDAsObjectID)
+		}
 
-	if len(*siblingIssues) > 1 {
-		directionOffset := -1
+		issues, err := s.repo.GetIssuesByIds(&issueIDs)
+		if err != nil {
+			return err
+		}
+
+		issuesMap := make(map[primitive.ObjectID]Issue)
+		for _, issue := range *issues {
+			issuesMap[issue.ID] = issue
+		}
 
-		for _, issue := range *siblingIssues {
-			if issue.ID == issueIDAsObjectID && issue.Ordinal > i.Ordinal {
-				directionOffset = 0
-				break
+		ranks := make([]string, len(issueIDs))
+		for n, id := range issueIDs {
+			issue, ok := issuesMap[id]
+			if !ok {
+				return fmt.Errorf("Issue %v not found", id.Hex())
 			}
+			ranks[n] = issue.Rank
 		}
 
-		ordinalOffset := 0
-		ordinal := 0
+		ranks, err = rerankIssues(ranks)
+		if err != nil {
+			return err
+		}
 
-		for _, issue := range *siblingIssues {
-			if ordinalOffset == 0 && (issue.Ordinal+int32(directionOffset)) >= i.Ordinal && issue.ID != issueIDAsObjectID {
-				ordinalOffset = 1
-			} else if ordinalOffset == 1 && issue.ID == issueIDAsObjectID {
-				ordinalOffset = 0
+		updatesMap := make(map[primitive.ObjectID]interface{})
+		changed := []Issue{}
+
+		for n, issueOrdinal := range ordered {
+			issue := issuesMap[issueIDs[n]]
+
+			setMap := bson.M{}
+			if ranks[n] != issue.Rank {
+				setMap["rank"] = ranks[n]
+			}
+			if issueOrdinal.Status != issue.Status {
+				setMap["status"] = issueOrdinal.Status
 			}
 
-			if issue.ID != issueIDAsObjectID {
-				slog.Infof("Adding %v with ordinal %v to updates map", issue.ID.Hex(), int32(ordinal+ordinalOffset))
-				updatesMap[issue.ID] = bson.M{
-					"$set": bson.M{
-						"ordinal": int32(ordinal + ordinalOffset),
-					},
+			if t, ok := transitions[issueOrdinal.ID]; ok {
+				transition, err := transitionUpdate(&t)
+				if err != nil {
+					return err
 				}
-				ordinal++
+				for k, v := range transition {
+					setMap[k] = v
+				}
+			}
+
+			if len(setMap) == 0 {
+				continue
 			}
+
+			updatesMap[issue.ID] = bson.M{
+				"$set": setMap,
+			}
+			changed = append(changed, issue)
+			s.checkIssueRanks(issueList{issue.ProjectID, issue.SprintID}, ranks[n])
 		}
-		// }
 
 		err = s.repo.UpdateIssues(updatesMap)
 		if err != nil {
 			return err
 		}
+
+		err = s.recordIssueChanges(changed, updatesMap)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-// UpdateIssueOrdinals ...
-func (s *Storage) UpdateIssueOrdinals(projectID *string, issueOrdinals *[]updating.IssueOrdinal) error {
-	if len(*issueOrdinals) > 0 {
-		updatesMap := make(map[primitive.ObjectID]interface{})
-
-		for _, issueOrdinal := range *issueOrdinals {
-			issueIDAsObjectID, err := primitive.ObjectIDFromHex(issueOrdinal.ID)
-			if err != nil {
-				return err
+// rerankIssues returns rank keys for issues in their new order. The longest run of issues whose current rank keys are
+// already in order keep them, and the other issues are ranked between their neighbours.
+func rerankIssues(current []string) ([]string, error) {
+	// lengths[i] is the length of the longest increasing run of rank keys ending at i, and prevs[i] its previous index
+	lengths := make([]int, len(current))
+	prevs := make([]int, len(current))
+	last := -1
+
+	for i := range current {
+		prevs[i] = -1
+		if len(current[i]) == 0 {
+			continue
+		}
+		lengths[i] = 1
+		for j := 0; j < i; j++ {
+			if lengths[j] > 0 && current[j] < current[i] && lengths[j]+1 > lengths[i] {
+				lengths[i] = lengths[j] + 1
+				prevs[i] = j
 			}
+		}
+		if last < 0 || lengths[i] > lengths[last] {
+			last = i
+		}
+	}
 
-			updatesMap[issueIDAsObjectID] = bson.M{
-				"$set": bson.M{
-					"ordinal": issueOrdinal.Ordinal,
-					"status":  issueOrdinal.Status,
-				},
-			}
+	keep := make([]bool, len(current))
+	for i := last; i >= 0; i = prevs[i] {
+		keep[i] = true
+	}
+
+	ranks := make([]string, len(current))
+	prev := ""
+	start := 0
+
+	for i := 0; i <= len(current); i++ {
+		if i < len(current) && !keep[i] {
+			continue
+		}
+
+		next := ""
+		if i < len(current) {
+			next = current[i]
 		}
 
-		err := s.repo.UpdateIssues(updatesMap)
+		keys, err := rankKeys(prev, next, i-start)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		copy(ranks[start:i], keys)
+
+		if i < len(current) {
+			ranks[i] = current[i]
+			prev = current[i]
 		}
+		start = i + 1
 	}
 
-	return nil
+	return ranks, nil
 }
 
 // UpdateIssueComment updates an issue comment entity in the database's "issue_comments" collection.
@@ -642,6 +1287,62 @@ func (s *Storage) UpdateIssueStatus(ID string, i *updating.IssueStatus) error {
 	return nil
 }
 
+// UpdateIssueType updates an issue type entity in the database's "issue_types" collection.
+func (s *Storage) UpdateIssueType(ID string, it *updating.IssueType) error {
+	update := bson.M{
+		"$set": bson.M{
+			"name":        it.Name,
+			"description": it.Description,
+			"icon":        it.Icon,
+			"isSubTask":   it.IsSubTask,
+		},
+	}
+
+	err := s.repo.UpdateIssueType(ID, update)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateLabel renames a label entity in the database's "labels" collection, and rewrites the issues using it.
+func (s *Storage) UpdateLabel(id string, l *updating.Label) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	label, err := s.repo.GetLabel(objectID)
+	if err != nil {
+		return fmt.Errorf("Label %v not found", id)
+	}
+
+	if label.Label == l.Label {
+		return nil
+	}
+
+	if existing, err := s.repo.GetLabelByValue(l.Label); err == nil && existing.ID != objectID {
+		return fmt.Errorf("Label %v already exists, merge the labels instead", l.Label)
+	}
+
+	err = s.repo.UpdateLabel(objectID, bson.M{"$set": bson.M{"label": l.Label}})
+	if err != nil {
+		return err
+	}
+
+	// Issue labels are unique, so the positional operator updates the only match
+	err = s.repo.UpdateManyIssues(
+		bson.M{"labels": label.Label},
+		bson.M{"$set": bson.M{"labels.$": l.Label, "updatedAt": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // UpdatePriorityType updates a priority type entity in the database's "priority_types" collection.
 func (s *Storage) UpdatePriorityType(ID string, pt *updating.PriorityType) error {
 	update := bson.M{
@@ -686,6 +1387,211 @@ func (s *Storage) UpdateProject(id string, p *updating.Project) error {
 	return nil
 }
 
+// UpdateProjectDefaultBoard sets the default board of a project entity in the database's "projects" collection. The
+// board must be one of the project's boards.
+func (s *Storage) UpdateProjectDefaultBoard(projectID *string, boardID *string) error {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return err
+	}
+
+	boardIDAsObjectID, err := primitive.ObjectIDFromHex(*boardID)
+	if err != nil {
+		return err
+	}
+
+	if _, err = s.getProjectBoard(projectIDAsObjectID, boardIDAsObjectID); err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"defaultBoardId": boardIDAsObjectID,
+			"updatedAt":      time.Now(),
+		},
+	}
+
+	err = s.repo.UpdateProject(projectIDAsObjectID, update)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateProjectComponent updates a component child entity of a project in the database's "projects" collection.
+func (s *Storage) UpdateProjectComponent(projectID *string, componentID *string, c *updating.Component) error {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return err
+	}
+
+	componentIDAsObjectID, err := primitive.ObjectIDFromHex(*componentID)
+	if err != nil {
+		return err
+	}
+
+	var leadIDAsObjectID primitive.ObjectID
+	if len(c.LeadID) > 0 {
+		if leadIDAsObjectID, err = primitive.ObjectIDFromHex(c.LeadID); err != nil {
+			return err
+		}
+		if _, err = s.repo.GetUserByID(&leadIDAsObjectID); err != nil {
+			return fmt.Errorf("User %v not found", c.LeadID)
+		}
+	}
+
+	set := bson.M{
+		"name":         c.Name,
+		"description":  c.Description,
+		"leadId":       leadIDAsObjectID,
+		"assigneeType": c.AssigneeType,
+	}
+
+	err = s.repo.UpdateProjectComponent(projectIDAsObjectID, componentIDAsObjectID, set)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateProjectIssueTypeScheme replaces the issue types allowed by a project entity in the database's "projects"
+// collection.
+func (s *Storage) UpdateProjectIssueTypeScheme(id string, its *updating.ProjectIssueTypeScheme) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	issueTypes := []string{}
+	for _, it := range its.IssueTypes {
+		if err = s.checkIssueType(it, nil); err != nil {
+			return err
+		}
+		issueTypes = append(issueTypes, it)
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"issueTypes": issueTypes,
+			"updatedAt":  time.Now(),
+		},
+	}
+
+	err = s.repo.UpdateProject(objectID, update)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateProjectBoard updates a board entity of a project in the database's "boards" collection. Replacing the columns
+// of a board records the change in the database's "board_column_changes" collection, and keeps the WIP limits of the
+// columns that keep their name.
+func (s *Storage) UpdateProjectBoard(projectID *string, boardID *string, b *updating.Board) error {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return err
+	}
+
+	boardIDAsObjectID, err := primitive.ObjectIDFromHex(*boardID)
+	if err != nil {
+		return err
+	}
+
+	board, err := s.getProjectBoard(projectIDAsObjectID, boardIDAsObjectID)
+	if err != nil {
+		return err
+	}
+
+	filters := []BoardFilter{}
+	for _, f := range b.Filters {
+		filters = append(filters, BoardFilter{Field: f.Field, Value: f.Value})
+	}
+	if _, err = s.getBoardIssueQuery(&Board{Filters: filters}); err != nil {
+		return err
+	}
+
+	set := bson.M{
+		"name":             b.Name,
+		"description":      b.Description,
+		"isBacklogVisible": b.IsBacklogVisible,
+		"isBoardVisible":   b.IsBoardVisible,
+		"parallelSprints":  b.ParallelSprints,
+		"filters":          filters,
+		"updatedAt":        time.Now(),
+	}
+
+	if len(b.Columns) == 0 {
+		return s.repo.UpdateBoard(boardIDAsObjectID, bson.M{"$set": set})
+	}
+
+	limits := make(map[string]BoardColumn)
+	for _, c := range board.Columns {
+		limits[c.Name] = c
+	}
+
+	columns := []updating.BoardColumn{}
+	for _, c := range b.Columns {
+		if l, ok := limits[c.Name]; ok {
+			c.MinWIP = l.MinWIP
+			c.MaxWIP = l.MaxWIP
+			c.WIPPolicy = l.WIPPolicy
+		}
+		columns = append(columns, c)
+	}
+
+	return s.updateBoardColumns(board, columns, b.UnmappedStatuses, set)
+}
+
+// UpdateProjectBoardColumnWIPLimit updates the WIP limits of a column of a board in the database's "boards" collection.
+// Columns are identified by their ordinal position.
+func (s *Storage) UpdateProjectBoardColumnWIPLimit(projectID *string, boardID *string, ordinal int32, l *updating.WIPLimit) error {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return err
+	}
+
+	boardIDAsObjectID, err := primitive.ObjectIDFromHex(*boardID)
+	if err != nil {
+		return err
+	}
+
+	board, err := s.getProjectBoard(projectIDAsObjectID, boardIDAsObjectID)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for i := range board.Columns {
+		if board.Columns[i].Ordinal == ordinal {
+			board.Columns[i].MinWIP = l.MinWIP
+			board.Columns[i].MaxWIP = l.MaxWIP
+			board.Columns[i].WIPPolicy = l.Policy
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("Column %v not found for board %v", ordinal, board.Name)
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"columns":   board.Columns,
+			"updatedAt": time.Now(),
+		},
+	}
+
+	err = s.repo.UpdateBoard(boardIDAsObjectID, update)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // UpdateProjectBoardSprint updates a sprint child entity of a target board in the database's "boards" collection.
 func (s *Storage) UpdateProjectBoardSprint(projectID *string, boardID *string, sprintID *string, sprint *updating.Sprint) error {
 	var err error
@@ -750,6 +1656,92 @@ func (s *Storage) UpdateProjectBoardSprint(projectID *string, boardID *string, s
 	return nil
 }
 
+// UpdateProjectVersion updates a version child entity of a project in the database's "projects" collection.
+// Archiving a version keeps its release state, which is restored when it is unarchived.
+func (s *Storage) UpdateProjectVersion(projectID *string, versionID *string, v *updating.Version) error {
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return err
+	}
+
+	versionIDAsObjectID, err := primitive.ObjectIDFromHex(*versionID)
+	if err != nil {
+		return err
+	}
+
+	project, err := s.repo.GetProject(projectIDAsObjectID)
+	if err != nil {
+		return err
+	}
+
+	version, err := getProjectVersion(project, versionIDAsObjectID)
+	if err != nil {
+		return err
+	}
+
+	status := version.Status
+	if v.IsArchived {
+		status = versionArchived
+	} else if status == versionArchived {
+		status = versionUnreleased
+		if version.ReleasedAt != nil {
+			status = versionReleased
+		}
+	}
+
+	set := bson.M{
+		"name":        v.Name,
+		"description": v.Description,
+		"status":      status,
+		"startAt":     v.StartAt,
+		"releaseAt":   v.ReleaseAt,
+	}
+
+	err = s.repo.UpdateProjectVersion(projectIDAsObjectID, versionIDAsObjectID, set)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateWorkflowTransitions replaces the transitions of a workflow entity in the database's "workflows" collection.
+func (s *Storage) UpdateWorkflowTransitions(workflowID int32, ts []updating.WorkflowTransition) error {
+	transformRules := func(rs []updating.TransitionRule) []TransitionRule {
+		rules := []TransitionRule{}
+		for _, r := range rs {
+			rules = append(rules, TransitionRule{Type: r.Type, Params: r.Params})
+		}
+		return rules
+	}
+
+	transitions := []WorkflowTransition{}
+	for _, t := range ts {
+		transition := WorkflowTransition{
+			Name:          t.Name,
+			FromStatusIDs: t.FromStatusIDs,
+			ToStatusID:    t.ToStatusID,
+			Conditions:    transformRules(t.Conditions),
+			Validators:    transformRules(t.Validators),
+			PostFunctions: transformRules(t.PostFunctions),
+		}
+		transitions = append(transitions, transition)
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"transitions": transitions,
+		},
+	}
+
+	err := s.repo.UpdateWorkflow(workflowID, update)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // UpdateProjectCounter updates a project counter entity in the database's "project_counters" collection.
 func (s *Storage) UpdateProjectCounter(ID string) (int64, error) {
 	var count int64
@@ -774,3 +1766,17 @@ func (s *Storage) UpdateProjectCounter(ID string) (int64, error) {
 
 	return count, err
 }
+
+// addMissingLabels adds label entities for any of a set of labels not yet in the database's "labels" collection.
+func (s *Storage) addMissingLabels(values []string) error {
+	var newLabels []Label
+
+	for _, v := range values {
+		if _, err := s.repo.GetLabelByValue(v); err == nil {
+			continue
+		}
+		newLabels = append(newLabels, Label{Label: v})
+	}
+
+	return s.repo.AddLabels(&newLabels)
+}
@@ -78,6 +78,34 @@ func (r *Repository) GetUserByEmail(email *string) (*User, error) {
 	return &u, nil
 }
 
+// GetUsersByID ...
+func (r *Repository) GetUsersByID(ids []primitive.ObjectID) (*[]User, error) {
+	var users = []User{}
+
+	collection := r.db.Collection("users")
+
+	filter := bson.M{"_id": bson.M{"$in": ids}}
+
+	cur, err := collection.Find(context.Background(), filter)
+	if err != nil {
+		return &users, err
+	}
+	defer cur.Close(context.Background())
+
+	for cur.Next(context.Background()) {
+		var u User
+
+		err = cur.Decode(&u)
+		if err != nil {
+			return &users, err
+		}
+
+		users = append(users, u)
+	}
+
+	return &users, nil
+}
+
 // GetUsers ...
 func (r *Repository) GetUsers(term *string) (*[]User, error) {
 	var users = []User{}
@@ -0,0 +1,165 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/njehyde/issue-tracker/libraries/slog"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Version defines the storage form of a project version entity.
+type Version struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	Name        string             `bson:"name"`
+	Description string             `bson:"description"`
+	Status      string             `bson:"status"`
+	StartAt     *time.Time         `bson:"startAt,omitempty"`
+	ReleaseAt   *time.Time         `bson:"releaseAt,omitempty"`
+	ReleasedAt  *time.Time         `bson:"releasedAt,omitempty"`
+	CreatedAt   time.Time          `bson:"createdAt"`
+	UpdatedAt   time.Time          `bson:"updatedAt"`
+}
+
+// Version statuses.
+const (
+	versionUnreleased = "UNRELEASED"
+	versionReleased   = "RELEASED"
+	versionArchived   = "ARCHIVED"
+)
+
+// AddProjectVersion ...
+func (r *Repository) AddProjectVersion(projectID primitive.ObjectID, v *Version) error {
+	collection := r.db.Collection("projects")
+
+	now := time.Now()
+
+	v.ID = primitive.NewObjectID()
+	v.CreatedAt = now
+	v.UpdatedAt = now
+
+	filter := bson.M{"_id": projectID}
+
+	update := bson.M{
+		"$set": bson.M{
+			"updatedAt": now,
+		},
+		"$push": bson.M{
+			"versions": v,
+		},
+	}
+
+	updateResult, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return fmt.Errorf("Project %v not found", projectID.Hex())
+	}
+
+	slog.Infof("Added version %v via update to project %v: %+v", v.ID.Hex(), projectID.Hex(), updateResult)
+
+	return nil
+}
+
+// DeleteProjectVersion ...
+func (r *Repository) DeleteProjectVersion(projectID primitive.ObjectID, versionID primitive.ObjectID) error {
+	collection := r.db.Collection("projects")
+
+	filter := bson.M{"_id": projectID, "versions._id": versionID}
+
+	update := bson.M{
+		"$set": bson.M{
+			"updatedAt": time.Now(),
+		},
+		"$pull": bson.M{
+			"versions": bson.M{
+				"_id": versionID,
+			},
+		},
+	}
+
+	updateResult, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return fmt.Errorf("Version %v not found for project %v", versionID.Hex(), projectID.Hex())
+	}
+
+	slog.Infof("Deleted version %v via update to project %v: %+v", versionID.Hex(), projectID.Hex(), updateResult)
+
+	return nil
+}
+
+// UpdateProjectVersion ...
+func (r *Repository) UpdateProjectVersion(projectID primitive.ObjectID, versionID primitive.ObjectID, set primitive.M) error {
+	collection := r.db.Collection("projects")
+
+	now := time.Now()
+
+	filter := bson.M{"_id": projectID, "versions._id": versionID}
+
+	update := bson.M{"$set": bson.M{"updatedAt": now, "versions.$.updatedAt": now}}
+	for k, v := range set {
+		update["$set"].(bson.M)["versions.$."+k] = v
+	}
+
+	updateResult, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return fmt.Errorf("Version %v not found for project %v", versionID.Hex(), projectID.Hex())
+	}
+
+	slog.Infof("Updated version %v via update to project %v: %+v", versionID.Hex(), projectID.Hex(), updateResult)
+
+	return nil
+}
+
+// getProjectVersion returns a version of a project, or an error where the version does not belong to the project.
+func getProjectVersion(p *Project, versionID primitive.ObjectID) (*Version, error) {
+	for i := range p.Versions {
+		if p.Versions[i].ID == versionID {
+			return &p.Versions[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("Version %v not found for project %v", versionID.Hex(), p.Key)
+}
+
+// getProjectVersionIDs returns a set of fix version ids as ObjectIDs, or an error where a version does not belong to
+// the project or has been archived. Archived versions an issue already has, given as existing, can be kept.
+func getProjectVersionIDs(p *Project, ids []string, existing []primitive.ObjectID) ([]primitive.ObjectID, error) {
+	results := []primitive.ObjectID{}
+
+	kept := make(map[primitive.ObjectID]bool)
+	for _, id := range existing {
+		kept[id] = true
+	}
+
+	for _, id := range ids {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return results, err
+		}
+
+		v, err := getProjectVersion(p, objectID)
+		if err != nil {
+			return results, err
+		}
+		if v.Status == versionArchived && !kept[objectID] {
+			return results, fmt.Errorf("Version %v is archived", v.Name)
+		}
+
+		results = append(results, objectID)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,194 @@
+package mongo
+
+import (
+	"github.com/njehyde/issue-tracker/pkg/updating"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// getBoardActiveSprints returns the active sprints of a sprintable board, whose issues are the only ones on it. It
+// returns nil for boards without sprints, which show every issue of their project.
+func getBoardActiveSprints(b *Board) map[primitive.ObjectID]bool {
+	if b.Sprints == nil {
+		return nil
+	}
+
+	results := make(map[primitive.ObjectID]bool)
+	for _, sprint := range b.Sprints {
+		if sprint.state() == sprintActive {
+			results[sprint.ID] = true
+		}
+	}
+
+	return results
+}
+
+// getBoardStatusesQuery returns the query for the issues on a board that have one of a set of statuses.
+func (s *Storage) getBoardStatusesQuery(b *Board, statuses []string, activeSprints map[primitive.ObjectID]bool) (bson.M, error) {
+	query, err := s.getBoardIssueQuery(b)
+	if err != nil {
+		return nil, err
+	}
+
+	query["status"] = bson.M{"$in": statuses}
+
+	if activeSprints != nil {
+		sprintIDs := []primitive.ObjectID{}
+		for id := range activeSprints {
+			sprintIDs = append(sprintIDs, id)
+		}
+		query["sprintId"] = bson.M{"$in": sprintIDs}
+	}
+
+	return query, nil
+}
+
+// countBoardColumnIssues counts the issues of a project that are in a column of one of its boards.
+func (s *Storage) countBoardColumnIssues(projectID primitive.ObjectID, b *Board, c *BoardColumn, activeSprints map[primitive.ObjectID]bool) (int64, error) {
+	query, err := s.getBoardStatusesQuery(b, c.IssueStatuses, activeSprints)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.repo.CountProjectIssues(&projectID, query)
+}
+
+// GetWIPBreaches returns the columns of a project's boards, in the database's "boards" collection, that a set of issue
+// moves would take over their maximum WIP limits.
+func (s *Storage) GetWIPBreaches(projectID *string, moves []updating.IssueMove) ([]updating.WIPBreach, error) {
+	results := []updating.WIPBreach{}
+	if len(moves) == 0 {
+		return results, nil
+	}
+
+	projectIDAsObjectID, err := primitive.ObjectIDFromHex(*projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := s.repo.GetProject(projectIDAsObjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	boards, err := s.repo.GetBoardsByIds(&project.Boards)
+	if err != nil {
+		return nil, err
+	}
+
+	issueIDs := []primitive.ObjectID{}
+	for _, m := range moves {
+		issueIDAsObjectID, err := primitive.ObjectIDFromHex(m.IssueID)
+		if err != nil {
+			return nil, err
+		}
+		issueIDs = append(issueIDs, issueIDAsObjectID)
+	}
+
+	issues, err := s.repo.GetIssuesByIds(&issueIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	issuesByID := make(map[string]*Issue)
+	for i := range *issues {
+		issuesByID[(*issues)[i].ID.Hex()] = &(*issues)[i]
+	}
+
+	for _, b := range *boards {
+		activeSprints := getBoardActiveSprints(&b)
+
+		// Only the moved issues that match the board's filters can change its column counts
+		matching := issuesByID
+		if len(b.Filters) > 0 {
+			query, err := s.getBoardIssueQuery(&b)
+			if err != nil {
+				return nil, err
+			}
+			query["_id"] = bson.M{"$in": issueIDs}
+
+			boardIssues, _, err := s.repo.GetProjectIssues(&projectIDAsObjectID, nil, 0, query, nil)
+			if err != nil {
+				return nil, err
+			}
+
+			matching = make(map[string]*Issue)
+			for i := range *boardIssues {
+				matching[(*boardIssues)[i].ID.Hex()] = &(*boardIssues)[i]
+			}
+		}
+
+		onBoard := func(sprintID primitive.ObjectID) bool {
+			return activeSprints == nil || activeSprints[sprintID]
+		}
+
+		for i := range b.Columns {
+			c := &b.Columns[i]
+			if c.MaxWIP <= 0 {
+				continue
+			}
+
+			inColumn := make(map[string]bool)
+			for _, status := range c.IssueStatuses {
+				inColumn[status] = true
+			}
+
+			var delta int64
+			for _, m := range moves {
+				issue, ok := matching[m.IssueID]
+				if !ok {
+					continue
+				}
+
+				status := issue.Status
+				if len(m.Status) > 0 {
+					status = m.Status
+				}
+
+				sprintID := issue.SprintID
+				if m.SprintID != nil {
+					sprintID = primitive.NilObjectID
+					if len(*m.SprintID) > 0 {
+						sprintID, err = primitive.ObjectIDFromHex(*m.SprintID)
+						if err != nil {
+							return nil, err
+						}
+					}
+				}
+
+				if onBoard(issue.SprintID) && inColumn[issue.Status] {
+					delta--
+				}
+				if onBoard(sprintID) && inColumn[status] {
+					delta++
+				}
+			}
+
+			if delta <= 0 {
+				continue
+			}
+
+			count, err := s.countBoardColumnIssues(projectIDAsObjectID, &b, c, activeSprints)
+			if err != nil {
+				return nil, err
+			}
+
+			if count+delta > int64(c.MaxWIP) {
+				policy := c.WIPPolicy
+				if len(policy) == 0 {
+					policy = updating.WIPPolicyFlag
+				}
+
+				results = append(results, updating.WIPBreach{
+					BoardID: b.ID.Hex(),
+					Column:  c.Name,
+					Count:   count + delta,
+					MaxWIP:  c.MaxWIP,
+					Policy:  policy,
+				})
+			}
+		}
+	}
+
+	return results, nil
+}
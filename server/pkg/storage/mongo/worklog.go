@@ -0,0 +1,425 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/njehyde/issue-tracker/libraries/slog"
+	"github.com/njehyde/issue-tracker/pkg/adding"
+	"github.com/njehyde/issue-tracker/pkg/listing"
+	"github.com/njehyde/issue-tracker/pkg/updating"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// IssueWorklog defines the storage form of an issue work log entry. Durations are in seconds.
+type IssueWorklog struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	IssueID   primitive.ObjectID `bson:"issueId"`
+	ProjectID primitive.ObjectID `bson:"projectId"`
+	UserID    primitive.ObjectID `bson:"userId"`
+	StartedAt time.Time          `bson:"startedAt"`
+	Duration  int64              `bson:"duration"`
+	Comment   string             `bson:"comment,omitempty"`
+	CreatedAt time.Time          `bson:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt"`
+}
+
+// AddIssueWorklog ...
+func (r *Repository) AddIssueWorklog(w *IssueWorklog) error {
+	collection := r.db.Collection("issue_worklogs")
+
+	now := time.Now()
+
+	w.ID = primitive.NewObjectID()
+	w.CreatedAt = now
+	w.UpdatedAt = now
+
+	insertResult, err := collection.InsertOne(context.Background(), w)
+	if err != nil {
+		return err
+	}
+
+	slog.Infof("Added issue worklog %v: %+v", w.ID.Hex(), insertResult)
+
+	return nil
+}
+
+// DeleteIssueWorklog ...
+func (r *Repository) DeleteIssueWorklog(issueID *primitive.ObjectID, worklogID *primitive.ObjectID) error {
+	collection := r.db.Collection("issue_worklogs")
+
+	filter := bson.M{"_id": worklogID, "issueId": issueID}
+
+	deleteResult, err := collection.DeleteOne(context.Background(), filter)
+	if err != nil {
+		return err
+	}
+
+	if deleteResult.DeletedCount == 0 {
+		return fmt.Errorf("Issue worklog could not be deleted")
+	}
+
+	slog.Infof("Deleted issue worklog %v of issue %v: %+v", worklogID, issueID, deleteResult)
+
+	return nil
+}
+
+// DeleteIssueWorklogs ...
+func (r *Repository) DeleteIssueWorklogs(issueID primitive.ObjectID) error {
+	collection := r.db.Collection("issue_worklogs")
+
+	deleteResult, err := collection.DeleteMany(context.Background(), bson.M{"issueId": issueID})
+	if err != nil {
+		return err
+	}
+
+	slog.Infof("Deleted %v worklogs of issue %v", deleteResult.DeletedCount, issueID.Hex())
+
+	return nil
+}
+
+// GetIssueWorklog ...
+func (r *Repository) GetIssueWorklog(issueID *primitive.ObjectID, worklogID *primitive.ObjectID) (*IssueWorklog, error) {
+	var w IssueWorklog
+
+	collection := r.db.Collection("issue_worklogs")
+
+	filter := bson.M{"_id": worklogID, "issueId": issueID}
+
+	err := collection.FindOne(context.Background(), filter).Decode(&w)
+	if err == mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("Worklog %v not found for issue %v", worklogID.Hex(), issueID.Hex())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &w, nil
+}
+
+// GetIssueWorklogs ...
+func (r *Repository) GetIssueWorklogs(issueID *primitive.ObjectID, cursor *primitive.ObjectID, limit int64) (*[]IssueWorklog, int64, error) {
+	var worklogs []IssueWorklog
+	var count int64
+
+	collection := r.db.Collection("issue_worklogs")
+
+	filter := bson.M{"issueId": issueID}
+
+	count, err := collection.CountDocuments(context.Background(), filter)
+	if err != nil {
+		return &worklogs, count, err
+	}
+
+	if cursor != nil && !cursor.IsZero() {
+		filter["_id"] = bson.M{"$gt": cursor}
+	}
+
+	findOptions := options.Find().SetLimit(limit).SetSort(
+		bson.D{
+			primitive.E{Key: "_id", Value: 1},
+		},
+	)
+
+	cur, err := collection.Find(context.Background(), filter, findOptions)
+	if err != nil {
+		return &worklogs, count, err
+	}
+	defer cur.Close(context.Background())
+
+	for cur.Next(context.Background()) {
+		var w IssueWorklog
+
+		err = cur.Decode(&w)
+		if err != nil {
+			return &worklogs, count, err
+		}
+
+		worklogs = append(worklogs, w)
+	}
+
+	return &worklogs, count, nil
+}
+
+// GetWorklogs ...
+func (r *Repository) GetWorklogs(filter bson.M) (*[]IssueWorklog, error) {
+	var worklogs []IssueWorklog
+
+	collection := r.db.Collection("issue_worklogs")
+
+	findOptions := options.Find().SetSort(
+		bson.D{
+			primitive.E{Key: "startedAt", Value: 1},
+		},
+	)
+
+	cur, err := collection.Find(context.Background(), filter, findOptions)
+	if err != nil {
+		return &worklogs, err
+	}
+	defer cur.Close(context.Background())
+
+	for cur.Next(context.Background()) {
+		var w IssueWorklog
+
+		err = cur.Decode(&w)
+		if err != nil {
+			return &worklogs, err
+		}
+
+		worklogs = append(worklogs, w)
+	}
+
+	return &worklogs, nil
+}
+
+// UpdateManyIssueWorklogs ...
+func (r *Repository) UpdateManyIssueWorklogs(filter primitive.M, update primitive.M) error {
+	collection := r.db.Collection("issue_worklogs")
+
+	updateResult, err := collection.UpdateMany(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	slog.Infof("Updated %v issue worklogs", updateResult.ModifiedCount)
+
+	return nil
+}
+
+// UpdateIssueWorklog ...
+func (r *Repository) UpdateIssueWorklog(issueID *primitive.ObjectID, worklogID *primitive.ObjectID, update *primitive.M) error {
+	collection := r.db.Collection("issue_worklogs")
+
+	filter := bson.M{"_id": worklogID, "issueId": issueID}
+
+	updateResult, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return fmt.Errorf("Worklog %v not found for issue %v", worklogID.Hex(), issueID.Hex())
+	}
+
+	slog.Infof("Updated issue worklog %v for issue %v: %+v", worklogID.Hex(), issueID.Hex(), updateResult)
+
+	return nil
+}
+
+// AddIssueWorklog adds an issue worklog to the database's "issue_worklogs" collection, and adds its duration to the
+// time spent on the issue.
+func (s *Storage) AddIssueWorklog(issueID *string, userID *string, w *adding.IssueWorklog) error {
+	issueIDAsObjectID, err := primitive.ObjectIDFromHex(*issueID)
+	if err != nil {
+		return err
+	}
+
+	userIDAsObjectID, err := primitive.ObjectIDFromHex(*userID)
+	if err != nil {
+		return err
+	}
+
+	issue, err := s.repo.GetIssue(issueIDAsObjectID)
+	if err != nil {
+		return err
+	}
+
+	newWorklog := IssueWorklog{
+		IssueID:   issueIDAsObjectID,
+		ProjectID: issue.ProjectID,
+		UserID:    userIDAsObjectID,
+		StartedAt: w.StartedAt,
+		Duration:  w.Duration,
+		Comment:   w.Comment,
+	}
+
+	err = s.repo.AddIssueWorklog(&newWorklog)
+	if err != nil {
+		return err
+	}
+
+	return s.logIssueTime(issue, w.Duration, w.RemainingEstimate)
+}
+
+// DeleteIssueWorklog deletes an issue worklog from the database's "issue_worklogs" collection, and takes its duration
+// off the time spent on the issue. Only the user who logged the work can delete it.
+func (s *Storage) DeleteIssueWorklog(issueID *string, worklogID *string, userID *string) error {
+	issueIDAsObjectID, err := primitive.ObjectIDFromHex(*issueID)
+	if err != nil {
+		return err
+	}
+
+	worklogIDAsObjectID, err := primitive.ObjectIDFromHex(*worklogID)
+	if err != nil {
+		return err
+	}
+
+	worklog, err := s.repo.GetIssueWorklog(&issueIDAsObjectID, &worklogIDAsObjectID)
+	if err != nil {
+		return err
+	}
+	if worklog.UserID.Hex() != *userID {
+		return fmt.Errorf("Only the user who logged work can delete it")
+	}
+
+	issue, err := s.repo.GetIssue(issueIDAsObjectID)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.DeleteIssueWorklog(&issueIDAsObjectID, &worklogIDAsObjectID)
+	if err != nil {
+		return err
+	}
+
+	return s.logIssueTime(issue, -worklog.Duration, nil)
+}
+
+// GetIssueWorklogs returns a paginated slice of issue worklog entities, in the order they were logged, from the
+// database's "issue_worklogs" collection.
+func (s *Storage) GetIssueWorklogs(issueID *string, p *listing.Pagination) (results []listing.IssueWorklog, count int64, err error) {
+	var cursor primitive.ObjectID
+
+	if len(p.Cursor) > 0 {
+		cursor, err = primitive.ObjectIDFromHex(p.Cursor)
+		if err != nil {
+			return results, count, err
+		}
+	}
+
+	issueIDAsObjectID, err := primitive.ObjectIDFromHex(*issueID)
+	if err != nil {
+		return results, count, err
+	}
+
+	worklogs, count, err := s.repo.GetIssueWorklogs(&issueIDAsObjectID, &cursor, int64(p.PageSize))
+	if err != nil {
+		return results, count, err
+	}
+
+	usersMap := map[primitive.ObjectID]listing.User{}
+	results = make([]listing.IssueWorklog, 0)
+
+	for _, w := range *worklogs {
+		if _, ok := usersMap[w.UserID]; !ok {
+			u, err := s.repo.GetUserByID(&w.UserID)
+			if err != nil {
+				return results, count, err
+			}
+			usersMap[w.UserID] = listing.User{
+				ID:    u.ID.Hex(),
+				Email: u.Email,
+				Name: listing.UserName{
+					FirstName: u.Name.FirstName,
+					LastName:  u.Name.LastName,
+				},
+			}
+		}
+
+		results = append(results, listing.IssueWorklog{
+			ID:        w.ID.Hex(),
+			User:      usersMap[w.UserID],
+			StartedAt: w.StartedAt,
+			Duration:  w.Duration,
+			Comment:   w.Comment,
+			CreatedAt: w.CreatedAt,
+			UpdatedAt: w.UpdatedAt,
+		})
+	}
+
+	if len(results) > 0 {
+		p.Cursor = results[len(results)-1].ID
+	}
+
+	return results, count, nil
+}
+
+// UpdateIssueWorklog updates an issue worklog entity in the database's "issue_worklogs" collection, and the time spent
+// on the issue by the change in its duration. Only the user who logged the work can update it.
+func (s *Storage) UpdateIssueWorklog(issueID *string, worklogID *string, userID *string, w *updating.IssueWorklog) error {
+	issueIDAsObjectID, err := primitive.ObjectIDFromHex(*issueID)
+	if err != nil {
+		return err
+	}
+
+	worklogIDAsObjectID, err := primitive.ObjectIDFromHex(*worklogID)
+	if err != nil {
+		return err
+	}
+
+	worklog, err := s.repo.GetIssueWorklog(&issueIDAsObjectID, &worklogIDAsObjectID)
+	if err != nil {
+		return err
+	}
+	if worklog.UserID.Hex() != *userID {
+		return fmt.Errorf("Only the user who logged work can update it")
+	}
+
+	issue, err := s.repo.GetIssue(issueIDAsObjectID)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"startedAt": w.StartedAt,
+			"duration":  w.Duration,
+			"comment":   w.Comment,
+			"updatedAt": time.Now(),
+		},
+	}
+
+	err = s.repo.UpdateIssueWorklog(&issueIDAsObjectID, &worklogIDAsObjectID, &update)
+	if err != nil {
+		return err
+	}
+
+	return s.logIssueTime(issue, w.Duration-worklog.Duration, w.RemainingEstimate)
+}
+
+// issueTimeAttempts is the number of times time is logged against an issue whose remaining estimate was changed by
+// work logged at the same time.
+const issueTimeAttempts = 3
+
+// logIssueTime adds time to the time spent on an issue, and sets its remaining estimate. Without a remaining estimate
+// the time is taken off the issue's remaining estimate, which never goes below zero. That remaining estimate is only
+// written while the issue keeps the one it was worked out from, so that work logged at the same time is never lost.
+func (s *Storage) logIssueTime(issue *Issue, spent int64, remaining *int64) error {
+	for attempt := 1; attempt <= issueTimeAttempts; attempt++ {
+		if attempt > 1 {
+			var err error
+			issue, err = s.repo.GetIssue(issue.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		remainingEstimate := issue.RemainingEstimate - spent
+		if remaining != nil {
+			remainingEstimate = *remaining
+		}
+		if remainingEstimate < 0 {
+			remainingEstimate = 0
+		}
+
+		update := bson.M{
+			"$inc": bson.M{"timeSpent": spent},
+			"$set": bson.M{"remainingEstimate": remainingEstimate},
+		}
+
+		ok, err := s.repo.UpdateIssueIfRemainingEstimate(issue.ID, issue.RemainingEstimate, update)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return s.recordIssueChanges([]Issue{*issue}, map[primitive.ObjectID]interface{}{issue.ID: update})
+		}
+	}
+
+	return fmt.Errorf("Issue %v was changed while its time was logged, please try again", issue.ProjectRef)
+}
@@ -1,6 +1,105 @@
 package updating
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
+
+// Board defines the updating form of a board entity. Columns are left as they are when none are given, and
+// otherwise every status of the board's workflow must be in a column or in the unmapped statuses.
+type Board struct {
+	Name             string        `json:"name"`
+	Description      string        `json:"description,omitempty"`
+	IsBacklogVisible bool          `json:"isBacklogVisible"`
+	IsBoardVisible   bool          `json:"isBoardVisible"`
+	ParallelSprints  bool          `json:"parallelSprints"`
+	Filters          []BoardFilter `json:"filters"`
+	Columns          []BoardColumn `json:"columns,omitempty"`
+	UnmappedStatuses []string      `json:"unmappedStatuses,omitempty"`
+}
+
+// BoardFilter defines the updating form of a board issue filter, for example a field of "label" and a value of
+// "mobile".
+type BoardFilter struct {
+	Field string `json:"field"`
+	Value string `json:"value"`
+}
+
+// BoardColumn defines the updating form of a board column Value Object. WIP limits are set through their own request,
+// and are only carried through changes to the columns.
+type BoardColumn struct {
+	Name          string   `json:"name"`
+	IssueStatuses []string `json:"issueStatuses"`
+	MinWIP        int32    `json:"-"`
+	MaxWIP        int32    `json:"-"`
+	WIPPolicy     string   `json:"-"`
+}
+
+func validateUpdateBoard(b *Board) error {
+	if b == nil {
+		return fmt.Errorf("Board is nil")
+	}
+	if len(b.Name) == 0 {
+		return fmt.Errorf("'name' is empty")
+	}
+	for _, f := range b.Filters {
+		if len(f.Field) == 0 {
+			return fmt.Errorf("Filter 'field' is empty")
+		}
+	}
+
+	return validateBoardColumns(b.Columns)
+}
+
+// BoardType defines the updating form of a board type entity. Board types without columns give their boards a column
+// for each step of the workflow.
+type BoardType struct {
+	Name             string        `json:"name"`
+	IsSprintable     bool          `json:"isSprintable"`
+	IsBacklogVisible bool          `json:"isBacklogVisible"`
+	IsBoardVisible   bool          `json:"isBoardVisible"`
+	WorkflowID       int32         `json:"workflowId"`
+	IsDefault        bool          `json:"default"`
+	Columns          []BoardColumn `json:"columns,omitempty"`
+	UnmappedStatuses []string      `json:"unmappedStatuses,omitempty"`
+}
+
+func validateUpdateBoardType(bt *BoardType) error {
+	if bt == nil {
+		return fmt.Errorf("Board type is nil")
+	}
+	if len(bt.Name) == 0 {
+		return fmt.Errorf("'name' is empty")
+	}
+	if bt.WorkflowID == 0 {
+		return fmt.Errorf("'workflowId' is empty")
+	}
+
+	return validateBoardColumns(bt.Columns)
+}
+
+func validateBoardColumns(columns []BoardColumn) error {
+	names := make(map[string]bool)
+	statuses := make(map[string]bool)
+	for _, c := range columns {
+		if len(c.Name) == 0 {
+			return fmt.Errorf("Column 'name' is empty")
+		}
+		if names[c.Name] {
+			return fmt.Errorf("Column %v is duplicated", c.Name)
+		}
+		names[c.Name] = true
+
+		for _, status := range c.IssueStatuses {
+			if statuses[status] {
+				return fmt.Errorf("Issue status %v is in more than one column", status)
+			}
+			statuses[status] = true
+		}
+	}
+
+	return nil
+}
 
 // Sprint ...
 type Sprint struct {
@@ -9,3 +108,59 @@ type Sprint struct {
 	StartAt time.Time `json:"startAt,omitempty"`
 	EndAt   time.Time `json:"endAt,omitempty"`
 }
+
+// SprintStart defines the updating sprint start request.
+type SprintStart struct {
+	StartAt *time.Time `json:"startAt"`
+	EndAt   *time.Time `json:"endAt"`
+}
+
+// SprintCompletion defines the updating sprint completion request. Unfinished issues are sent to the backlog,
+// unless MoveUnfinishedTo is "NEXT", for the board's next sprint, or the id of another sprint on the board.
+type SprintCompletion struct {
+	MoveUnfinishedTo string `json:"moveUnfinishedTo,omitempty"`
+}
+
+func validateSprintStart(ss *SprintStart) error {
+	if ss == nil {
+		return fmt.Errorf("Sprint start is nil")
+	}
+	if ss.StartAt == nil || ss.StartAt.IsZero() {
+		return fmt.Errorf("'startAt' is empty")
+	}
+	if ss.EndAt == nil || ss.EndAt.IsZero() {
+		return fmt.Errorf("'endAt' is empty")
+	}
+	if !ss.EndAt.After(*ss.StartAt) {
+		return fmt.Errorf("'endAt' must be after 'startAt'")
+	}
+
+	return nil
+}
+
+// WIPLimit defines the updating form of the work in progress limits of a board column. A limit of 0 is no limit.
+type WIPLimit struct {
+	MinWIP int32  `json:"minWip"`
+	MaxWIP int32  `json:"maxWip"`
+	Policy string `json:"policy,omitempty"`
+}
+
+func validateWIPLimit(l *WIPLimit) error {
+	if l == nil {
+		return fmt.Errorf("WIP limit is nil")
+	}
+	if l.MinWIP < 0 || l.MaxWIP < 0 {
+		return fmt.Errorf("WIP limits cannot be negative")
+	}
+	if l.MaxWIP > 0 && l.MinWIP > l.MaxWIP {
+		return fmt.Errorf("'minWip' cannot be greater than 'maxWip'")
+	}
+	if len(l.Policy) == 0 {
+		l.Policy = WIPPolicyFlag
+	}
+	if l.Policy != WIPPolicyFlag && l.Policy != WIPPolicyReject {
+		return fmt.Errorf("Unknown WIP policy %v", l.Policy)
+	}
+
+	return nil
+}
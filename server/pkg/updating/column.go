@@ -0,0 +1,240 @@
+package updating
+
+import "fmt"
+
+// BoardColumns defines the updating form of the column configuration of a board. Columns are in ordinal order, and
+// every status of the board's workflow is in exactly one column or in the unmapped statuses.
+type BoardColumns struct {
+	Columns          []BoardColumn `json:"columns"`
+	UnmappedStatuses []string      `json:"unmappedStatuses"`
+}
+
+// ColumnMove defines the updating column move request, to the ordinal position the column should take.
+type ColumnMove struct {
+	Ordinal int32 `json:"ordinal"`
+}
+
+// StatusMapping defines the updating status mapping request. A status is mapped onto the column at an ordinal
+// position, or explicitly left unmapped.
+type StatusMapping struct {
+	Ordinal  *int32 `json:"ordinal,omitempty"`
+	Unmapped bool   `json:"unmapped,omitempty"`
+}
+
+func validateBoardColumn(c *BoardColumn) error {
+	if c == nil {
+		return fmt.Errorf("Column is nil")
+	}
+	if len(c.Name) == 0 {
+		return fmt.Errorf("'name' is empty")
+	}
+
+	return nil
+}
+
+func validateStatusMapping(m *StatusMapping) error {
+	if m == nil {
+		return fmt.Errorf("Status mapping is nil")
+	}
+	if m.Ordinal == nil && !m.Unmapped {
+		return fmt.Errorf("'ordinal' is empty")
+	}
+	if m.Ordinal != nil && m.Unmapped {
+		return fmt.Errorf("'ordinal' must be empty for an unmapped status")
+	}
+
+	return nil
+}
+
+func (bc *BoardColumns) column(ordinal int32) (*BoardColumn, error) {
+	if ordinal < 0 || int(ordinal) >= len(bc.Columns) {
+		return nil, fmt.Errorf("Column %v not found", ordinal)
+	}
+	return &bc.Columns[ordinal], nil
+}
+
+func (bc *BoardColumns) checkName(name string, ordinal int32) error {
+	for i, c := range bc.Columns {
+		if c.Name == name && int32(i) != ordinal {
+			return fmt.Errorf("Column %v already exists", name)
+		}
+	}
+	return nil
+}
+
+// take removes statuses from whichever column, or the unmapped statuses, they are currently in.
+func (bc *BoardColumns) take(statuses []string) {
+	taken := make(map[string]bool)
+	for _, status := range statuses {
+		taken[status] = true
+	}
+
+	remove := func(values []string) []string {
+		results := []string{}
+		for _, v := range values {
+			if !taken[v] {
+				results = append(results, v)
+			}
+		}
+		return results
+	}
+
+	for i := range bc.Columns {
+		bc.Columns[i].IssueStatuses = remove(bc.Columns[i].IssueStatuses)
+	}
+	bc.UnmappedStatuses = remove(bc.UnmappedStatuses)
+}
+
+// addColumn adds a column after the last column, moving its statuses from wherever they are mapped.
+func (bc *BoardColumns) addColumn(c *BoardColumn) error {
+	if err := bc.checkName(c.Name, -1); err != nil {
+		return err
+	}
+
+	bc.take(c.IssueStatuses)
+	bc.Columns = append(bc.Columns, BoardColumn{Name: c.Name, IssueStatuses: c.IssueStatuses})
+
+	return nil
+}
+
+// updateColumn renames a column and replaces its statuses. Statuses it no longer has become unmapped.
+func (bc *BoardColumns) updateColumn(ordinal int32, c *BoardColumn) error {
+	column, err := bc.column(ordinal)
+	if err != nil {
+		return err
+	}
+	if err = bc.checkName(c.Name, ordinal); err != nil {
+		return err
+	}
+
+	previous := column.IssueStatuses
+
+	bc.take(c.IssueStatuses)
+	column.Name = c.Name
+	column.IssueStatuses = c.IssueStatuses
+
+	kept := make(map[string]bool)
+	for _, status := range c.IssueStatuses {
+		kept[status] = true
+	}
+	for _, status := range previous {
+		if !kept[status] {
+			bc.UnmappedStatuses = append(bc.UnmappedStatuses, status)
+		}
+	}
+
+	return nil
+}
+
+// moveColumn moves a column to another ordinal position, shifting the columns in between.
+func (bc *BoardColumns) moveColumn(ordinal int32, m *ColumnMove) error {
+	column, err := bc.column(ordinal)
+	if err != nil {
+		return err
+	}
+	if _, err = bc.column(m.Ordinal); err != nil {
+		return err
+	}
+
+	moved := *column
+	columns := append(append([]BoardColumn{}, bc.Columns[:ordinal]...), bc.Columns[ordinal+1:]...)
+	columns = append(columns[:m.Ordinal], append([]BoardColumn{moved}, columns[m.Ordinal:]...)...)
+	bc.Columns = columns
+
+	return nil
+}
+
+// splitColumn moves some of the statuses of a column into a new column placed straight after it.
+func (bc *BoardColumns) splitColumn(ordinal int32, c *BoardColumn) error {
+	column, err := bc.column(ordinal)
+	if err != nil {
+		return err
+	}
+	if err = bc.checkName(c.Name, -1); err != nil {
+		return err
+	}
+	if len(c.IssueStatuses) == 0 {
+		return fmt.Errorf("'issueStatuses' is empty")
+	}
+
+	inColumn := make(map[string]bool)
+	for _, status := range column.IssueStatuses {
+		inColumn[status] = true
+	}
+	for _, status := range c.IssueStatuses {
+		if !inColumn[status] {
+			return fmt.Errorf("Issue status %v is not in column %v", status, column.Name)
+		}
+	}
+
+	bc.take(c.IssueStatuses)
+
+	split := BoardColumn{Name: c.Name, IssueStatuses: c.IssueStatuses}
+	bc.Columns = append(bc.Columns[:ordinal+1], append([]BoardColumn{split}, bc.Columns[ordinal+1:]...)...)
+
+	return nil
+}
+
+// removeColumn removes a column, leaving its statuses unmapped. A board keeps at least one column.
+func (bc *BoardColumns) removeColumn(ordinal int32) error {
+	column, err := bc.column(ordinal)
+	if err != nil {
+		return err
+	}
+	if len(bc.Columns) == 1 {
+		return fmt.Errorf("Cannot remove the last column of a board")
+	}
+
+	bc.UnmappedStatuses = append(bc.UnmappedStatuses, column.IssueStatuses...)
+	bc.Columns = append(bc.Columns[:ordinal], bc.Columns[ordinal+1:]...)
+
+	return nil
+}
+
+// mapStatus maps a status onto a column, or leaves it unmapped.
+func (bc *BoardColumns) mapStatus(statusID string, m *StatusMapping) error {
+	var column *BoardColumn
+	if m.Ordinal != nil {
+		c, err := bc.column(*m.Ordinal)
+		if err != nil {
+			return err
+		}
+		column = c
+	}
+
+	bc.take([]string{statusID})
+
+	if column != nil {
+		column.IssueStatuses = append(column.IssueStatuses, statusID)
+	} else {
+		bc.UnmappedStatuses = append(bc.UnmappedStatuses, statusID)
+	}
+
+	return nil
+}
+
+// updateProjectBoardColumns applies a change to the column configuration of a project board, then saves it.
+func (s *service) updateProjectBoardColumns(userID *string, projectID *string, boardID *string, change func(*BoardColumns) error) error {
+	bc, err := s.repo.GetProjectBoardColumns(projectID, boardID)
+	if err != nil {
+		return err
+	}
+
+	err = change(bc)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.UpdateProjectBoardColumns(projectID, boardID, bc)
+	if err != nil {
+		return err
+	}
+
+	payload := ProjectBoardColumnUpdatedPayload{*userID, *projectID, *boardID}
+	err = s.broadcastEvent(ProjectBoardColumnUpdated, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
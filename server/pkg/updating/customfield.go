@@ -0,0 +1,24 @@
+package updating
+
+import "fmt"
+
+// CustomField defines the updating form of a custom field entity.
+type CustomField struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Options     []string `json:"options,omitempty"`
+	IsRequired  bool     `json:"isRequired"`
+	ProjectIDs  []string `json:"projectIds,omitempty"`
+	IssueTypes  []string `json:"issueTypes,omitempty"`
+}
+
+func validateUpdateCustomField(cf *CustomField) error {
+	if cf == nil {
+		return fmt.Errorf("Custom field is nil")
+	}
+	if len(cf.Name) == 0 {
+		return fmt.Errorf("'name' is empty")
+	}
+
+	return nil
+}
@@ -1,17 +1,54 @@
 package updating
 
+import "time"
+
 // EventType defines a custom type for events.
 type EventType string
 
 const (
 	// IssueUpdated defines the EventType for when an issue has been updated.
 	IssueUpdated EventType = "ISSUE_UPDATED"
+	// IssueMoved defines the EventType for when an issue has been moved within, or between, the backlog and sprints.
+	IssueMoved EventType = "ISSUE_MOVED"
+	// IssueTransferred defines the EventType for when an issue has been moved to another project.
+	IssueTransferred EventType = "ISSUE_TRANSFERRED"
+	// IssueSLABreached defines the EventType for when an issue has breached the target of an SLA policy.
+	IssueSLABreached EventType = "ISSUE_SLA_BREACHED"
+	// IssueOverdue defines the EventType for when an unfinished issue has passed its due date.
+	IssueOverdue EventType = "ISSUE_OVERDUE"
+	// IssueScheduleUpdated defines the EventType for when a project issue schedule has been updated.
+	IssueScheduleUpdated EventType = "ISSUE_SCHEDULE_UPDATED"
 	// IssueCommentUpdated defines the EventType for when an issue comment has been updated.
 	IssueCommentUpdated EventType = "ISSUE_COMMENT_UPDATED"
+	// IssueWorklogUpdated defines the EventType for when an issue worklog has been updated.
+	IssueWorklogUpdated EventType = "ISSUE_WORKLOG_UPDATED"
 	// ProjectUpdated defines the EventType for when a project has been updated.
 	ProjectUpdated EventType = "PROJECT_UPDATED"
+	// ProjectComponentUpdated defines the EventType for when a project component has been updated.
+	ProjectComponentUpdated EventType = "PROJECT_COMPONENT_UPDATED"
+	// ProjectIssueTemplateUpdated defines the EventType for when a project issue template has been updated.
+	ProjectIssueTemplateUpdated EventType = "PROJECT_ISSUE_TEMPLATE_UPDATED"
+	// ProjectSLAPolicyUpdated defines the EventType for when a project SLA policy has been updated.
+	ProjectSLAPolicyUpdated EventType = "PROJECT_SLA_POLICY_UPDATED"
+	// ProjectVersionUpdated defines the EventType for when a project version has been updated.
+	ProjectVersionUpdated EventType = "PROJECT_VERSION_UPDATED"
+	// ProjectVersionReleased defines the EventType for when a project version has been released.
+	ProjectVersionReleased EventType = "PROJECT_VERSION_RELEASED"
+	// ProjectVersionUnreleased defines the EventType for when a project version has been unreleased.
+	ProjectVersionUnreleased EventType = "PROJECT_VERSION_UNRELEASED"
+	// ProjectBoardUpdated defines the EventType for when a project board has been updated.
+	ProjectBoardUpdated EventType = "PROJECT_BOARD_UPDATED"
 	// ProjectBoardSprintUpdated defines the EventType for when a project board sprint has been updated.
 	ProjectBoardSprintUpdated EventType = "PROJECT_BOARD_SPRINT_UPDATED"
+	// ProjectBoardSprintStarted defines the EventType for when a project board sprint has been started.
+	ProjectBoardSprintStarted EventType = "PROJECT_BOARD_SPRINT_STARTED"
+	// ProjectBoardSprintCompleted defines the EventType for when a project board sprint has been completed.
+	ProjectBoardSprintCompleted EventType = "PROJECT_BOARD_SPRINT_COMPLETED"
+	// ProjectBoardColumnUpdated defines the EventType for when a project board column has been updated.
+	ProjectBoardColumnUpdated EventType = "PROJECT_BOARD_COLUMN_UPDATED"
+	// ProjectBoardColumnWIPExceeded defines the EventType for when a move has taken a project board column over its
+	// maximum WIP limit.
+	ProjectBoardColumnWIPExceeded EventType = "PROJECT_BOARD_COLUMN_WIP_EXCEEDED"
 )
 
 // Message ...
@@ -27,6 +64,41 @@ type IssueUpdatedPayload struct {
 	IssueID   string `json:"issueId"`
 }
 
+// IssueBreachedPayload defines the payload of data for an issue SLA breached, or issue overdue, event.
+type IssueBreachedPayload struct {
+	ProjectID  string    `json:"projectId"`
+	IssueID    string    `json:"issueId"`
+	PolicyID   string    `json:"policyId,omitempty"`
+	Name       string    `json:"name"`
+	BreachedAt time.Time `json:"breachedAt"`
+}
+
+// IssueScheduleUpdatedPayload defines the payload of data for a project issue schedule updated event.
+type IssueScheduleUpdatedPayload struct {
+	UserID     string `json:"userId"`
+	ProjectID  string `json:"projectId"`
+	ScheduleID string `json:"scheduleId"`
+}
+
+// IssueMovedPayload defines the payload of data for an issue moved event.
+type IssueMovedPayload struct {
+	UserID    string `json:"userId"`
+	ProjectID string `json:"projectId"`
+	IssueID   string `json:"issueId"`
+	SprintID  string `json:"sprintId,omitempty"`
+	BeforeID  string `json:"beforeId,omitempty"`
+	AfterID   string `json:"afterId,omitempty"`
+	Status    string `json:"status,omitempty"`
+}
+
+// IssueTransferredPayload defines the payload of data for an issue transferred event.
+type IssueTransferredPayload struct {
+	UserID        string `json:"userId"`
+	FromProjectID string `json:"fromProjectId"`
+	ProjectID     string `json:"projectId"`
+	IssueID       string `json:"issueId"`
+}
+
 // IssueCommentUpdatedPayload defines the payload of data for an issue comment updated event.
 type IssueCommentUpdatedPayload struct {
 	UserID    string `json:"userId"`
@@ -34,12 +106,43 @@ type IssueCommentUpdatedPayload struct {
 	CommentID string `json:"commentId"`
 }
 
+// IssueWorklogUpdatedPayload defines the payload of data for an issue worklog updated event.
+type IssueWorklogUpdatedPayload struct {
+	UserID    string `json:"userId"`
+	IssueID   string `json:"issueId"`
+	WorklogID string `json:"worklogId"`
+}
+
 // ProjectUpdatedPayload defines the payload of data for a project updated event.
 type ProjectUpdatedPayload struct {
 	UserID    string `json:"userId"`
 	ProjectID string `json:"projectId"`
 }
 
+// ProjectBoardUpdatedPayload defines the payload of data for a project board updated event.
+type ProjectBoardUpdatedPayload struct {
+	UserID    string `json:"userId"`
+	ProjectID string `json:"projectId"`
+	BoardID   string `json:"boardId"`
+}
+
+// ProjectBoardColumnUpdatedPayload defines the payload of data for a project board column updated event.
+type ProjectBoardColumnUpdatedPayload struct {
+	UserID    string `json:"userId"`
+	ProjectID string `json:"projectId"`
+	BoardID   string `json:"boardId"`
+}
+
+// ProjectBoardColumnWIPExceededPayload defines the payload of data for a project board column WIP exceeded event.
+type ProjectBoardColumnWIPExceededPayload struct {
+	UserID    string `json:"userId"`
+	ProjectID string `json:"projectId"`
+	BoardID   string `json:"boardId"`
+	Column    string `json:"column"`
+	Count     int64  `json:"count"`
+	MaxWIP    int32  `json:"maxWip"`
+}
+
 // ProjectBoardSprintUpdatedPayload defines the payload of data for a project board sprint updated event.
 type ProjectBoardSprintUpdatedPayload struct {
 	UserID    string `json:"userId"`
@@ -47,3 +150,31 @@ type ProjectBoardSprintUpdatedPayload struct {
 	BoardID   string `json:"boardId"`
 	SprintID  string `json:"sprintId"`
 }
+
+// ProjectComponentUpdatedPayload defines the payload of data for a project component updated event.
+type ProjectComponentUpdatedPayload struct {
+	UserID      string `json:"userId"`
+	ProjectID   string `json:"projectId"`
+	ComponentID string `json:"componentId,omitempty"`
+}
+
+// ProjectIssueTemplateUpdatedPayload defines the payload of data for a project issue template updated event.
+type ProjectIssueTemplateUpdatedPayload struct {
+	UserID          string `json:"userId"`
+	ProjectID       string `json:"projectId"`
+	IssueTemplateID string `json:"issueTemplateId,omitempty"`
+}
+
+// ProjectSLAPolicyUpdatedPayload defines the payload of data for a project SLA policy updated event.
+type ProjectSLAPolicyUpdatedPayload struct {
+	UserID      string `json:"userId"`
+	ProjectID   string `json:"projectId"`
+	SLAPolicyID string `json:"slaPolicyId,omitempty"`
+}
+
+// ProjectVersionUpdatedPayload defines the payload of data for a project version updated event.
+type ProjectVersionUpdatedPayload struct {
+	UserID    string `json:"userId"`
+	ProjectID string `json:"projectId"`
+	VersionID string `json:"versionId,omitempty"`
+}
@@ -1,16 +1,50 @@
 package updating
 
+import (
+	"fmt"
+	"time"
+)
+
 // Issue defines the updating form of an issue entity.
 type Issue struct {
-	SprintID    string `json:"sprintId"`
-	Type        string `json:"type"`
-	Summary     string `json:"summary"`
-	Description string `json:"description,omitempty"`
-	Status      string `json:"status"`
-	Priority    string `json:"priority"`
-	Points      int32  `json:"points,omitempty"`
-	AssigneeID  string `json:"assigneeId,omitempty"`
-	Ordinal     int32  `json:"ordinal"`
+	SprintID          string                 `json:"sprintId"`
+	Type              string                 `json:"type"`
+	Summary           string                 `json:"summary"`
+	Description       string                 `json:"description,omitempty"`
+	Status            string                 `json:"status"`
+	Priority          string                 `json:"priority"`
+	Points            int32                  `json:"points,omitempty"`
+	OriginalEstimate  int64                  `json:"originalEstimate,omitempty"`
+	RemainingEstimate *int64                 `json:"remainingEstimate,omitempty"`
+	DueDate           *time.Time             `json:"dueDate,omitempty"`
+	AssigneeID        string                 `json:"assigneeId,omitempty"`
+	EpicID            string                 `json:"epicId,omitempty"`
+	Ordinal           int32                  `json:"ordinal"`
+	AddLabels         []string               `json:"addLabels,omitempty"`
+	RemoveLabels      []string               `json:"removeLabels,omitempty"`
+	Components        []string               `json:"components,omitempty"`
+	FixVersions       []string               `json:"fixVersions,omitempty"`
+	CustomFields      map[string]interface{} `json:"customFields,omitempty"`
+}
+
+// ApplyLabels returns a copy of labels with the issue's label additions and removals applied.
+func (i *Issue) ApplyLabels(labels []string) []string {
+	remove := make(map[string]bool)
+	for _, l := range i.RemoveLabels {
+		remove[l] = true
+	}
+
+	results := []string{}
+	seen := make(map[string]bool)
+	for _, l := range append(append([]string{}, labels...), i.AddLabels...) {
+		if remove[l] || seen[l] || len(l) == 0 {
+			continue
+		}
+		seen[l] = true
+		results = append(results, l)
+	}
+
+	return results
 }
 
 // IssueOrdinal defines the updating form of an issue ordinal.
@@ -42,9 +76,63 @@ type IssueStatus struct {
 	Category    string `json:"category"`
 }
 
+// IssueType defines the updating form of an issue type entity.
+type IssueType struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Icon        string `json:"icon,omitempty"`
+	IsSubTask   bool   `json:"subTask"`
+}
+
+// Label defines the updating form of a label entity.
+type Label struct {
+	Label string `json:"label"`
+}
+
+// LabelMerge defines the updating label merge request. The merged label is replaced by the target label.
+type LabelMerge struct {
+	TargetID string `json:"targetId"`
+}
+
 // PriorityType defines the updating form of a priotity type entity.
 type PriorityType struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	Color       string `json:"color"`
 }
+
+func validateUpdateIssueType(it *IssueType) error {
+	if it == nil {
+		return fmt.Errorf("Issue type is nil")
+	}
+	if len(it.Name) == 0 {
+		return fmt.Errorf("'name' is empty")
+	}
+
+	return nil
+}
+
+func validateUpdateLabel(l *Label) error {
+	if l == nil {
+		return fmt.Errorf("Label is nil")
+	}
+	if len(l.Label) == 0 {
+		return fmt.Errorf("'label' is empty")
+	}
+
+	return nil
+}
+
+func validateLabelMerge(id string, lm *LabelMerge) error {
+	if lm == nil {
+		return fmt.Errorf("Label merge is nil")
+	}
+	if len(lm.TargetID) == 0 {
+		return fmt.Errorf("'targetId' is empty")
+	}
+	if lm.TargetID == id {
+		return fmt.Errorf("A label cannot be merged into itself")
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package updating
+
+import "fmt"
+
+// IssuePosition defines the updating issue move request. An issue is placed before, or after, another issue of the
+// backlog or of a sprint, or at the bottom where neither is given. An empty sprint id is the backlog. Where a board
+// column is given, the issue's status is derived from the column's statuses.
+type IssuePosition struct {
+	BeforeID string `json:"beforeId,omitempty"`
+	AfterID  string `json:"afterId,omitempty"`
+	SprintID string `json:"sprintId,omitempty"`
+	BoardID  string `json:"boardId,omitempty"`
+	Column   *int32 `json:"column,omitempty"`
+}
+
+func validateIssuePosition(issueID string, p *IssuePosition) error {
+	if p == nil {
+		return fmt.Errorf("Issue position is nil")
+	}
+	if len(p.BeforeID) > 0 && len(p.AfterID) > 0 {
+		return fmt.Errorf("'beforeId' and 'afterId' cannot both be set")
+	}
+	if p.BeforeID == issueID || p.AfterID == issueID {
+		return fmt.Errorf("Issue cannot be placed relative to itself")
+	}
+	if p.Column != nil && len(p.BoardID) == 0 {
+		return fmt.Errorf("'boardId' is empty")
+	}
+
+	return nil
+}
+
+// getColumnTransition returns the target state of an issue moved into a board column: unchanged where its status is
+// already in the column, or the first status of the column the workflow allows it to transition to.
+func (s *service) getColumnTransition(userID *string, projectID *string, issueID *string, p *IssuePosition) (*TransitionIssue, error) {
+	bc, err := s.repo.GetProjectBoardColumns(projectID, &p.BoardID)
+	if err != nil {
+		return nil, err
+	}
+
+	ordinal := int(*p.Column)
+	if ordinal < 0 || ordinal >= len(bc.Columns) {
+		return nil, fmt.Errorf("Column %v not found for board %v", ordinal, p.BoardID)
+	}
+	column := bc.Columns[ordinal]
+
+	issues, err := s.repo.GetTransitionIssues([]string{*issueID})
+	if err != nil {
+		return nil, err
+	}
+
+	current, ok := issues[*issueID]
+	if !ok {
+		return nil, fmt.Errorf("Issue %v not found", *issueID)
+	}
+
+	for _, status := range column.IssueStatuses {
+		if status == current.Status {
+			return nil, nil
+		}
+	}
+
+	ts, err := s.repo.GetProjectWorkflowTransitions(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = fmt.Errorf("Column %v has no statuses", column.Name)
+	for _, status := range column.IssueStatuses {
+		target := current
+		target.Status = status
+
+		ctx := TransitionContext{UserID: *userID, FromStatus: current.Status, Current: &current, Issue: &target}
+		if err = applyTransition(ts, &ctx); err == nil {
+			return &target, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Issue cannot be moved to column %v: %v", column.Name, err.Error())
+}
@@ -1,5 +1,7 @@
 package updating
 
+import "fmt"
+
 // Project defines the updating form of a project entity.
 type Project struct {
 	Name              string `json:"name,omitempty"`
@@ -8,3 +10,41 @@ type Project struct {
 	LeadID            string `json:"leadId,omitempty"`
 	DefaultAssigneeID string `json:"defaultAssigneeId,omitempty"`
 }
+
+// ProjectIssueTypeScheme defines the updating form of a project's issue type scheme. An empty scheme allows
+// every issue type.
+type ProjectIssueTypeScheme struct {
+	IssueTypes []string `json:"issueTypes"`
+}
+
+// Component defines the updating form of a project component entity.
+type Component struct {
+	Name         string `json:"name"`
+	Description  string `json:"description,omitempty"`
+	LeadID       string `json:"leadId,omitempty"`
+	AssigneeType string `json:"assigneeType"`
+}
+
+var componentAssigneeTypes = []string{"PROJECT_DEFAULT", "COMPONENT_LEAD", "UNASSIGNED"}
+
+func validateUpdateComponent(c *Component) error {
+	if c == nil {
+		return fmt.Errorf("Component is nil")
+	}
+	if len(c.Name) == 0 {
+		return fmt.Errorf("'name' is empty")
+	}
+	if len(c.AssigneeType) == 0 {
+		c.AssigneeType = componentAssigneeTypes[0]
+	}
+	for _, t := range componentAssigneeTypes {
+		if t == c.AssigneeType {
+			if t == "COMPONENT_LEAD" && len(c.LeadID) == 0 {
+				return fmt.Errorf("'leadId' is empty")
+			}
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Unknown assignee type %v", c.AssigneeType)
+}
@@ -0,0 +1,110 @@
+package updating
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/njehyde/issue-tracker/libraries/recurrence"
+)
+
+// IssueSchedule defines the updating form of a project issue schedule entity, which creates an issue from its template
+// at every occurrence of its rule. The rule is a cron expression, or an RRULE, in the schedule's time zone.
+type IssueSchedule struct {
+	Name      string         `json:"name"`
+	Rule      string         `json:"rule"`
+	Timezone  string         `json:"timezone,omitempty"`
+	StartAt   *time.Time     `json:"startAt,omitempty"`
+	CatchUp   string         `json:"catchUp,omitempty"`
+	IsEnabled bool           `json:"enabled"`
+	Issue     ScheduledIssue `json:"issue"`
+}
+
+// ScheduledIssue defines the updating form of the issue template of an issue schedule. Issues are due the given number
+// of seconds after they are created.
+type ScheduledIssue struct {
+	Type             string                 `json:"type"`
+	Summary          string                 `json:"summary"`
+	Description      string                 `json:"description,omitempty"`
+	Status           string                 `json:"status"`
+	Priority         string                 `json:"priority"`
+	Points           int32                  `json:"points,omitempty"`
+	OriginalEstimate int64                  `json:"originalEstimate,omitempty"`
+	DueIn            int64                  `json:"dueIn,omitempty"`
+	AssigneeID       string                 `json:"assigneeId,omitempty"`
+	EpicID           string                 `json:"epicId,omitempty"`
+	Labels           []string               `json:"labels,omitempty"`
+	Components       []string               `json:"components,omitempty"`
+	CustomFields     map[string]interface{} `json:"customFields,omitempty"`
+}
+
+func validateUpdateIssueSchedule(s *IssueSchedule) error {
+	if s == nil {
+		return fmt.Errorf("Issue schedule is nil")
+	}
+	if len(s.Name) == 0 {
+		return fmt.Errorf("'name' is empty")
+	}
+	if len(s.Rule) == 0 {
+		return fmt.Errorf("'rule' is empty")
+	}
+	if s.StartAt == nil {
+		return fmt.Errorf("'startAt' is empty")
+	}
+	if len(s.Timezone) == 0 {
+		s.Timezone = "UTC"
+	}
+	if len(s.CatchUp) == 0 {
+		s.CatchUp = "LATEST"
+	}
+	if s.CatchUp != "SKIP" && s.CatchUp != "LATEST" && s.CatchUp != "ALL" {
+		return fmt.Errorf("Unknown catch up policy %v", s.CatchUp)
+	}
+	if len(s.Issue.Type) == 0 {
+		return fmt.Errorf("'type' is empty")
+	}
+	if len(s.Issue.Summary) == 0 {
+		return fmt.Errorf("'summary' is empty")
+	}
+	if len(s.Issue.Status) == 0 {
+		return fmt.Errorf("'status' is empty")
+	}
+	if len(s.Issue.Priority) == 0 {
+		return fmt.Errorf("'priority' is empty")
+	}
+	if s.Issue.DueIn < 0 {
+		return fmt.Errorf("'dueIn' must not be negative")
+	}
+
+	return nil
+}
+
+// getIssueScheduleRule parses the rule of an issue schedule in its time zone, starting from its start time or now.
+func getIssueScheduleRule(rule string, timezone string, startAt *time.Time) (recurrence.Rule, time.Time, error) {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("Unknown time zone %v", timezone)
+	}
+
+	start := time.Now()
+	if startAt != nil {
+		start = *startAt
+	}
+	start = start.In(loc)
+
+	r, err := recurrence.Parse(rule, start)
+	if err != nil {
+		return nil, start, err
+	}
+
+	return r, start, nil
+}
+
+// getNextRunAt returns the first run of a rule after a time, or nil when the rule has no more runs.
+func getNextRunAt(r recurrence.Rule, after time.Time) *time.Time {
+	next, ok := r.Next(after)
+	if !ok {
+		return nil
+	}
+
+	return &next
+}
@@ -2,12 +2,20 @@ package updating
 
 import (
 	"encoding/json"
+	"fmt"
+	"time"
 
 	"github.com/njehyde/issue-tracker/pkg/http/ws"
 )
 
 // Service provides entity updating operations
 type Service interface {
+	// AddProjectBoardColumn adds a column to a project board.
+	AddProjectBoardColumn(*string, *string, *string, *BoardColumn) error
+	// CheckIssueBreaches records, and announces, the issues that have newly breached an SLA policy or passed their due date.
+	CheckIssueBreaches() error
+	// CompleteProjectBoardSprint completes an active project board sprint, moving its unfinished issues.
+	CompleteProjectBoardSprint(*string, *string, *string, *string, *SprintCompletion) error
 	// DecreaseIssueStatus updates the ordinal position of an issue status entity, as well as one or more of its siblings.
 	DecreaseIssueStatus(string) error
 	// DecreasePriorityType updates the ordinal position of an priority type entity, as well as one or more of its siblings.
@@ -16,30 +24,90 @@ type Service interface {
 	IncreaseIssueStatus(string) error
 	// IncreasePriorityType updates the ordinal position of an priority type entity, as well as one or more of its siblings.
 	IncreasePriorityType(string) error
+	// MergeLabels replaces a label entity with another on every issue, then deletes it.
+	MergeLabels(string, *LabelMerge) error
+	// MoveIssue places an issue before or after another issue of the backlog or a sprint, optionally into a board column.
+	MoveIssue(*string, *string, *string, *IssuePosition) error
+	// MoveProjectBoardColumn moves a project board column to another ordinal position.
+	MoveProjectBoardColumn(*string, *string, *string, int32, *ColumnMove) error
+	// ReadNotification marks a notification entity of a user as read.
+	ReadNotification(*string, *string) error
+	// ReleaseProjectVersion releases a project version entity, optionally moving its unfinished issues to another version.
+	ReleaseProjectVersion(*string, *string, *string, *VersionRelease) error
+	// RemoveProjectBoardColumn removes a column from a project board, leaving its statuses unmapped.
+	RemoveProjectBoardColumn(*string, *string, *string, int32) error
 	// SendIssueToSprint sends an issue to a sprint.
 	SendIssueToSprint(*string, *string, *string, *string, *SendIssueToSprintMetadata) error
-	// SendIssueToBottomOfBacklog sends an issue to the bottom of the backlog, and reassigns backlog issue ordinal positions.
+	// SendIssueToBottomOfBacklog sends an issue to the bottom of the backlog.
 	SendIssueToBottomOfBacklog(*string, *string, *string) error
-	// SendIssueToTopOfBacklog sends an issue to the top of the backlog, and reassigns backlog issue ordinal positions.
+	// SendIssueToTopOfBacklog sends an issue to the top of the backlog.
 	SendIssueToTopOfBacklog(*string, *string, *string) error
+	// SplitProjectBoardColumn moves some of the statuses of a project board column into a new column after it.
+	SplitProjectBoardColumn(*string, *string, *string, int32, *BoardColumn) error
+	// StartProjectBoardSprint starts a project board sprint.
+	StartProjectBoardSprint(*string, *string, *string, *string, *SprintStart) error
+	// TransferIssue moves an issue to another project, under a new project reference.
+	TransferIssue(*string, *string, *IssueTransfer) error
+	// UnreleaseProjectVersion returns a released project version entity to unreleased.
+	UnreleaseProjectVersion(*string, *string, *string) error
+	// UpdateBoardType updates a board type entity.
+	UpdateBoardType(string, *BoardType) error
+	// UpdateCustomField updates a custom field entity.
+	UpdateCustomField(string, *CustomField) error
 	// UpdateIssue updates an issue entity.
 	UpdateIssue(*string, *string, *string, *Issue) error
 	// UpdateIssueOrdinals ...
 	UpdateIssueOrdinals(*string, *string, *[]IssueOrdinal) error
 	// UpdateIssueComment updates an issue comment entity.
 	UpdateIssueComment(*string, *string, *string, *IssueComment) error
+	// UpdateIssueSchedule updates a project issue schedule entity, and when it next runs.
+	UpdateIssueSchedule(*string, *string, *string, *IssueSchedule) error
 	// UpdateIssueStatus updates an issue status entity.
 	UpdateIssueStatus(string, *IssueStatus) error
+	// UpdateIssueType updates an issue type entity.
+	UpdateIssueType(string, *IssueType) error
+	// UpdateIssueWorklog updates an issue worklog entity.
+	UpdateIssueWorklog(*string, *string, *string, *IssueWorklog) error
+	// UpdateLabel renames a label entity, and the issues using it.
+	UpdateLabel(string, *Label) error
 	// UpdatePriorityType updates a priority type entity.
 	UpdatePriorityType(string, *PriorityType) error
 	// UpdateProject updates a project entity.
 	UpdateProject(*string, string, *Project) error
+	// UpdateProjectDefaultBoard sets which of a project's boards is its default board.
+	UpdateProjectDefaultBoard(*string, *string, *string) error
+	// UpdateProjectComponent updates a project component entity.
+	UpdateProjectComponent(*string, *string, *string, *Component) error
+	// UpdateProjectIssueTemplate updates a project issue template entity.
+	UpdateProjectIssueTemplate(*string, *string, *string, *IssueTemplate) error
+	// UpdateProjectIssueTypeScheme replaces the issue types allowed by a project entity.
+	UpdateProjectIssueTypeScheme(*string, string, *ProjectIssueTypeScheme) error
+	// UpdateProjectSLAPolicy updates a project SLA policy entity.
+	UpdateProjectSLAPolicy(*string, *string, *string, *SLAPolicy) error
+	// UpdateProjectVersion updates a project version entity.
+	UpdateProjectVersion(*string, *string, *string, *Version) error
+	// UpdateProjectBoard updates a project board entity.
+	UpdateProjectBoard(*string, *string, *string, *Board) error
+	// UpdateProjectBoardColumn renames a project board column and replaces its statuses.
+	UpdateProjectBoardColumn(*string, *string, *string, int32, *BoardColumn) error
+	// UpdateProjectBoardColumnWIPLimit updates the WIP limits of a project board column.
+	UpdateProjectBoardColumnWIPLimit(*string, *string, *string, int32, *WIPLimit) error
 	// UpdateProjectBoardSprint updates a project board sprint entity.
 	UpdateProjectBoardSprint(*string, *string, *string, *string, *Sprint) error
+	// UpdateProjectBoardStatusMapping maps an issue status onto a project board column, or leaves it unmapped.
+	UpdateProjectBoardStatusMapping(*string, *string, *string, string, *StatusMapping) error
+	// UpdateProjectBoardSwimlanes updates how the issues of a project board are grouped into swimlanes.
+	UpdateProjectBoardSwimlanes(*string, *string, *string, *Swimlanes) error
+	// UpdateWorkflowTransitions replaces the transitions, and their rules, of a workflow entity.
+	UpdateWorkflowTransitions(int32, []WorkflowTransition) error
 }
 
 // Repository provides access to issue repository
 type Repository interface {
+	// AddIssueBreach records that an issue has breached an SLA policy, or passed its due date, and notifies its users in storage.
+	AddIssueBreach(*IssueBreach) error
+	// CompleteProjectBoardSprint completes an active project board sprint in storage, moving its unfinished issues.
+	CompleteProjectBoardSprint(*string, *string, *string, *SprintCompletion) error
 	// DecreaseIssueStatus updates the ordinal position of an issue status entity, as well as one or more of its siblings.
 	DecreaseIssueStatus(string) error
 	// DecreasePriorityType updates the ordinal position of an priority type entity, as well as one or more of its siblings.
@@ -48,26 +116,84 @@ type Repository interface {
 	IncreaseIssueStatus(string) error
 	// IncreasePriorityType updates the ordinal position of an priority type entity, as well as one or more of its siblings.
 	IncreasePriorityType(string) error
+	// GetIssueBreaches returns the issues that have breached an SLA policy, or passed their due date, and have not been recorded yet.
+	GetIssueBreaches(time.Time) ([]IssueBreach, error)
+	// GetProjectBoardColumns returns the column configuration of a project board from storage.
+	GetProjectBoardColumns(*string, *string) (*BoardColumns, error)
+	// GetProjectWorkflowTransitions returns the transitions of the workflow used by a project from storage.
+	GetProjectWorkflowTransitions(*string) ([]WorkflowTransition, error)
+	// GetTransitionIssues returns the transition state of a set of issue entities, keyed by id, from storage.
+	GetTransitionIssues([]string) (map[string]TransitionIssue, error)
+	// GetWIPBreaches returns the board columns that a set of issue moves would take over their maximum WIP limits.
+	GetWIPBreaches(*string, []IssueMove) ([]WIPBreach, error)
+	// MergeLabels replaces a label entity with another on every issue, then deletes it from storage.
+	MergeLabels(string, string) error
+	// MoveIssue places an issue before or after another issue of the backlog or a sprint in storage, applying a transition.
+	MoveIssue(*string, *string, *IssuePosition, *TransitionIssue) error
+	// ReadNotification marks a notification entity of a user as read in storage.
+	ReadNotification(*string, *string) error
+	// ReleaseProjectVersion releases a project version entity in storage, optionally moving its unfinished issues to another version.
+	ReleaseProjectVersion(*string, *string, *VersionRelease) error
 	// SendIssueToSprint sends an issue to a sprint.
-	SendIssueToSprint(*string, *string, *string, *SendIssueToSprintMetadata) error
-	// SendIssueToBottomOfBacklog sends an issue to the bottom of the backlog, and reassigns backlog issue ordinal positions.
+	SendIssueToSprint(*string, *string, *string, *SendIssueToSprintMetadata, *TransitionIssue) error
+	// SendIssueToBottomOfBacklog sends an issue to the bottom of the backlog.
 	SendIssueToBottomOfBacklog(*string, *string) error
-	// SendIssueToTopOfBacklog sends an issue to the top of the backlog, and reassigns backlog issue ordinal positions.
+	// SendIssueToTopOfBacklog sends an issue to the top of the backlog.
 	SendIssueToTopOfBacklog(*string, *string) error
+	// StartProjectBoardSprint starts a project board sprint in storage.
+	StartProjectBoardSprint(*string, *string, *string, *SprintStart) error
+	// TransferIssue moves an issue to another project, under a new project reference, in storage, and returns the project it was moved from.
+	TransferIssue(*string, *IssueTransfer) (string, error)
+	// UnreleaseProjectVersion returns a released project version entity to unreleased in storage.
+	UnreleaseProjectVersion(*string, *string) error
+	// UpdateBoardType updates a board type entity in storage.
+	UpdateBoardType(string, *BoardType) error
+	// UpdateCustomField updates a custom field entity in storage.
+	UpdateCustomField(string, *CustomField) error
 	// UpdateIssue updates an issue entity in storage.
 	UpdateIssue(*string, *string, *Issue) error
 	// UpdateIssueOrdinals ...
-	UpdateIssueOrdinals(*string, *[]IssueOrdinal) error
+	UpdateIssueOrdinals(*string, *[]IssueOrdinal, map[string]TransitionIssue) error
 	// UpdateIssueComment updates an issue comment entity in storage.
 	UpdateIssueComment(*string, *string, *IssueComment) error
+	// UpdateIssueSchedule updates a project issue schedule entity, and when it next runs, in storage.
+	UpdateIssueSchedule(*string, *string, *IssueSchedule, *time.Time) error
 	// UpdateIssueStatus updates an issue status entity in storage.
 	UpdateIssueStatus(string, *IssueStatus) error
+	// UpdateIssueType updates an issue type entity in storage.
+	UpdateIssueType(string, *IssueType) error
+	// UpdateIssueWorklog updates an issue worklog entity in storage.
+	UpdateIssueWorklog(*string, *string, *string, *IssueWorklog) error
+	// UpdateLabel renames a label entity, and the issues using it, in storage.
+	UpdateLabel(string, *Label) error
 	// UpdatePriorityType updates a priority type entity in storage.
 	UpdatePriorityType(string, *PriorityType) error
 	// UpdateProject updates a project entity in storage.
 	UpdateProject(string, *Project) error
+	// UpdateProjectDefaultBoard sets which of a project's boards is its default board in storage.
+	UpdateProjectDefaultBoard(*string, *string) error
+	// UpdateProjectComponent updates a project component entity in storage.
+	UpdateProjectComponent(*string, *string, *Component) error
+	// UpdateProjectIssueTemplate updates a project issue template entity in storage.
+	UpdateProjectIssueTemplate(*string, *string, *IssueTemplate) error
+	// UpdateProjectIssueTypeScheme replaces the issue types allowed by a project entity in storage.
+	UpdateProjectIssueTypeScheme(string, *ProjectIssueTypeScheme) error
+	// UpdateProjectSLAPolicy updates a project SLA policy entity in storage.
+	UpdateProjectSLAPolicy(*string, *string, *SLAPolicy) error
+	// UpdateProjectVersion updates a project version entity in storage.
+	UpdateProjectVersion(*string, *string, *Version) error
+	// UpdateProjectBoard updates a project board entity in storage.
+	UpdateProjectBoard(*string, *string, *Board) error
+	// UpdateProjectBoardColumns replaces the column configuration of a project board in storage.
+	UpdateProjectBoardColumns(*string, *string, *BoardColumns) error
+	// UpdateProjectBoardColumnWIPLimit updates the WIP limits of a project board column in storage.
+	UpdateProjectBoardColumnWIPLimit(*string, *string, int32, *WIPLimit) error
 	// UpdateProjectBoardSprint updates a project board sprint entity in storage.
 	UpdateProjectBoardSprint(*string, *string, *string, *Sprint) error
+	// UpdateProjectBoardSwimlanes updates the swimlane configuration of a project board in storage.
+	UpdateProjectBoardSwimlanes(*string, *string, *Swimlanes) error
+	// UpdateWorkflowTransitions replaces the transitions of a workflow entity in storage.
+	UpdateWorkflowTransitions(int32, []WorkflowTransition) error
 }
 
 type service struct {
@@ -80,6 +206,61 @@ func NewService(r Repository, hub *ws.Hub) Service {
 	return &service{r, hub}
 }
 
+func (s *service) AddProjectBoardColumn(userID *string, projectID *string, boardID *string, c *BoardColumn) error {
+	err := validateBoardColumn(c)
+	if err != nil {
+		return err
+	}
+
+	return s.updateProjectBoardColumns(userID, projectID, boardID, func(bc *BoardColumns) error {
+		return bc.addColumn(c)
+	})
+}
+
+func (s *service) CheckIssueBreaches() error {
+	breaches, err := s.repo.GetIssueBreaches(time.Now())
+	if err != nil {
+		return err
+	}
+
+	for n := range breaches {
+		b := &breaches[n]
+
+		err = s.repo.AddIssueBreach(b)
+		if err != nil {
+			return err
+		}
+
+		eventType := IssueSLABreached
+		if len(b.PolicyID) == 0 {
+			eventType = IssueOverdue
+		}
+
+		payload := IssueBreachedPayload{b.ProjectID, b.IssueID, b.PolicyID, b.Name, b.BreachedAt}
+		err = s.broadcastEvent(eventType, payload)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *service) CompleteProjectBoardSprint(userID *string, projectID *string, boardID *string, sprintID *string, sc *SprintCompletion) error {
+	err := s.repo.CompleteProjectBoardSprint(projectID, boardID, sprintID, sc)
+	if err != nil {
+		return err
+	}
+
+	payload := ProjectBoardSprintUpdatedPayload{*userID, *projectID, *boardID, *sprintID}
+	err = s.broadcastEvent(ProjectBoardSprintCompleted, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (s *service) DecreaseIssueStatus(id string) error {
 	err := s.repo.DecreaseIssueStatus(id)
 	if err != nil {
@@ -116,13 +297,132 @@ func (s *service) IncreasePriorityType(id string) error {
 	return nil
 }
 
+func (s *service) MergeLabels(id string, lm *LabelMerge) error {
+	err := validateLabelMerge(id, lm)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.MergeLabels(id, lm.TargetID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) MoveIssue(userID *string, projectID *string, issueID *string, p *IssuePosition) error {
+	err := validateIssuePosition(*issueID, p)
+	if err != nil {
+		return err
+	}
+
+	var transition *TransitionIssue
+	if p.Column != nil {
+		transition, err = s.getColumnTransition(userID, projectID, issueID, p)
+		if err != nil {
+			return err
+		}
+	}
+
+	move := IssueMove{IssueID: *issueID, SprintID: &p.SprintID}
+	if transition != nil {
+		move.Status = transition.Status
+	}
+
+	breaches, err := s.checkWIPLimits(projectID, []IssueMove{move})
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.MoveIssue(projectID, issueID, p, transition)
+	if err != nil {
+		return err
+	}
+
+	payload := IssueMovedPayload{*userID, *projectID, *issueID, p.SprintID, p.BeforeID, p.AfterID, move.Status}
+	err = s.broadcastEvent(IssueMoved, payload)
+	if err != nil {
+		return err
+	}
+
+	err = s.flagWIPBreaches(userID, projectID, breaches)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) MoveProjectBoardColumn(userID *string, projectID *string, boardID *string, ordinal int32, m *ColumnMove) error {
+	if m == nil {
+		return fmt.Errorf("Column move is nil")
+	}
+
+	return s.updateProjectBoardColumns(userID, projectID, boardID, func(bc *BoardColumns) error {
+		return bc.moveColumn(ordinal, m)
+	})
+}
+
+func (s *service) ReadNotification(userID *string, notificationID *string) error {
+	return s.repo.ReadNotification(userID, notificationID)
+}
+
+func (s *service) ReleaseProjectVersion(userID *string, projectID *string, versionID *string, vr *VersionRelease) error {
+	err := validateVersionRelease(*versionID, vr)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.ReleaseProjectVersion(projectID, versionID, vr)
+	if err != nil {
+		return err
+	}
+
+	payload := ProjectVersionUpdatedPayload{*userID, *projectID, *versionID}
+	err = s.broadcastEvent(ProjectVersionReleased, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) RemoveProjectBoardColumn(userID *string, projectID *string, boardID *string, ordinal int32) error {
+	return s.updateProjectBoardColumns(userID, projectID, boardID, func(bc *BoardColumns) error {
+		return bc.removeColumn(ordinal)
+	})
+}
+
 func (s *service) SendIssueToSprint(userID *string, projectID *string, sprintID *string, issueID *string, d *SendIssueToSprintMetadata) error {
 	// TODO: Validation for SendIssueToSprint
 	// err = validateSendIssueToBottomOfBacklog(projectID, issueID)
 	// if err != nil {
 	// 	return err
 	// }
-	err := s.repo.SendIssueToSprint(projectID, sprintID, issueID, d)
+	var err error
+	var transition *TransitionIssue
+
+	if d != nil && len(d.Status) > 0 {
+		transition, err = s.transitionIssue(userID, projectID, issueID, func(ti *TransitionIssue) {
+			ti.Status = d.Status
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	move := IssueMove{IssueID: *issueID, SprintID: sprintID}
+	if transition != nil {
+		move.Status = transition.Status
+	}
+
+	breaches, err := s.checkWIPLimits(projectID, []IssueMove{move})
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.SendIssueToSprint(projectID, sprintID, issueID, d, transition)
 	if err != nil {
 		return err
 	}
@@ -133,6 +433,11 @@ func (s *service) SendIssueToSprint(userID *string, projectID *string, sprintID
 		return err
 	}
 
+	err = s.flagWIPBreaches(userID, projectID, breaches)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -176,6 +481,100 @@ func (s *service) SendIssueToTopOfBacklog(userID *string, projectID *string, iss
 	return nil
 }
 
+func (s *service) SplitProjectBoardColumn(userID *string, projectID *string, boardID *string, ordinal int32, c *BoardColumn) error {
+	err := validateBoardColumn(c)
+	if err != nil {
+		return err
+	}
+
+	return s.updateProjectBoardColumns(userID, projectID, boardID, func(bc *BoardColumns) error {
+		return bc.splitColumn(ordinal, c)
+	})
+}
+
+func (s *service) StartProjectBoardSprint(userID *string, projectID *string, boardID *string, sprintID *string, ss *SprintStart) error {
+	err := validateSprintStart(ss)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.StartProjectBoardSprint(projectID, boardID, sprintID, ss)
+	if err != nil {
+		return err
+	}
+
+	payload := ProjectBoardSprintUpdatedPayload{*userID, *projectID, *boardID, *sprintID}
+	err = s.broadcastEvent(ProjectBoardSprintStarted, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) UnreleaseProjectVersion(userID *string, projectID *string, versionID *string) error {
+	err := s.repo.UnreleaseProjectVersion(projectID, versionID)
+	if err != nil {
+		return err
+	}
+
+	payload := ProjectVersionUpdatedPayload{*userID, *projectID, *versionID}
+	err = s.broadcastEvent(ProjectVersionUnreleased, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) UpdateBoardType(id string, bt *BoardType) error {
+	err := validateUpdateBoardType(bt)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.UpdateBoardType(id, bt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) UpdateCustomField(id string, cf *CustomField) error {
+	err := validateUpdateCustomField(cf)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.UpdateCustomField(id, cf)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) TransferIssue(userID *string, issueID *string, t *IssueTransfer) error {
+	err := validateIssueTransfer(t)
+	if err != nil {
+		return err
+	}
+
+	fromProjectID, err := s.repo.TransferIssue(issueID, t)
+	if err != nil {
+		return err
+	}
+
+	payload := IssueTransferredPayload{*userID, fromProjectID, t.ProjectID, *issueID}
+	err = s.broadcastEvent(IssueTransferred, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (s *service) UpdateIssue(userID *string, projectID *string, issueID *string, i *Issue) error {
 	// TODO: Validation for UpdateIssue
 	// err = validateUpdateIssue(*i)
@@ -183,7 +582,30 @@ func (s *service) UpdateIssue(userID *string, projectID *string, issueID *string
 	// 	return err
 	// }
 
-	err := s.repo.UpdateIssue(projectID, issueID, i)
+	transition, err := s.transitionIssue(userID, projectID, issueID, func(ti *TransitionIssue) {
+		ti.Type = i.Type
+		ti.Status = i.Status
+		ti.Priority = i.Priority
+		ti.Points = i.Points
+		ti.AssigneeID = i.AssigneeID
+		ti.Labels = i.ApplyLabels(ti.Labels)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Post functions write their changes through the issue update
+	i.Type = transition.Type
+	i.Priority = transition.Priority
+	i.Points = transition.Points
+	i.AssigneeID = transition.AssigneeID
+
+	breaches, err := s.checkWIPLimits(projectID, []IssueMove{{IssueID: *issueID, Status: i.Status, SprintID: &i.SprintID}})
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.UpdateIssue(projectID, issueID, i)
 	if err != nil {
 		return err
 	}
@@ -194,6 +616,11 @@ func (s *service) UpdateIssue(userID *string, projectID *string, issueID *string
 		return err
 	}
 
+	err = s.flagWIPBreaches(userID, projectID, breaches)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -204,7 +631,50 @@ func (s *service) UpdateIssueOrdinals(userID *string, projectID *string, issueOr
 	// 	return err
 	// }
 
-	err := s.repo.UpdateIssueOrdinals(projectID, issueOrdinals)
+	var ids []string
+	for _, io := range *issueOrdinals {
+		ids = append(ids, io.ID)
+	}
+
+	issues, err := s.repo.GetTransitionIssues(ids)
+	if err != nil {
+		return err
+	}
+
+	ts, err := s.repo.GetProjectWorkflowTransitions(projectID)
+	if err != nil {
+		return err
+	}
+
+	transitions := make(map[string]TransitionIssue)
+	moves := []IssueMove{}
+	for _, io := range *issueOrdinals {
+		current, ok := issues[io.ID]
+		if !ok {
+			return fmt.Errorf("Issue %v not found", io.ID)
+		}
+		if current.Status == io.Status {
+			continue
+		}
+
+		target := current
+		target.Status = io.Status
+
+		ctx := TransitionContext{UserID: *userID, FromStatus: current.Status, Current: &current, Issue: &target}
+		if err = applyTransition(ts, &ctx); err != nil {
+			return err
+		}
+
+		transitions[io.ID] = target
+		moves = append(moves, IssueMove{IssueID: io.ID, Status: io.Status})
+	}
+
+	breaches, err := s.checkWIPLimits(projectID, moves)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.UpdateIssueOrdinals(projectID, issueOrdinals, transitions)
 	if err != nil {
 		return err
 	}
@@ -215,6 +685,11 @@ func (s *service) UpdateIssueOrdinals(userID *string, projectID *string, issueOr
 		return err
 	}
 
+	err = s.flagWIPBreaches(userID, projectID, breaches)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -239,6 +714,42 @@ func (s *service) UpdateIssueComment(userID *string, issueID *string, commentID
 	return nil
 }
 
+func (s *service) UpdateIssueSchedule(userID *string, projectID *string, scheduleID *string, is *IssueSchedule) error {
+	err := validateUpdateIssueSchedule(is)
+	if err != nil {
+		return err
+	}
+
+	r, start, err := getIssueScheduleRule(is.Rule, is.Timezone, is.StartAt)
+	if err != nil {
+		return err
+	}
+	is.StartAt = &start
+
+	// Disabled schedules do not run, and runs missed while a schedule was disabled are not caught up on
+	var nextRunAt *time.Time
+	if is.IsEnabled {
+		after := time.Now()
+		if start.After(after) {
+			after = start.Add(-time.Nanosecond)
+		}
+		nextRunAt = getNextRunAt(r, after)
+	}
+
+	err = s.repo.UpdateIssueSchedule(projectID, scheduleID, is, nextRunAt)
+	if err != nil {
+		return err
+	}
+
+	payload := IssueScheduleUpdatedPayload{*userID, *projectID, *scheduleID}
+	err = s.broadcastEvent(IssueScheduleUpdated, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (s *service) UpdateIssueStatus(id string, i *IssueStatus) error {
 	// TODO: Validation for UpdateIssueStatus
 	// err = validateUpdateIssueStatus(*i)
@@ -254,6 +765,54 @@ func (s *service) UpdateIssueStatus(id string, i *IssueStatus) error {
 	return nil
 }
 
+func (s *service) UpdateIssueType(id string, it *IssueType) error {
+	err := validateUpdateIssueType(it)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.UpdateIssueType(id, it)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) UpdateIssueWorklog(userID *string, issueID *string, worklogID *string, w *IssueWorklog) error {
+	err := validateUpdateIssueWorklog(w)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.UpdateIssueWorklog(issueID, worklogID, userID, w)
+	if err != nil {
+		return err
+	}
+
+	payload := IssueWorklogUpdatedPayload{*userID, *issueID, *worklogID}
+	err = s.broadcastEvent(IssueWorklogUpdated, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) UpdateLabel(id string, l *Label) error {
+	err := validateUpdateLabel(l)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.UpdateLabel(id, l)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (s *service) UpdatePriorityType(id string, pt *PriorityType) error {
 	// TODO: Validation for UpdatePriorityType
 	// err = validateUpdatePriorityType(*pt)
@@ -288,6 +847,147 @@ func (s *service) UpdateProject(userID *string, projectID string, p *Project) er
 	return nil
 }
 
+func (s *service) UpdateProjectDefaultBoard(userID *string, projectID *string, boardID *string) error {
+	err := s.repo.UpdateProjectDefaultBoard(projectID, boardID)
+	if err != nil {
+		return err
+	}
+
+	payload := ProjectUpdatedPayload{*userID, *projectID}
+	err = s.broadcastEvent(ProjectUpdated, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) UpdateProjectComponent(userID *string, projectID *string, componentID *string, c *Component) error {
+	err := validateUpdateComponent(c)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.UpdateProjectComponent(projectID, componentID, c)
+	if err != nil {
+		return err
+	}
+
+	payload := ProjectComponentUpdatedPayload{*userID, *projectID, *componentID}
+	err = s.broadcastEvent(ProjectComponentUpdated, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) UpdateProjectSLAPolicy(userID *string, projectID *string, policyID *string, p *SLAPolicy) error {
+	err := validateUpdateSLAPolicy(p)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.UpdateProjectSLAPolicy(projectID, policyID, p)
+	if err != nil {
+		return err
+	}
+
+	payload := ProjectSLAPolicyUpdatedPayload{*userID, *projectID, *policyID}
+	err = s.broadcastEvent(ProjectSLAPolicyUpdated, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) UpdateProjectIssueTemplate(userID *string, projectID *string, templateID *string, t *IssueTemplate) error {
+	err := validateUpdateIssueTemplate(t)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.UpdateProjectIssueTemplate(projectID, templateID, t)
+	if err != nil {
+		return err
+	}
+
+	payload := ProjectIssueTemplateUpdatedPayload{*userID, *projectID, *templateID}
+	err = s.broadcastEvent(ProjectIssueTemplateUpdated, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) UpdateProjectIssueTypeScheme(userID *string, projectID string, its *ProjectIssueTypeScheme) error {
+	err := s.repo.UpdateProjectIssueTypeScheme(projectID, its)
+	if err != nil {
+		return err
+	}
+
+	payload := ProjectUpdatedPayload{*userID, projectID}
+	err = s.broadcastEvent(ProjectUpdated, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) UpdateProjectBoard(userID *string, projectID *string, boardID *string, b *Board) error {
+	err := validateUpdateBoard(b)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.UpdateProjectBoard(projectID, boardID, b)
+	if err != nil {
+		return err
+	}
+
+	payload := ProjectBoardUpdatedPayload{*userID, *projectID, *boardID}
+	err = s.broadcastEvent(ProjectBoardUpdated, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) UpdateProjectBoardColumn(userID *string, projectID *string, boardID *string, ordinal int32, c *BoardColumn) error {
+	err := validateBoardColumn(c)
+	if err != nil {
+		return err
+	}
+
+	return s.updateProjectBoardColumns(userID, projectID, boardID, func(bc *BoardColumns) error {
+		return bc.updateColumn(ordinal, c)
+	})
+}
+
+func (s *service) UpdateProjectBoardColumnWIPLimit(userID *string, projectID *string, boardID *string, ordinal int32, l *WIPLimit) error {
+	err := validateWIPLimit(l)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.UpdateProjectBoardColumnWIPLimit(projectID, boardID, ordinal, l)
+	if err != nil {
+		return err
+	}
+
+	payload := ProjectBoardColumnUpdatedPayload{*userID, *projectID, *boardID}
+	err = s.broadcastEvent(ProjectBoardColumnUpdated, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (s *service) UpdateProjectBoardSprint(userID *string, projectID *string, boardID *string, sprintID *string, sprint *Sprint) error {
 	// TODO: Validation for UpdateProjectBoardSprint
 	// err = validateUpdateProject(*p)
@@ -308,6 +1008,100 @@ func (s *service) UpdateProjectBoardSprint(userID *string, projectID *string, bo
 	return nil
 }
 
+func (s *service) UpdateProjectBoardStatusMapping(userID *string, projectID *string, boardID *string, statusID string, m *StatusMapping) error {
+	err := validateStatusMapping(m)
+	if err != nil {
+		return err
+	}
+
+	return s.updateProjectBoardColumns(userID, projectID, boardID, func(bc *BoardColumns) error {
+		return bc.mapStatus(statusID, m)
+	})
+}
+
+func (s *service) UpdateProjectBoardSwimlanes(userID *string, projectID *string, boardID *string, sl *Swimlanes) error {
+	err := validateSwimlanes(sl)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.UpdateProjectBoardSwimlanes(projectID, boardID, sl)
+	if err != nil {
+		return err
+	}
+
+	payload := ProjectBoardUpdatedPayload{*userID, *projectID, *boardID}
+	err = s.broadcastEvent(ProjectBoardUpdated, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) UpdateProjectVersion(userID *string, projectID *string, versionID *string, v *Version) error {
+	err := validateUpdateVersion(v)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.UpdateProjectVersion(projectID, versionID, v)
+	if err != nil {
+		return err
+	}
+
+	payload := ProjectVersionUpdatedPayload{*userID, *projectID, *versionID}
+	err = s.broadcastEvent(ProjectVersionUpdated, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) UpdateWorkflowTransitions(workflowID int32, ts []WorkflowTransition) error {
+	err := validateWorkflowTransitions(ts)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.UpdateWorkflowTransitions(workflowID, ts)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// transitionIssue applies the target changes to the current state of an issue and runs the project's
+// workflow transition rules against the result.
+func (s *service) transitionIssue(userID *string, projectID *string, issueID *string, change func(*TransitionIssue)) (*TransitionIssue, error) {
+	issues, err := s.repo.GetTransitionIssues([]string{*issueID})
+	if err != nil {
+		return nil, err
+	}
+
+	current, ok := issues[*issueID]
+	if !ok {
+		return nil, fmt.Errorf("Issue %v not found", *issueID)
+	}
+
+	ts, err := s.repo.GetProjectWorkflowTransitions(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	target := current
+	change(&target)
+
+	ctx := TransitionContext{UserID: *userID, FromStatus: current.Status, Current: &current, Issue: &target}
+	if err = applyTransition(ts, &ctx); err != nil {
+		return nil, err
+	}
+
+	return &target, nil
+}
+
 func (s *service) broadcastEvent(eventType EventType, payload interface{}) error {
 	m := Message{Type: eventType, Payload: payload}
 	b, err := json.Marshal(m)
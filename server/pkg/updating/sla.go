@@ -0,0 +1,80 @@
+package updating
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/njehyde/issue-tracker/libraries/slog"
+)
+
+// SLAPolicy defines the updating form of a project SLA policy entity. Its clock starts when an issue first enters a
+// status of one of the start categories, is paused in the pause categories, and stops in the stop categories.
+type SLAPolicy struct {
+	Name            string      `json:"name"`
+	StartCategories []string    `json:"startCategories"`
+	PauseCategories []string    `json:"pauseCategories,omitempty"`
+	StopCategories  []string    `json:"stopCategories"`
+	Targets         []SLATarget `json:"targets"`
+}
+
+// SLATarget defines the updating form of the time an SLA policy allows issues of a priority. Durations are in seconds.
+type SLATarget struct {
+	Priority string `json:"priority"`
+	Duration int64  `json:"duration"`
+}
+
+func validateUpdateSLAPolicy(p *SLAPolicy) error {
+	if p == nil {
+		return fmt.Errorf("SLA policy is nil")
+	}
+	if len(p.Name) == 0 {
+		return fmt.Errorf("'name' is empty")
+	}
+	if len(p.StartCategories) == 0 {
+		return fmt.Errorf("'startCategories' is empty")
+	}
+	if len(p.StopCategories) == 0 {
+		return fmt.Errorf("'stopCategories' is empty")
+	}
+	if len(p.Targets) == 0 {
+		return fmt.Errorf("'targets' is empty")
+	}
+
+	priorities := make(map[string]bool)
+	for _, t := range p.Targets {
+		if len(t.Priority) == 0 {
+			return fmt.Errorf("'priority' is empty")
+		}
+		if t.Duration <= 0 {
+			return fmt.Errorf("'duration' must be greater than zero")
+		}
+		if priorities[t.Priority] {
+			return fmt.Errorf("Priority %v has more than one target", t.Priority)
+		}
+		priorities[t.Priority] = true
+	}
+
+	return nil
+}
+
+// IssueBreach defines an issue that has breached the target of an SLA policy, or passed its due date, in which case
+// it has no policy id.
+type IssueBreach struct {
+	ProjectID  string
+	IssueID    string
+	PolicyID   string
+	Name       string
+	BreachedAt time.Time
+}
+
+// WatchIssueBreaches checks for issue breaches every interval, until the process exits.
+func WatchIssueBreaches(s Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := s.CheckIssueBreaches(); err != nil {
+			slog.Errorf("Checking issue breaches failed: %v", err)
+		}
+	}
+}
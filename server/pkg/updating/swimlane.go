@@ -0,0 +1,69 @@
+package updating
+
+import "fmt"
+
+// Swimlane types, which set how the issues of a board are grouped into horizontal lanes.
+const (
+	SwimlaneTypeNone      = "NONE"
+	SwimlaneTypeAssignee  = "ASSIGNEE"
+	SwimlaneTypeEpic      = "EPIC"
+	SwimlaneTypePriority  = "PRIORITY"
+	SwimlaneTypeIssueType = "ISSUE_TYPE"
+	SwimlaneTypeQuery     = "QUERY"
+)
+
+// Swimlanes defines the updating form of the swimlane configuration of a board. Queries are only used by the QUERY
+// swimlane type, where each issue is placed in the lane of the first query that it matches.
+type Swimlanes struct {
+	Type    string          `json:"type"`
+	Queries []SwimlaneQuery `json:"queries,omitempty"`
+}
+
+// SwimlaneQuery defines the updating form of a named swimlane query, whose issues match all of its filters.
+type SwimlaneQuery struct {
+	Name    string        `json:"name"`
+	Filters []BoardFilter `json:"filters"`
+}
+
+func validateSwimlanes(sl *Swimlanes) error {
+	if sl == nil {
+		return fmt.Errorf("Swimlanes is nil")
+	}
+
+	switch sl.Type {
+	case SwimlaneTypeNone, SwimlaneTypeAssignee, SwimlaneTypeEpic, SwimlaneTypePriority, SwimlaneTypeIssueType:
+		if len(sl.Queries) > 0 {
+			return fmt.Errorf("'queries' must be empty for %v swimlanes", sl.Type)
+		}
+	case SwimlaneTypeQuery:
+		if len(sl.Queries) == 0 {
+			return fmt.Errorf("'queries' is empty")
+		}
+	case "":
+		return fmt.Errorf("'type' is empty")
+	default:
+		return fmt.Errorf("Unknown swimlane type %v", sl.Type)
+	}
+
+	names := make(map[string]bool)
+	for _, q := range sl.Queries {
+		if len(q.Name) == 0 {
+			return fmt.Errorf("Query 'name' is empty")
+		}
+		if names[q.Name] {
+			return fmt.Errorf("Query %v is duplicated", q.Name)
+		}
+		names[q.Name] = true
+
+		if len(q.Filters) == 0 {
+			return fmt.Errorf("Query %v 'filters' is empty", q.Name)
+		}
+		for _, f := range q.Filters {
+			if len(f.Field) == 0 {
+				return fmt.Errorf("Filter 'field' is empty")
+			}
+		}
+	}
+
+	return nil
+}
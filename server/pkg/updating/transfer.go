@@ -0,0 +1,22 @@
+package updating
+
+import "fmt"
+
+// IssueTransfer defines the updating form of a move of an issue to another project. The issue keeps its issue type
+// and status, unless others are given for projects that do not have them.
+type IssueTransfer struct {
+	ProjectID string `json:"projectId"`
+	Type      string `json:"type,omitempty"`
+	Status    string `json:"status,omitempty"`
+}
+
+func validateIssueTransfer(t *IssueTransfer) error {
+	if t == nil {
+		return fmt.Errorf("Issue transfer is nil")
+	}
+	if len(t.ProjectID) == 0 {
+		return fmt.Errorf("'projectId' is empty")
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package updating
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TransitionIssue defines the state of an issue that transition rules are evaluated against.
+type TransitionIssue struct {
+	ID         string
+	ProjectID  string
+	Type       string
+	Status     string
+	Priority   string
+	Points     int32
+	ReporterID string
+	AssigneeID string
+	Labels     []string
+}
+
+// TransitionContext holds the acting user, the status being left, and the current and target states of the issue.
+// Conditions about who may act check the current state, so that a change made by the same update, such as taking the
+// issue's assignment, cannot satisfy them.
+type TransitionContext struct {
+	UserID     string
+	FromStatus string
+	Current    *TransitionIssue
+	Issue      *TransitionIssue
+}
+
+// Condition decides whether a transition is available to the acting user.
+type Condition func(*TransitionContext, map[string]string) error
+
+// Validator checks the target state of an issue before a transition is performed.
+type Validator func(*TransitionContext, map[string]string) error
+
+// PostFunction changes the target state of an issue as part of a transition. Its changes are
+// written by the same update as the status change.
+type PostFunction func(*TransitionContext, map[string]string) error
+
+var conditions = map[string]Condition{
+	"ASSIGNEE_ONLY": func(ctx *TransitionContext, params map[string]string) error {
+		if ctx.Current.AssigneeID != ctx.UserID {
+			return fmt.Errorf("Only the assignee can move issue to %v", ctx.Issue.Status)
+		}
+		return nil
+	},
+	"REPORTER_ONLY": func(ctx *TransitionContext, params map[string]string) error {
+		if ctx.Current.ReporterID != ctx.UserID {
+			return fmt.Errorf("Only the reporter can move issue to %v", ctx.Issue.Status)
+		}
+		return nil
+	},
+	"USER_IN_LIST": func(ctx *TransitionContext, params map[string]string) error {
+		for _, userID := range strings.Split(params["userIds"], ",") {
+			if strings.TrimSpace(userID) == ctx.UserID {
+				return nil
+			}
+		}
+		return fmt.Errorf("User %v cannot move issue to %v", ctx.UserID, ctx.Issue.Status)
+	},
+}
+
+var validators = map[string]Validator{
+	"POINTS_REQUIRED": func(ctx *TransitionContext, params map[string]string) error {
+		if ctx.Issue.Points <= 0 {
+			return fmt.Errorf("Points are required before moving issue to %v", ctx.Issue.Status)
+		}
+		return nil
+	},
+	"ASSIGNEE_REQUIRED": func(ctx *TransitionContext, params map[string]string) error {
+		if len(ctx.Issue.AssigneeID) == 0 {
+			return fmt.Errorf("An assignee is required before moving issue to %v", ctx.Issue.Status)
+		}
+		return nil
+	},
+	"LABEL_REQUIRED": func(ctx *TransitionContext, params map[string]string) error {
+		for _, l := range ctx.Issue.Labels {
+			if len(params["label"]) == 0 || l == params["label"] {
+				return nil
+			}
+		}
+		return fmt.Errorf("A label is required before moving issue to %v", ctx.Issue.Status)
+	},
+}
+
+var postFunctions = map[string]PostFunction{
+	"CLEAR_ASSIGNEE": func(ctx *TransitionContext, params map[string]string) error {
+		ctx.Issue.AssigneeID = ""
+		return nil
+	},
+	"ASSIGN_TO_CURRENT_USER": func(ctx *TransitionContext, params map[string]string) error {
+		ctx.Issue.AssigneeID = ctx.UserID
+		return nil
+	},
+	"ASSIGN_TO_REPORTER": func(ctx *TransitionContext, params map[string]string) error {
+		ctx.Issue.AssigneeID = ctx.Issue.ReporterID
+		return nil
+	},
+	"SET_PRIORITY": func(ctx *TransitionContext, params map[string]string) error {
+		if len(params["priority"]) == 0 {
+			return fmt.Errorf("SET_PRIORITY requires a 'priority' param")
+		}
+		ctx.Issue.Priority = params["priority"]
+		return nil
+	},
+}
+
+// RegisterCondition adds a named condition to the transition rule engine. It should be called during initialisation.
+func RegisterCondition(name string, c Condition) {
+	conditions[name] = c
+}
+
+// RegisterValidator adds a named validator to the transition rule engine. It should be called during initialisation.
+func RegisterValidator(name string, v Validator) {
+	validators[name] = v
+}
+
+// RegisterPostFunction adds a named post function to the transition rule engine. It should be called during initialisation.
+func RegisterPostFunction(name string, pf PostFunction) {
+	postFunctions[name] = pf
+}
+
+func (t *WorkflowTransition) matches(from string, to string) bool {
+	if t.ToStatusID != to {
+		return false
+	}
+	if len(t.FromStatusIDs) == 0 {
+		return true
+	}
+	for _, id := range t.FromStatusIDs {
+		if id == from {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *WorkflowTransition) check(ctx *TransitionContext) error {
+	for _, r := range t.Conditions {
+		condition, ok := conditions[r.Type]
+		if !ok {
+			return fmt.Errorf("Unknown condition %v for transition %v", r.Type, t.Name)
+		}
+		if err := condition(ctx, r.Params); err != nil {
+			return err
+		}
+	}
+	for _, r := range t.Validators {
+		validator, ok := validators[r.Type]
+		if !ok {
+			return fmt.Errorf("Unknown validator %v for transition %v", r.Type, t.Name)
+		}
+		if err := validator(ctx, r.Params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyTransition evaluates the rules of the first workflow transition that allows the issue to move from
+// ctx.FromStatus to ctx.Issue.Status, and then runs its post functions against ctx.Issue. Workflows without
+// transitions allow any status change.
+func applyTransition(ts []WorkflowTransition, ctx *TransitionContext) error {
+	if ctx.FromStatus == ctx.Issue.Status || len(ts) == 0 {
+		return nil
+	}
+
+	var err error
+	for i := range ts {
+		t := &ts[i]
+		if !t.matches(ctx.FromStatus, ctx.Issue.Status) {
+			continue
+		}
+		if err = t.check(ctx); err != nil {
+			continue
+		}
+		// Every post function is looked up before any of them changes the issue
+		pfs := []PostFunction{}
+		for _, r := range t.PostFunctions {
+			pf, ok := postFunctions[r.Type]
+			if !ok {
+				return fmt.Errorf("Unknown post function %v for transition %v", r.Type, t.Name)
+			}
+			pfs = append(pfs, pf)
+		}
+		for n, pf := range pfs {
+			if err = pf(ctx, t.PostFunctions[n].Params); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return fmt.Errorf("Transition from %v to %v is not allowed", ctx.FromStatus, ctx.Issue.Status)
+}
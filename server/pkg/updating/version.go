@@ -0,0 +1,47 @@
+package updating
+
+import (
+	"fmt"
+	"time"
+)
+
+// Version defines the updating form of a project version entity.
+type Version struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	StartAt     *time.Time `json:"startAt,omitempty"`
+	ReleaseAt   *time.Time `json:"releaseAt,omitempty"`
+	IsArchived  bool       `json:"archived"`
+}
+
+// VersionRelease defines the updating version release request. Where MoveUnfinishedTo is set, issues of the
+// released version that are not done are moved to that version.
+type VersionRelease struct {
+	ReleasedAt       *time.Time `json:"releasedAt,omitempty"`
+	MoveUnfinishedTo string     `json:"moveUnfinishedTo,omitempty"`
+}
+
+func validateUpdateVersion(v *Version) error {
+	if v == nil {
+		return fmt.Errorf("Version is nil")
+	}
+	if len(v.Name) == 0 {
+		return fmt.Errorf("'name' is empty")
+	}
+	if v.StartAt != nil && v.ReleaseAt != nil && v.ReleaseAt.Before(*v.StartAt) {
+		return fmt.Errorf("'releaseAt' is before 'startAt'")
+	}
+
+	return nil
+}
+
+func validateVersionRelease(versionID string, vr *VersionRelease) error {
+	if vr == nil {
+		return fmt.Errorf("Version release is nil")
+	}
+	if vr.MoveUnfinishedTo == versionID {
+		return fmt.Errorf("Unfinished issues cannot be moved to the version being released")
+	}
+
+	return nil
+}
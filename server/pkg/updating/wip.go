@@ -0,0 +1,56 @@
+package updating
+
+import "fmt"
+
+// WIP limit policies, applied when a move would take a board column over its maximum.
+const (
+	WIPPolicyFlag   = "FLAG"
+	WIPPolicyReject = "REJECT"
+)
+
+// IssueMove defines a change to the status, and optionally the sprint, of an issue. An empty status leaves the
+// status unchanged, and a nil sprint id leaves the sprint unchanged.
+type IssueMove struct {
+	IssueID  string
+	Status   string
+	SprintID *string
+}
+
+// WIPBreach defines a board column that a set of issue moves would take over its maximum WIP limit.
+type WIPBreach struct {
+	BoardID string
+	Column  string
+	Count   int64
+	MaxWIP  int32
+	Policy  string
+}
+
+// checkWIPLimits returns the WIP limit breaches that a set of issue moves would cause and that are only flagged, or
+// an error where a breached column rejects them.
+func (s *service) checkWIPLimits(projectID *string, moves []IssueMove) ([]WIPBreach, error) {
+	breaches, err := s.repo.GetWIPBreaches(projectID, moves)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, b := range breaches {
+		if b.Policy == WIPPolicyReject {
+			return nil, fmt.Errorf("Column %v is limited to %v issues", b.Column, b.MaxWIP)
+		}
+	}
+
+	return breaches, nil
+}
+
+// flagWIPBreaches broadcasts an event for each flagged WIP limit breach.
+func (s *service) flagWIPBreaches(userID *string, projectID *string, breaches []WIPBreach) error {
+	for _, b := range breaches {
+		payload := ProjectBoardColumnWIPExceededPayload{*userID, *projectID, b.BoardID, b.Column, b.Count, b.MaxWIP}
+		err := s.broadcastEvent(ProjectBoardColumnWIPExceeded, payload)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
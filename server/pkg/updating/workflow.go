@@ -0,0 +1,52 @@
+package updating
+
+import "fmt"
+
+// TransitionRule defines the updating form of a rule attached to a workflow transition Value Object.
+type TransitionRule struct {
+	Type   string            `json:"type"`
+	Params map[string]string `json:"params,omitempty"`
+}
+
+// WorkflowTransition defines the updating form of a workflow transition Value Object.
+type WorkflowTransition struct {
+	Name          string           `json:"name"`
+	FromStatusIDs []string         `json:"fromStatusIds,omitempty"`
+	ToStatusID    string           `json:"toStatusId"`
+	Conditions    []TransitionRule `json:"conditions,omitempty"`
+	Validators    []TransitionRule `json:"validators,omitempty"`
+	PostFunctions []TransitionRule `json:"postFunctions,omitempty"`
+}
+
+// WorkflowTransitions defines the updating workflow transitions request.
+type WorkflowTransitions struct {
+	Transitions []WorkflowTransition `json:"transitions"`
+}
+
+func validateWorkflowTransitions(ts []WorkflowTransition) error {
+	for _, t := range ts {
+		if len(t.Name) == 0 {
+			return fmt.Errorf("'name' is empty")
+		}
+		if len(t.ToStatusID) == 0 {
+			return fmt.Errorf("'toStatusId' is empty for transition %v", t.Name)
+		}
+		for _, r := range t.Conditions {
+			if _, ok := conditions[r.Type]; !ok {
+				return fmt.Errorf("Unknown condition %v for transition %v", r.Type, t.Name)
+			}
+		}
+		for _, r := range t.Validators {
+			if _, ok := validators[r.Type]; !ok {
+				return fmt.Errorf("Unknown validator %v for transition %v", r.Type, t.Name)
+			}
+		}
+		for _, r := range t.PostFunctions {
+			if _, ok := postFunctions[r.Type]; !ok {
+				return fmt.Errorf("Unknown post function %v for transition %v", r.Type, t.Name)
+			}
+		}
+	}
+
+	return nil
+}